@@ -2,82 +2,222 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
 	"time"
+	_ "time/tzdata" // TZ_NAMEに任意のタイムゾーンを指定できるよう、実行環境に依存せずタイムゾーンデータを埋め込む
 
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
 
+	"github.com/k-negishi/google-calendar-line-notifier/internal/app"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
 	"github.com/k-negishi/google-calendar-line-notifier/internal/config"
-	"github.com/k-negishi/google-calendar-line-notifier/internal/gateway"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/metrics"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/payload"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/runid"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/schedule"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/timing"
 	"github.com/k-negishi/google-calendar-line-notifier/internal/usecase"
 )
 
-// LambdaEvent Lambda実行時のイベント構造体
-type LambdaEvent struct {
-	// EventBridge Schedulerからの実行なので特に使用しない
-}
-
 // LambdaResponse Lambda実行結果のレスポンス
 type LambdaResponse struct {
 	StatusCode int    `json:"statusCode"`
 	Message    string `json:"message"`
+	RunID      string `json:"runId"`
+	// SkipReason 通知を送信しなかった理由（予定なし・不在など、送信した場合は省略）
+	SkipReason string `json:"skipReason,omitempty"`
+	// TimingsMs 処理段階ごとの所要時間（ミリ秒）
+	TimingsMs map[string]int64 `json:"timingsMs,omitempty"`
 }
 
 // handler Lambda関数のメインハンドラー
-func handler(ctx context.Context, _ LambdaEvent) (LambdaResponse, error) {
+// rawEventはpayload.Eventの形式（スキーマはinternal/payload/schema.json）
+func handler(ctx context.Context, rawEvent json.RawMessage) (resp LambdaResponse, err error) {
+	// 実行IDをログ・送信ログ・レスポンスに残し、配信を追跡できるようにする
+	id := newRunID(ctx)
+	ctx = runid.WithRunID(ctx, id)
+	log.SetPrefix(fmt.Sprintf("[%s] ", id))
+
+	// 処理段階ごとの所要時間を計測し、レスポンスとログに残す
+	recorder := timing.NewRecorder()
+	ctx = timing.WithRecorder(ctx, recorder)
+	dryRun := false
+	defer func() {
+		resp.TimingsMs = recorder.Milliseconds()
+		log.Printf("timings: %s", recorder)
+		emitRunMetric(ctx, id, outcome(resp, err, dryRun), resp.SkipReason)
+	}()
+
+	// ペイロードを検証
+	event, err := payload.Parse(rawEvent)
+	if err != nil {
+		return LambdaResponse{
+			StatusCode: 400,
+			Message:    "ペイロードエラー",
+			RunID:      id,
+		}, err
+	}
+
 	// 設定を読み込み
+	stopConfig := recorder.Start("config_load")
 	cfg, err := config.Load()
+	stopConfig()
 	if err != nil {
 		return LambdaResponse{
 			StatusCode: 500,
 			Message:    "設定読み込みエラー",
+			RunID:      id,
 		}, err
 	}
+	// 日付の計算・表示は設定されたタイムゾーンで行う（todayOverrideの解釈を含む）
+	dates.SetLocation(cfg.Timezone)
 
-	// 依存性の注入: Google Calendarリポジトリを初期化
-	calendarRepo, err := gateway.NewGoogleCalendarRepository([]byte(cfg.GoogleCredentials), cfg.CalendarID)
+	// スケジュールが指定されていれば、そのスケジュールの表示テンプレート（フラグ）を適用する
+	if event.Schedule != "" {
+		s, err := findSchedule(cfg.Schedules, event.Schedule)
+		if err != nil {
+			return LambdaResponse{
+				StatusCode: 400,
+				Message:    "スケジュールエラー",
+				RunID:      id,
+			}, err
+		}
+		cfg.FeatureFlags = append(cfg.FeatureFlags, s.Flags...)
+	}
+	// ペイロードで指定されたフラグは環境変数・スケジュールの値より優先する
+	cfg.FeatureFlags = append(cfg.FeatureFlags, event.Flags...)
+	if event.IsDryRun() {
+		cfg.DryRun = true
+	}
+	dryRun = cfg.DryRun
+
+	// 日付が指定されていれば、その日を今日として実行する
+	var clk clock.Clock = clock.RealClock{}
+	if today, ok := event.Today(); ok {
+		clk = clock.OnDate(clk, today)
+		log.Printf("今日の日付を %s に差し替えて実行します (dryRun=%t)", event.TodayOverride, cfg.DryRun)
+	}
+
+	// 依存関係を組み立て
+	stopInit := recorder.Start("init")
+	application, err := app.NewWithClock(cfg, clk)
+	stopInit()
 	if err != nil {
 		return LambdaResponse{
 			StatusCode: 500,
 			Message:    "Google Calendar初期化エラー",
+			RunID:      id,
 		}, err
 	}
 
-	// 依存性の注入: LINE通知クライアントを初期化
-	notifier := gateway.NewLINENotifier(cfg.LineChannelAccessToken, cfg.LineUserID)
-
-	// ユースケースを生成
-	uc := usecase.NewNotifyScheduleUseCase(calendarRepo, notifier)
-
-	// JST固定で現在時刻を取得
-	jst, _ := time.LoadLocation("Asia/Tokyo")
-	now := time.Now().In(jst)
-
-	// JST固定で今日と明日の日付を確実に計算
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, jst)
-	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, jst)
+	// リマインダーのスケジュールから呼び出された場合は、予定通知の代わりにリマインダーを送信する
+	if event.Reminder != nil {
+		if err := application.Remind(ctx, *event.Reminder); err != nil {
+			return LambdaResponse{
+				StatusCode: 500,
+				Message:    "リマインダー送信エラー",
+				RunID:      id,
+			}, err
+		}
+		message := "リマインダー送信完了"
+		if cfg.DryRun {
+			message = "ドライラン完了（LINE送信なし）"
+		}
+		return LambdaResponse{
+			StatusCode: 200,
+			Message:    message,
+			RunID:      id,
+		}, nil
+	}
 
 	// ユースケースを実行
-	skipped, err := uc.Execute(ctx, today, tomorrow)
+	skipReason, err := application.Run(ctx)
 	if err != nil {
 		return LambdaResponse{
 			StatusCode: 500,
 			Message:    "通知処理エラー",
+			RunID:      id,
 		}, err
 	}
 
-	if skipped {
+	if skipReason != "" {
+		message := "予定なしのため通知スキップ"
+		if skipReason == usecase.SkipReasonOutOfOffice {
+			message = "不在のため通知スキップ"
+		}
 		return LambdaResponse{
 			StatusCode: 200,
-			Message:    "予定なしのため通知スキップ",
+			Message:    message,
+			RunID:      id,
+			SkipReason: string(skipReason),
+		}, nil
+	}
+
+	if cfg.DryRun {
+		return LambdaResponse{
+			StatusCode: 200,
+			Message:    "ドライラン完了（LINE送信なし）",
+			RunID:      id,
 		}, nil
 	}
 
 	return LambdaResponse{
 		StatusCode: 200,
 		Message:    "通知送信完了",
+		RunID:      id,
 	}, nil
 }
 
+// findSchedule 設定されたスケジュールから名前で探す
+func findSchedule(definitions, name string) (schedule.Schedule, error) {
+	schedules, err := schedule.Parse(definitions)
+	if err != nil {
+		return schedule.Schedule{}, err
+	}
+	s, ok := schedule.Find(schedules, name)
+	if !ok {
+		return schedule.Schedule{}, fmt.Errorf("スケジュール %s はSCHEDULESに定義されていません", name)
+	}
+	return s, nil
+}
+
+// outcome レスポンスとエラーからメトリクスの実行結果を判定
+func outcome(resp LambdaResponse, err error, dryRun bool) string {
+	switch {
+	case err != nil:
+		return metrics.OutcomeFailed
+	case resp.SkipReason != "":
+		return metrics.OutcomeSkipped
+	case dryRun:
+		return metrics.OutcomeDryRun
+	default:
+		return metrics.OutcomeSent
+	}
+}
+
+// emitRunMetric Lambda上で実行している場合、実行結果のメトリクスをEMFで出力する
+func emitRunMetric(ctx context.Context, id, outcome, skipReason string) {
+	if _, ok := lambdacontext.FromContext(ctx); !ok {
+		return
+	}
+	if err := metrics.EmitRun(os.Stdout, time.Now(), id, outcome, skipReason); err != nil {
+		log.Printf("Warning: メトリクスの出力に失敗しました: %v", err)
+	}
+}
+
+// newRunID 実行IDを作成（Lambda上ではリクエストIDを使用する）
+func newRunID(ctx context.Context) string {
+	if lc, ok := lambdacontext.FromContext(ctx); ok && lc.AwsRequestID != "" {
+		return lc.AwsRequestID
+	}
+	return runid.New()
+}
+
 func main() {
 	lambda.Start(handler)
 }
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/fakes"
+)
+
+// setupE2E 偽のLINE・Google Calendarサーバーを起動し、ハンドラーがそれらを参照するよう環境変数を設定する
+func setupE2E(t *testing.T) (*fakes.CalendarServer, *fakes.LINEServer) {
+	t.Helper()
+
+	calendarServer := fakes.NewCalendarServer(t)
+	lineServer := fakes.NewLINEServer(t)
+
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "")
+	t.Setenv("GOOGLE_CREDENTIALS", string(calendarServer.ServiceAccountJSON(t)))
+	t.Setenv("CALENDAR_ID", "e2e-calendar")
+	t.Setenv("GOOGLE_CALENDAR_ENDPOINT", calendarServer.Endpoint())
+	t.Setenv("LINE_CHANNEL_ACCESS_TOKEN", "e2e-token")
+	t.Setenv("LINE_USER_ID", "e2e-user")
+	t.Setenv("LINE_API_BASE_URL", lineServer.URL)
+
+	return calendarServer, lineServer
+}
+
+// todayAt 今日(JST)の指定時刻をRFC3339形式で返す
+func todayAt(hour, minute int) string {
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Now().In(jst)
+	return time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, jst).Format(time.RFC3339)
+}
+
+func TestHandler_E2E_SendsNotification(t *testing.T) {
+	calendarServer, lineServer := setupE2E(t)
+	calendarServer.AddEvents("e2e-calendar", &calendar.Event{
+		Id:       "e2e-1",
+		Summary:  "E2E朝会",
+		Location: "会議室A",
+		Start:    &calendar.EventDateTime{DateTime: todayAt(9, 0)},
+		End:      &calendar.EventDateTime{DateTime: todayAt(9, 30)},
+	})
+
+	resp, err := handler(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "通知送信完了", resp.Message)
+	assert.NotEmpty(t, resp.RunID)
+	for _, stage := range []string{"config_load", "init", "fetch_today", "fetch_tomorrow", "build", "send"} {
+		assert.Contains(t, resp.TimingsMs, stage)
+	}
+
+	requests := lineServer.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/v2/bot/message/push", requests[0].Path)
+	assert.Equal(t, "Bearer e2e-token", requests[0].Authorization)
+	assert.Equal(t, "e2e-user", requests[0].To)
+	require.Len(t, requests[0].Messages, 1)
+	assert.Contains(t, requests[0].Messages[0].Text, "09:00〜09:30 E2E朝会")
+	assert.Contains(t, requests[0].Messages[0].Text, "📍 会議室A")
+	assert.Equal(t, 2, calendarServer.ListCalls())
+}
+
+func TestHandler_E2E_NoEventsSkipped(t *testing.T) {
+	_, lineServer := setupE2E(t)
+
+	resp, err := handler(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "予定なしのため通知スキップ", resp.Message)
+	assert.Equal(t, "empty", resp.SkipReason)
+	assert.Empty(t, lineServer.Requests())
+}
+
+func TestOutcome(t *testing.T) {
+	assert.Equal(t, "failed", outcome(LambdaResponse{StatusCode: 500}, errors.New("boom"), false))
+	assert.Equal(t, "skipped", outcome(LambdaResponse{SkipReason: "out_of_office"}, nil, false))
+	assert.Equal(t, "dry_run", outcome(LambdaResponse{}, nil, true))
+	assert.Equal(t, "sent", outcome(LambdaResponse{}, nil, false))
+}
+
+func TestHandler_E2E_CalendarError(t *testing.T) {
+	calendarServer, lineServer := setupE2E(t)
+	calendarServer.FailWith(http.StatusServiceUnavailable)
+
+	resp, err := handler(context.Background(), nil)
+	assert.Error(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+	assert.Equal(t, "通知処理エラー", resp.Message)
+	assert.Empty(t, lineServer.Requests())
+}
+
+func TestHandler_E2E_LINEError(t *testing.T) {
+	calendarServer, lineServer := setupE2E(t)
+	calendarServer.AddEvents("e2e-calendar", &calendar.Event{
+		Id:      "e2e-1",
+		Summary: "E2E朝会",
+		Start:   &calendar.EventDateTime{DateTime: todayAt(9, 0)},
+		End:     &calendar.EventDateTime{DateTime: todayAt(9, 30)},
+	})
+	lineServer.FailWith(http.StatusTooManyRequests, `{"message":"The API rate limit has been exceeded. Try again later."}`)
+
+	resp, err := handler(context.Background(), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rate limit")
+	assert.Equal(t, 500, resp.StatusCode)
+}
+
+func TestHandler_InvalidPayload(t *testing.T) {
+	_, lineServer := setupE2E(t)
+
+	resp, err := handler(context.Background(), json.RawMessage(`{"mode":"preview"}`))
+	assert.ErrorContains(t, err, `未知のフィールド "mode"`)
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Equal(t, "ペイロードエラー", resp.Message)
+	assert.Empty(t, lineServer.Requests())
+}
+
+func TestHandler_E2E_Reminder(t *testing.T) {
+	calendarServer, lineServer := setupE2E(t)
+
+	// リマインダーのスケジュールが渡すペイロードでは、予定を取得せずにリマインダーだけを送る
+	raw := json.RawMessage(`{"version":1,"reminder":{"eventId":"e2e-1","title":"E2E朝会","start":"` + todayAt(9, 0) + `","location":"会議室A","remindAt":"` + todayAt(8, 50) + `"}}`)
+	resp, err := handler(context.Background(), raw)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "リマインダー送信完了", resp.Message)
+
+	requests := lineServer.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "e2e-user", requests[0].To)
+	require.Len(t, requests[0].Messages, 1)
+	assert.Contains(t, requests[0].Messages[0].Text, "09:00〜 E2E朝会")
+	assert.Contains(t, requests[0].Messages[0].Text, "📍 会議室A")
+	assert.Equal(t, 0, calendarServer.ListCalls())
+}
+
+func TestHandler_E2E_PayloadFlags(t *testing.T) {
+	calendarServer, lineServer := setupE2E(t)
+	calendarServer.AddEvents("e2e-calendar", &calendar.Event{
+		Id:      "e2e-1",
+		Summary: "E2E朝会",
+		Start:   &calendar.EventDateTime{DateTime: todayAt(9, 0)},
+		End:     &calendar.EventDateTime{DateTime: todayAt(9, 30)},
+	})
+
+	resp, err := handler(context.Background(), json.RawMessage(`{"version":1,"flags":["split_day_messages"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	requests := lineServer.Requests()
+	require.Len(t, requests, 1)
+	assert.Len(t, requests[0].Messages, 2)
+}
+
+func TestHandler_E2E_Schedule(t *testing.T) {
+	calendarServer, lineServer := setupE2E(t)
+	// template.yamlのSCHEDULESと各スケジュールのInputと同じ値
+	t.Setenv("SCHEDULES", `[{"name":"morning"},{"name":"evening","flags":["split_day_messages"]}]`)
+	calendarServer.AddEvents("e2e-calendar", &calendar.Event{
+		Id:      "e2e-1",
+		Summary: "E2E朝会",
+		Start:   &calendar.EventDateTime{DateTime: todayAt(9, 0)},
+		End:     &calendar.EventDateTime{DateTime: todayAt(9, 30)},
+	})
+
+	resp, err := handler(context.Background(), json.RawMessage(`{"version":1,"schedule":"evening"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	requests := lineServer.Requests()
+	require.Len(t, requests, 1)
+	assert.Len(t, requests[0].Messages, 2)
+
+	resp, err = handler(context.Background(), json.RawMessage(`{"schedule":"noon"}`))
+	assert.ErrorContains(t, err, "スケジュール noon はSCHEDULESに定義されていません")
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Len(t, lineServer.Requests(), 1)
+}
+
+func TestHandler_E2E_TodayOverrideDryRun(t *testing.T) {
+	calendarServer, lineServer := setupE2E(t)
+	calendarServer.AddEvents("e2e-calendar", &calendar.Event{
+		Id:      "e2e-nye",
+		Summary: "年越し",
+		Start:   &calendar.EventDateTime{DateTime: "2024-12-31T23:00:00+09:00"},
+		End:     &calendar.EventDateTime{DateTime: "2025-01-01T00:30:00+09:00"},
+	})
+
+	resp, err := handler(context.Background(), json.RawMessage(`{"todayOverride":"2024-12-31"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "ドライラン完了（LINE送信なし）", resp.Message)
+	assert.Empty(t, lineServer.Requests())
+}
+
+func TestHandler_E2E_TodayOverrideForce(t *testing.T) {
+	calendarServer, lineServer := setupE2E(t)
+	calendarServer.AddEvents("e2e-calendar", &calendar.Event{
+		Id:      "e2e-leap",
+		Summary: "うるう日",
+		Start:   &calendar.EventDateTime{Date: "2024-02-29"},
+		End:     &calendar.EventDateTime{Date: "2024-03-01"},
+	})
+
+	resp, err := handler(context.Background(), json.RawMessage(`{"todayOverride":"2024-02-28","force":true}`))
+	require.NoError(t, err)
+	assert.Equal(t, "通知送信完了", resp.Message)
+
+	requests := lineServer.Requests()
+	require.Len(t, requests, 1)
+	assert.Contains(t, requests[0].Messages[0].Text, "本日 2/28(水): 予定なし")
+	assert.Contains(t, requests[0].Messages[0].Text, "翌日 2/29(木)")
+	assert.Contains(t, requests[0].Messages[0].Text, "うるう日")
+}
+
+func TestHandler_E2E_IncrementalSyncSkippedInDryRun(t *testing.T) {
+	calendarServer, lineServer := setupE2E(t)
+	t.Setenv("STORE_BACKEND", "memory")
+	t.Setenv("INCREMENTAL_SYNC", "true")
+	calendarServer.AddEvents("e2e-calendar", &calendar.Event{
+		Id:      "e2e-1",
+		Summary: "E2E朝会",
+		Start:   &calendar.EventDateTime{DateTime: todayAt(9, 0)},
+		End:     &calendar.EventDateTime{DateTime: todayAt(9, 30)},
+	})
+
+	// ドライランではsyncTokenを保存しないため、差分取得を使わずに全件を取得する
+	resp, err := handler(context.Background(), json.RawMessage(`{"dryRun":true}`))
+	require.NoError(t, err)
+	assert.Equal(t, "ドライラン完了（LINE送信なし）", resp.Message)
+	assert.Empty(t, lineServer.Requests())
+	assert.Equal(t, 0, calendarServer.SyncCalls())
+
+	// 通常の実行では1日目の全件取得で保存したsyncTokenで、2日目を差分取得する
+	_, err = handler(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Len(t, lineServer.Requests(), 1)
+	assert.Equal(t, 1, calendarServer.SyncCalls())
+}
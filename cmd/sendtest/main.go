@@ -0,0 +1,64 @@
+// Command sendtest は通知と同じ設定（トークン・宛先・プロキシ・CA証明書）で任意のテキストをLINEに送信し、
+// 送信経路をエンドツーエンドで確認する
+//
+//	go run ./cmd/sendtest [-text "テスト送信"] [-to Uxxxxxxxx]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/config"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/gateway"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/transport"
+)
+
+// defaultText -textを省略した場合に送信するテキスト
+const defaultText = "Google Calendar LINE Notifier からのテスト送信です"
+
+func main() {
+	text := flag.String("text", defaultText, "送信するテキスト")
+	to := flag.String("to", "", "送信先のLINEユーザーIDまたはグループID。省略時は通知先（LINE_USER_ID）に送信する")
+	flag.Parse()
+
+	if err := run(context.Background(), *text, *to); err != nil {
+		fmt.Fprintf(os.Stderr, "テスト送信に失敗しました: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, text, to string) error {
+	if text == "" {
+		return fmt.Errorf("送信するテキストが空です")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	base, err := transport.NewBase(cfg.CABundlePath)
+	if err != nil {
+		return fmt.Errorf("HTTPクライアントの初期化に失敗しました: %v", err)
+	}
+	httpTransport := transport.New(transport.Options{
+		UserAgent: cfg.UserAgent,
+		Debug:     cfg.IsDebug(),
+		Base:      base,
+	})
+
+	notifier := gateway.NewLINENotifier(cfg.LineChannelAccessToken, cfg.LineUserID, cfg.LineAPIBaseURL, httpTransport, clock.RealClock{})
+	notifier.SetDryRun(cfg.DryRun)
+	if err := notifier.SendTestMessage(ctx, to, text); err != nil {
+		return err
+	}
+
+	if to == "" {
+		to = cfg.LineUserID
+	}
+	fmt.Printf("テスト送信が完了しました: %s\n", to)
+	return nil
+}
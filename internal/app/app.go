@@ -0,0 +1,465 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/category"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/config"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/experiment"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/flags"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/gateway"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/metrics"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/payload"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/rewrite"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/scoring"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/transport"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/usecase"
+)
+
+// フィーチャーフラグ名
+const (
+	// FlagSplitDayMessages 本日・翌日の予定を別々のメッセージで送る
+	FlagSplitDayMessages = "split_day_messages"
+	// FlagPlainText 絵文字を使わないプレーンテキストで送る
+	FlagPlainText = "plain_text"
+)
+
+// App アプリケーション全体の依存関係を保持するコンポジションルート
+type App struct {
+	Config         *config.Config
+	Clock          clock.Clock
+	CalendarRepo   usecase.CalendarRepository
+	Notifier       usecase.Notifier
+	NotifySchedule *usecase.NotifyScheduleUseCase
+	// Store 状態を保存するストア（STORE_BACKENDが未設定の場合はnil）
+	Store store.Store
+	// Flags フィーチャーフラグ
+	Flags *flags.Flags
+	// Variant A/Bテストで選ばれたテンプレートのバリアント名（A/Bテストを行わない場合は空）
+	Variant string
+	// Reminders リマインダーの送信先
+	Reminders ReminderSender
+}
+
+// ReminderSender 予定のリマインダーを送信する
+type ReminderSender interface {
+	SendReminder(ctx context.Context, event domain.Event, remindAt time.Time) error
+}
+
+// New 設定から本番用の依存関係を組み立ててAppを作成
+func New(cfg *config.Config) (*App, error) {
+	return NewWithClock(cfg, clock.RealClock{})
+}
+
+// requestObserver 外部APIへのリクエストごとの所要時間とステータスをメトリクスとして出力するObserverを返す
+// 実行結果のメトリクスと同様に、CloudWatchが取り込むLambda上で実行している場合のみ出力する
+func requestObserver() transport.RequestObserver {
+	if os.Getenv("AWS_LAMBDA_FUNCTION_NAME") == "" {
+		return nil
+	}
+	return metrics.NewRequestObserver(os.Stdout)
+}
+
+// NewWithClock 時刻を指定して本番用の依存関係を組み立ててAppを作成
+// 日付を差し替えて表示を確認する場合に使う
+func NewWithClock(cfg *config.Config, clk clock.Clock) (*App, error) {
+	// GoogleとLINEのクライアントで共通のHTTPトランスポート
+	base, err := transport.NewBase(cfg.CABundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPクライアントの初期化に失敗しました: %v", err)
+	}
+	httpTransport := transport.New(transport.Options{
+		UserAgent: cfg.UserAgent,
+		Debug:     cfg.IsDebug(),
+		Observer:  requestObserver(),
+		Base:      base,
+	})
+
+	// 依存性の注入: Google Calendarリポジトリを初期化
+	calendarRepo, err := gateway.NewGoogleCalendarRepository([]byte(cfg.GoogleCredentials), cfg.CalendarIDs(), cfg.GoogleCalendarEndpoint, httpTransport)
+	if err != nil {
+		return nil, fmt.Errorf("google Calendarの初期化に失敗しました: %v", err)
+	}
+
+	// フィーチャーフラグ（AppConfigの値で環境変数の値を上書きする）
+	featureFlags := loadFlags(cfg, httpTransport)
+
+	// テンプレートのA/Bテストが設定されていれば、送信先と日付ごとに選んだバリアントのフラグを適用する
+	variant, err := chooseVariant(cfg, clk)
+	if err != nil {
+		return nil, err
+	}
+	featureFlags = featureFlags.Merge(variant.FlagSet())
+
+	// 依存性の注入: LINE通知クライアントを初期化
+	notifier := gateway.NewLINENotifier(cfg.LineChannelAccessToken, cfg.LineUserID, cfg.LineAPIBaseURL, httpTransport, clk)
+	allDayPlacement, err := gateway.ParseAllDayPlacement(cfg.AllDayPlacement)
+	if err != nil {
+		return nil, err
+	}
+	format := gateway.MessageFormat{
+		AllDayPlacement:      allDayPlacement,
+		MaxEventsPerDay:      cfg.MaxEventsPerDay,
+		MaxTitleLength:       cfg.MaxTitleLength,
+		MaxLocationLength:    cfg.MaxLocationLength,
+		SplitDays:            cfg.SplitDayMessages || featureFlags.Enabled(FlagSplitDayMessages),
+		PlainText:            cfg.PlainText || featureFlags.Enabled(FlagPlainText),
+		CelebrateMeetingFree: cfg.NotifyWhenEmpty,
+	}
+	if cfg.CategoryRules != "" {
+		rules, err := category.ParseRules(cfg.CategoryRules)
+		if err != nil {
+			return nil, err
+		}
+		format.Classifier = category.NewClassifier(rules)
+	}
+	var earlyWarning usecase.EarlyWarning
+	if cfg.EarlyWarningBefore != "" {
+		before, err := dates.ParseClock(cfg.EarlyWarningBefore)
+		if err != nil {
+			return nil, fmt.Errorf("EARLY_WARNING_BEFOREの値が不正です: %v", err)
+		}
+		earlyWarning = usecase.EarlyWarning{Before: before, OfficeKeywords: cfg.OfficeKeywords}
+	}
+	if len(cfg.Anniversaries) > 0 {
+		anniversaries, err := gateway.ParseAnniversaries(cfg.Anniversaries)
+		if err != nil {
+			return nil, err
+		}
+		format.Anniversaries = &gateway.AnniversaryCountdown{Anniversaries: anniversaries, Within: cfg.AnniversaryDays}
+	}
+	if len(cfg.MessageSections) > 0 {
+		sections, err := gateway.ParseSectionLayout(cfg.MessageSections)
+		if err != nil {
+			return nil, fmt.Errorf("MESSAGE_SECTIONSの値が不正です: %v", err)
+		}
+		format.Sections = sections
+	}
+	notifier.SetMessageFormat(format)
+	notifier.SetDebugFooter(cfg.IsDebug())
+	notifier.SetDryRun(cfg.DryRun)
+	if len(cfg.MentionMap) > 0 {
+		notifier.SetMentions(cfg.MentionMap)
+	}
+
+	// 状態を保存するストア（STORE_BACKENDが空の場合はnil）
+	stateStore, err := newStore(cfg, clk)
+	if err != nil {
+		return nil, err
+	}
+
+	// 差分取得が有効であれば、syncTokenをストアに保存して前回からの変更だけを取得する
+	// ドライランではストアに書き込まないよう、毎回全件を取得する
+	if cfg.IncrementalSync {
+		if stateStore == nil {
+			return nil, fmt.Errorf("INCREMENTAL_SYNCを有効にする場合はSTORE_BACKENDを設定してください")
+		}
+		if !cfg.DryRun {
+			calendarRepo.SetSyncStore(stateStore)
+		}
+	}
+
+	// ストアが設定されていれば送信前のメッセージをアウトボックスに保存し、送信失敗時は次回再送する
+	// ドライランでは送信しないため、アウトボックス・送信ログ・外部への出力は使わない
+	if stateStore != nil && !cfg.DryRun {
+		notifier.SetOutbox(stateStore)
+		// 保存形式が古いバージョンの場合は起動時に移行しておく（失敗しても読み込み時に移行する）
+		if err := notifier.MigrateOutbox(context.TODO()); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+
+	// 確認用の宛先が設定されていれば、送信したメッセージを間引きながら確認用の宛先にも送る
+	if cfg.DebugMirrorTo != "" && !cfg.DryRun {
+		notifier.SetDebugMirror(cfg.DebugMirrorTo, cfg.DebugMirrorInterval, stateStore)
+	}
+
+	// 送信ログが有効であれば送信したメッセージをストアに保存する
+	var auditLog *gateway.AuditLog
+	if cfg.AuditLog && !cfg.DryRun {
+		if stateStore == nil {
+			return nil, fmt.Errorf("AUDIT_LOGを有効にする場合はSTORE_BACKENDを設定してください")
+		}
+		auditLog = gateway.NewAuditLog(stateStore, cfg.AuditRetention, cfg.AuditRedact, clk)
+		notifier.SetAuditLog(auditLog)
+	}
+
+	// ICSバケットが設定されていれば本日の予定を.icsとしてエクスポートし、通知にリンクを添える
+	if cfg.ICSBucket != "" && !cfg.DryRun {
+		awsCfg, err := awsconfig.LoadDefaultConfig(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("AWS設定の読み込みに失敗しました: %v", err)
+		}
+		notifier.SetScheduleExporter(gateway.NewS3ICSExporter(s3.NewFromConfig(awsCfg), awsCfg.Credentials, cfg.ICSBucket, cfg.ICSKeyPrefix, cfg.ICSURLExpiry))
+	}
+
+	// 通知状況の書き込みが有効であれば、他の通知インスタンス（通知先の異なるもの）が本日通知済みの予定は除外する
+	var googleRepo usecase.CalendarRepository = calendarRepo
+	if cfg.NotificationWriteBack {
+		googleRepo = gateway.NewSkipNotifiedCalendarRepository(calendarRepo, gateway.NotificationInstanceID(cfg.LineUserID), clk)
+	}
+
+	// GitHubトークンが設定されていればマイルストーン期限を締め切りとして併せて取得
+	// 締め切りの取得に失敗しても（OptionalSourceTimeout以内に応答がなくても）予定の通知は継続する
+	sources := []gateway.CalendarSource{{Label: "Google", Repository: googleRepo, Required: true}}
+	if cfg.GitHubToken != "" {
+		sources = append(sources, gateway.CalendarSource{
+			Label:      "GitHub",
+			Repository: gateway.NewGitHubDeadlineRepository(cfg.GitHubToken, cfg.GitHubAPIBaseURL, httpTransport, dates.Location()),
+			Timeout:    cfg.OptionalSourceTimeout,
+		})
+	}
+
+	// 家族のカレンダーが設定されていれば、絞り込んだ予定を家族セクションとして併せて取得
+	// 家族のカレンダーの取得に失敗しても自分の予定の通知は継続する
+	if cfg.FamilyCalendarID != "" {
+		familyRepo, err := gateway.NewGoogleCalendarRepository([]byte(cfg.GoogleCredentials), []string{cfg.FamilyCalendarID}, cfg.GoogleCalendarEndpoint, httpTransport)
+		if err != nil {
+			return nil, fmt.Errorf("家族のカレンダーの初期化に失敗しました: %v", err)
+		}
+		filter := gateway.EventFilter{
+			Include:       cfg.FamilyIncludeKeywords,
+			Exclude:       cfg.FamilyExcludeKeywords,
+			ExcludeAllDay: cfg.FamilyExcludeAllDay,
+		}
+		sources = append(sources, gateway.CalendarSource{
+			Label:      "Family",
+			Repository: gateway.NewFamilyCalendarRepository(familyRepo, filter),
+			Timeout:    cfg.OptionalSourceTimeout,
+		})
+	}
+
+	var repo usecase.CalendarRepository = googleRepo
+	if len(sources) > 1 {
+		repo = gateway.NewCompositeCalendarRepository(sources...)
+	}
+
+	// 参加者の表示名が設定されていれば、メールアドレスの代わりに表示名を表示する
+	attendeeNames, err := loadAttendeeNames(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(attendeeNames) > 0 {
+		repo = gateway.NewAttendeeNameRepository(repo, attendeeNames)
+	}
+
+	// 設定されたルールで予定のタイトルを書き換える（重要度の算出や表示より前に適用）
+	if cfg.TitleRewriteRules != "" {
+		rules, err := rewrite.ParseRules(cfg.TitleRewriteRules)
+		if err != nil {
+			return nil, err
+		}
+		repo = rewrite.NewRepository(repo, rewrite.NewRewriter(rules))
+	}
+
+	// 予定の重要度を算出し、表示件数を絞る際の優先度に使う
+	repo = scoring.NewRepository(repo, scoring.NewScorer(cfg.PriorityKeywords, cfg.PriorityOrganizers))
+
+	// イベントバスが設定されていれば、正規化した予定を他システム向けに発行する
+	if cfg.EventBusName != "" && !cfg.DryRun {
+		awsCfg, err := awsconfig.LoadDefaultConfig(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("AWS設定の読み込みに失敗しました: %v", err)
+		}
+		publisher := gateway.NewEventBridgePublisher(eventbridge.NewFromConfig(awsCfg), cfg.EventBusName)
+		repo = gateway.NewPublishingCalendarRepository(repo, publisher)
+	}
+
+	// フラッシュブリーフィングのバケットが設定されていれば、LINE通知に加えて読み上げ用のフィードも保存する
+	var secondaries []gateway.ScheduleNotifier
+	if cfg.FlashBriefingBucket != "" && !cfg.DryRun {
+		awsCfg, err := awsconfig.LoadDefaultConfig(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("AWS設定の読み込みに失敗しました: %v", err)
+		}
+		secondaries = append(secondaries, gateway.NewFlashBriefingWriter(s3.NewFromConfig(awsCfg), cfg.FlashBriefingBucket, cfg.FlashBriefingKey))
+	}
+
+	// 重複抑止の時間が設定されていれば、通知した予定を記録して他の通知モードでの重複を抑止する
+	// 記録を参照する出力先より前に実行されるように追加する
+	var dedup *gateway.EventDedup
+	if cfg.DedupWindow > 0 && !cfg.DryRun {
+		if stateStore == nil {
+			return nil, fmt.Errorf("DEDUP_WINDOWを設定する場合はSTORE_BACKENDを設定してください")
+		}
+		dedup = gateway.NewEventDedup(stateStore, cfg.DedupWindow, clk)
+		secondaries = append(secondaries, dedup)
+	}
+
+	// スケジュールグループが設定されていれば、予定ごとのリマインダーをEventBridge Schedulerに登録する
+	if cfg.ReminderScheduleGroup != "" && !cfg.DryRun {
+		if cfg.ReminderTargetARN == "" || cfg.ReminderRoleARN == "" {
+			return nil, fmt.Errorf("REMINDER_SCHEDULE_GROUPを設定する場合はREMINDER_TARGET_ARNとREMINDER_ROLE_ARNを設定してください")
+		}
+		awsCfg, err := awsconfig.LoadDefaultConfig(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("AWS設定の読み込みに失敗しました: %v", err)
+		}
+		reminderScheduler := gateway.NewReminderScheduler(
+			scheduler.NewFromConfig(awsCfg), cfg.ReminderScheduleGroup, cfg.ReminderTargetARN, cfg.ReminderRoleARN, cfg.ReminderLead, clk)
+		if dedup != nil {
+			reminderScheduler.SetDedup(dedup)
+		}
+		secondaries = append(secondaries, reminderScheduler)
+	}
+
+	// 配信確認が有効であれば、前日の送信ログとLINEが集計した送信数を突き合わせて記録する
+	if cfg.DeliveryCheck && !cfg.DryRun {
+		if auditLog == nil {
+			return nil, fmt.Errorf("DELIVERY_CHECKを有効にする場合はAUDIT_LOGを有効にしてください")
+		}
+		secondaries = append(secondaries, gateway.NewLINEDeliveryVerifier(
+			cfg.LineChannelAccessToken, cfg.LineAPIBaseURL, httpTransport, cfg.LineUserID, auditLog, stateStore, cfg.AuditRetention, clk))
+	}
+
+	// 通知状況の書き込みが有効であれば、通知した予定に通知済みの日付を書き込む
+	if cfg.NotificationWriteBack && !cfg.DryRun {
+		patcher, err := gateway.NewGoogleEventsPatcher([]byte(cfg.GoogleCredentials), cfg.GoogleCalendarEndpoint, httpTransport)
+		if err != nil {
+			return nil, fmt.Errorf("google Calendarの初期化に失敗しました: %v", err)
+		}
+		secondaries = append(secondaries, gateway.NewNotificationStatusWriter(patcher, cfg.CalendarIDs(), gateway.NotificationInstanceID(cfg.LineUserID), clk))
+	}
+
+	var scheduleNotifier usecase.Notifier = notifier
+	if len(secondaries) > 0 {
+		scheduleNotifier = gateway.NewMultiNotifier(notifier, secondaries...)
+	}
+
+	application := NewWithDependencies(cfg, clk, repo, scheduleNotifier)
+	outOfOfficeMute, err := usecase.ParseOutOfOfficeMute(cfg.OutOfOfficeMute)
+	if err != nil {
+		return nil, err
+	}
+	application.NotifySchedule.SetOutOfOffice(usecase.OutOfOffice{Mute: outOfOfficeMute, Keywords: cfg.OutOfOfficeKeywords})
+	application.NotifySchedule.SetEarlyWarning(earlyWarning)
+	application.NotifySchedule.SetNotifyWhenEmpty(cfg.NotifyWhenEmpty)
+	application.Store = stateStore
+	application.Flags = featureFlags
+	application.Variant = variant.Name
+	application.Reminders = notifier
+	return application, nil
+}
+
+// loadFlags 環境変数とAppConfigからフィーチャーフラグを読み込む
+// AppConfigから取得できない場合は環境変数の値のみで続行する
+func loadFlags(cfg *config.Config, rt http.RoundTripper) *flags.Flags {
+	featureFlags := flags.Parse(cfg.FeatureFlags)
+	if cfg.AppConfigFlagsURL == "" {
+		return featureFlags
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	remote, err := flags.LoadAppConfig(ctx, &http.Client{Transport: rt}, cfg.AppConfigFlagsURL)
+	if err != nil {
+		fmt.Printf("Warning: AppConfigからフィーチャーフラグを取得できませんでした: %v\n", err)
+		return featureFlags
+	}
+	return featureFlags.Merge(remote)
+}
+
+// loadAttendeeNames 表示名ファイルと環境変数から参加者の表示名を読み込む（環境変数の値を優先する）
+func loadAttendeeNames(cfg *config.Config) (map[string]string, error) {
+	names := map[string]string{}
+	if cfg.AttendeeNamesFile != "" {
+		fromFile, err := gateway.LoadAttendeeNames(cfg.AttendeeNamesFile)
+		if err != nil {
+			return nil, err
+		}
+		for email, name := range fromFile {
+			names[email] = name
+		}
+	}
+	for email, name := range cfg.AttendeeNames {
+		names[email] = name
+	}
+	return names, nil
+}
+
+// chooseVariant 送信先と本日の日付からテンプレートのバリアントを選ぶ（A/Bテストを行わない場合はゼロ値）
+func chooseVariant(cfg *config.Config, clk clock.Clock) (experiment.Variant, error) {
+	variants, err := experiment.ParseVariants(cfg.TemplateVariants)
+	if err != nil {
+		return experiment.Variant{}, err
+	}
+	variant, _ := experiment.Choose(variants, cfg.LineUserID+":"+clk.Now().In(dates.Location()).Format("2006-01-02"))
+	return variant, nil
+}
+
+// newStore 設定されたバックエンドのストアを作成（未設定の場合はnil）
+func newStore(cfg *config.Config, clk clock.Clock) (store.Store, error) {
+	switch cfg.StoreBackend {
+	case "":
+		return nil, nil
+	case "memory":
+		return store.NewMemoryStore(clk), nil
+	case "dynamodb":
+		if cfg.StoreTable == "" {
+			return nil, fmt.Errorf("STORE_BACKEND=dynamodbの場合はSTORE_TABLEを設定してください")
+		}
+		awsCfg, err := awsconfig.LoadDefaultConfig(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("AWS設定の読み込みに失敗しました: %v", err)
+		}
+		return store.NewDynamoDBStore(dynamodb.NewFromConfig(awsCfg), cfg.StoreTable, clk), nil
+	case "redis":
+		if cfg.RedisURL == "" {
+			return nil, fmt.Errorf("STORE_BACKEND=redisの場合はREDIS_URLを設定してください")
+		}
+		return store.OpenRedisStore(cfg.RedisURL)
+	case "bolt":
+		if cfg.StorePath == "" {
+			return nil, fmt.Errorf("STORE_BACKEND=boltの場合はSTORE_PATHを設定してください")
+		}
+		return store.OpenBoltStore(cfg.StorePath, clk)
+	default:
+		return nil, fmt.Errorf("STORE_BACKENDの値が不正です: %s", cfg.StoreBackend)
+	}
+}
+
+// NewWithDependencies 時刻・リポジトリ・通知クライアントを指定してAppを作成（テスト用）
+func NewWithDependencies(cfg *config.Config, clk clock.Clock, calendarRepo usecase.CalendarRepository, notifier usecase.Notifier) *App {
+	return &App{
+		Config:         cfg,
+		Clock:          clk,
+		CalendarRepo:   calendarRepo,
+		Notifier:       notifier,
+		NotifySchedule: usecase.NewNotifyScheduleUseCase(calendarRepo, notifier, clk),
+	}
+}
+
+// Run 今日と明日の予定通知を実行する（送信しなかった場合はその理由を返す）
+func (a *App) Run(ctx context.Context) (usecase.SkipReason, error) {
+	if a.Variant != "" {
+		ctx = experiment.WithVariant(ctx, a.Variant)
+	}
+	return a.NotifySchedule.Run(ctx)
+}
+
+// Remind リマインダーのスケジュールから呼び出され、予定のリマインダーを送信する
+func (a *App) Remind(ctx context.Context, reminder payload.Reminder) error {
+	if a.Reminders == nil {
+		return fmt.Errorf("リマインダーの送信先が設定されていません")
+	}
+	return a.Reminders.SendReminder(ctx, domain.Event{
+		ID:        reminder.EventID,
+		Title:     reminder.Title,
+		StartTime: reminder.Start,
+		Location:  reminder.Location,
+	}, reminder.RemindAt)
+}
@@ -0,0 +1,158 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/config"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/metrics"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/usecase"
+)
+
+// testNow テストで使用する固定の現在時刻（2024/1/15 9:00 JST）
+var testNow = time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+// fakeCalendarRepository は日付ごとに固定のイベントを返すテスト用リポジトリ
+type fakeCalendarRepository struct {
+	events  []domain.Event
+	err     error
+	mu      sync.Mutex
+	targets []time.Time
+}
+
+func (f *fakeCalendarRepository) GetEvents(_ context.Context, targetDate time.Time) ([]domain.Event, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.targets = append(f.targets, targetDate)
+	return f.events, f.err
+}
+
+// fakeNotifier は呼び出し回数を記録するテスト用通知クライアント
+type fakeNotifier struct {
+	calls int
+}
+
+func (f *fakeNotifier) SendScheduleNotification(_ context.Context, _ domain.ScheduleReport) error {
+	f.calls++
+	return nil
+}
+
+func TestNew_InvalidCredentials(t *testing.T) {
+	cfg := &config.Config{GoogleCredentials: "not valid json", CalendarID: "primary"}
+
+	_, err := New(cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "google Calendarの初期化に失敗しました")
+}
+
+func TestRun_SendsNotification(t *testing.T) {
+	repo := &fakeCalendarRepository{events: []domain.Event{{Title: "朝会"}}}
+	notifier := &fakeNotifier{}
+	a := NewWithDependencies(&config.Config{}, clock.Fixed(testNow), repo, notifier)
+
+	reason, err := a.Run(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, reason)
+	assert.Equal(t, 1, notifier.calls)
+
+	// 今日と明日の2日分を取得している
+	require.Len(t, repo.targets, 2)
+	days := []string{repo.targets[0].Format("2006-01-02"), repo.targets[1].Format("2006-01-02")}
+	assert.ElementsMatch(t, []string{"2024-01-15", "2024-01-16"}, days)
+}
+
+func TestRun_NoEvents_Skipped(t *testing.T) {
+	notifier := &fakeNotifier{}
+	a := NewWithDependencies(&config.Config{}, clock.Fixed(testNow), &fakeCalendarRepository{}, notifier)
+
+	reason, err := a.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, usecase.SkipReasonEmpty, reason)
+	assert.Equal(t, 0, notifier.calls)
+}
+
+func TestRun_CalendarError(t *testing.T) {
+	repo := &fakeCalendarRepository{err: errors.New("calendar API error")}
+	a := NewWithDependencies(&config.Config{}, clock.Fixed(testNow), repo, &fakeNotifier{})
+
+	_, err := a.Run(context.Background())
+	assert.Error(t, err)
+}
+
+func TestNewStore(t *testing.T) {
+	clk := clock.Fixed(testNow)
+
+	t.Run("未設定の場合はストアを使用しない", func(t *testing.T) {
+		s, err := newStore(&config.Config{}, clk)
+		require.NoError(t, err)
+		assert.Nil(t, s)
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		s, err := newStore(&config.Config{StoreBackend: "memory"}, clk)
+		require.NoError(t, err)
+		assert.IsType(t, &store.MemoryStore{}, s)
+	})
+
+	t.Run("redis", func(t *testing.T) {
+		s, err := newStore(&config.Config{StoreBackend: "redis", RedisURL: "rediss://cache.example.com:6379/0"}, clk)
+		require.NoError(t, err)
+		assert.IsType(t, &store.RedisStore{}, s)
+	})
+
+	t.Run("必須の設定が不足している場合はエラー", func(t *testing.T) {
+		for _, backend := range []string{"dynamodb", "redis", "bolt"} {
+			_, err := newStore(&config.Config{StoreBackend: backend}, clk)
+			assert.Error(t, err, backend)
+		}
+	})
+
+	t.Run("不明なバックエンドはエラー", func(t *testing.T) {
+		_, err := newStore(&config.Config{StoreBackend: "sqlite"}, clk)
+		assert.ErrorContains(t, err, "STORE_BACKENDの値が不正です")
+	})
+}
+
+func TestRequestObserver(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "")
+	assert.Nil(t, requestObserver())
+
+	// Lambda上ではリクエストごとのメトリクスを出力する
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "google-calendar-line-notifier")
+	assert.IsType(t, &metrics.RequestObserver{}, requestObserver())
+}
+
+func TestLoadFlags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"plain_text":{"enabled":false},"split_day_messages":{"enabled":true}}`))
+	}))
+	defer server.Close()
+
+	t.Run("環境変数のみ", func(t *testing.T) {
+		f := loadFlags(&config.Config{FeatureFlags: []string{FlagPlainText}}, nil)
+		assert.True(t, f.Enabled(FlagPlainText))
+		assert.False(t, f.Enabled(FlagSplitDayMessages))
+	})
+
+	t.Run("AppConfigの値で上書き", func(t *testing.T) {
+		f := loadFlags(&config.Config{FeatureFlags: []string{FlagPlainText}, AppConfigFlagsURL: server.URL}, http.DefaultTransport)
+		assert.False(t, f.Enabled(FlagPlainText))
+		assert.True(t, f.Enabled(FlagSplitDayMessages))
+	})
+
+	t.Run("AppConfigから取得できない場合は環境変数の値で続行", func(t *testing.T) {
+		f := loadFlags(&config.Config{FeatureFlags: []string{FlagPlainText}, AppConfigFlagsURL: "http://127.0.0.1:1/flags"}, http.DefaultTransport)
+		assert.True(t, f.Enabled(FlagPlainText))
+	})
+}
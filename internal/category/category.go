@@ -0,0 +1,100 @@
+// Package category は予定をルールに基づいてカテゴリ（会議・集中・個人・移動など）に分類する
+package category
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+// Uncategorized どのルールにも一致しなかった予定のカテゴリ名
+const Uncategorized = "その他"
+
+// Rule タイトルに含まれるキーワードでカテゴリを判定するルール
+type Rule struct {
+	Name     string
+	Keywords []string
+}
+
+// Total カテゴリごとの合計時間
+type Total struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Classifier ルールを先頭から順に評価して予定を分類する
+type Classifier struct {
+	rules []Rule
+}
+
+// NewClassifier ルールを指定してClassifierを作成
+func NewClassifier(rules []Rule) *Classifier {
+	return &Classifier{rules: rules}
+}
+
+// ParseRules "会議:定例|MTG;移動:移動|出張" 形式の文字列からルールを解析する
+// カテゴリは ; 区切り、カテゴリ名とキーワードは : 区切り、キーワードは | 区切り
+func ParseRules(s string) ([]Rule, error) {
+	var rules []Rule
+	for _, part := range strings.Split(s, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		name, keywords, ok := strings.Cut(part, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("カテゴリルールの形式が不正です: %q", part)
+		}
+
+		rule := Rule{Name: name}
+		for _, keyword := range strings.Split(keywords, "|") {
+			if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+				rule.Keywords = append(rule.Keywords, keyword)
+			}
+		}
+		if len(rule.Keywords) == 0 {
+			return nil, fmt.Errorf("カテゴリ %s にキーワードが指定されていません", name)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Classify 予定のカテゴリ名を返す（一致するルールがなければUncategorized）
+func (c *Classifier) Classify(event domain.Event) string {
+	title := strings.ToLower(event.Title)
+	for _, rule := range c.rules {
+		for _, keyword := range rule.Keywords {
+			if strings.Contains(title, keyword) {
+				return rule.Name
+			}
+		}
+	}
+	return Uncategorized
+}
+
+// Totals 時間指定の予定をカテゴリごとに集計する
+// 結果はルールの定義順で、Uncategorizedは末尾に並ぶ。終日予定と合計0のカテゴリは含まない
+func (c *Classifier) Totals(events []domain.Event) []Total {
+	durations := make(map[string]time.Duration)
+	for _, event := range events {
+		if event.IsAllDay || !event.EndTime.After(event.StartTime) {
+			continue
+		}
+		durations[c.Classify(event)] += event.EndTime.Sub(event.StartTime)
+	}
+
+	var totals []Total
+	for _, rule := range c.rules {
+		if d, ok := durations[rule.Name]; ok {
+			totals = append(totals, Total{Name: rule.Name, Duration: d})
+			delete(durations, rule.Name)
+		}
+	}
+	if d, ok := durations[Uncategorized]; ok {
+		totals = append(totals, Total{Name: Uncategorized, Duration: d})
+	}
+	return totals
+}
@@ -0,0 +1,72 @@
+package category
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("会議:定例|MTG; 移動 : 移動|出張 ;")
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Name: "会議", Keywords: []string{"定例", "mtg"}},
+		{Name: "移動", Keywords: []string{"移動", "出張"}},
+	}, rules)
+}
+
+func TestParseRules_Empty(t *testing.T) {
+	rules, err := ParseRules("")
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	for _, input := range []string{"会議", ":定例", "会議:"} {
+		_, err := ParseRules(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestClassifier_Classify(t *testing.T) {
+	c := NewClassifier([]Rule{
+		{Name: "会議", Keywords: []string{"mtg"}},
+		{Name: "集中", Keywords: []string{"作業"}},
+	})
+
+	assert.Equal(t, "会議", c.Classify(domain.Event{Title: "週次MTG"}))
+	assert.Equal(t, "集中", c.Classify(domain.Event{Title: "資料作業"}))
+	// 先に定義したルールが優先される
+	assert.Equal(t, "会議", c.Classify(domain.Event{Title: "MTG準備作業"}))
+	assert.Equal(t, Uncategorized, c.Classify(domain.Event{Title: "ランチ"}))
+}
+
+func TestClassifier_Totals(t *testing.T) {
+	c := NewClassifier([]Rule{
+		{Name: "会議", Keywords: []string{"mtg"}},
+		{Name: "移動", Keywords: []string{"移動"}},
+		{Name: "集中", Keywords: []string{"作業"}},
+	})
+	start := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	event := func(title string, minutes int) domain.Event {
+		return domain.Event{Title: title, StartTime: start, EndTime: start.Add(time.Duration(minutes) * time.Minute)}
+	}
+
+	totals := c.Totals([]domain.Event{
+		event("ランチ", 60),
+		event("MTG", 30),
+		event("作業", 120),
+		event("MTG", 45),
+		{Title: "移動日", IsAllDay: true, StartTime: start, EndTime: start.Add(24 * time.Hour)},
+	})
+
+	assert.Equal(t, []Total{
+		{Name: "会議", Duration: 75 * time.Minute},
+		{Name: "集中", Duration: 2 * time.Hour},
+		{Name: Uncategorized, Duration: time.Hour},
+	}, totals)
+}
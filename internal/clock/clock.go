@@ -0,0 +1,38 @@
+package clock
+
+import "time"
+
+// Clock 現在時刻を提供する
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock システム時刻を返すClockの実装
+type RealClock struct{}
+
+// Now 現在のシステム時刻を返す
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// Func 関数をClockとして扱うためのアダプタ
+type Func func() time.Time
+
+// Now 関数を呼び出して時刻を返す
+func (f Func) Now() time.Time {
+	return f()
+}
+
+// Fixed 常に同じ時刻を返すClockを作成（テスト・シミュレーション用）
+func Fixed(t time.Time) Clock {
+	return Func(func() time.Time { return t })
+}
+
+// OnDate 日付だけをdateに差し替え、時刻はbaseの現在時刻を返すClockを作成
+// 年末年始や月末などの表示を任意の日付で確認する際に使う（タイムゾーンはdateのもの）
+func OnDate(base Clock, date time.Time) Clock {
+	return Func(func() time.Time {
+		now := base.Now().In(date.Location())
+		return time.Date(date.Year(), date.Month(), date.Day(), now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), date.Location())
+	})
+}
@@ -0,0 +1,17 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnDate(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	base := Fixed(time.Date(2024, 6, 10, 1, 30, 15, 0, time.UTC))
+
+	clk := OnDate(base, time.Date(2024, 12, 31, 0, 0, 0, 0, jst))
+
+	assert.Equal(t, time.Date(2024, 12, 31, 10, 30, 15, 0, jst), clk.Now())
+}
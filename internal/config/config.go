@@ -5,12 +5,33 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/joho/godotenv"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
+)
+
+const (
+	// defaultICSKeyPrefix ICSファイルを保存するS3キーのデフォルトのプレフィックス
+	defaultICSKeyPrefix = "ics/"
+	// defaultICSURLExpiry ICSファイルの署名付きURLのデフォルトの有効期限
+	defaultICSURLExpiry = 24 * time.Hour
+	// defaultAuditRetention 送信ログのデフォルトの保存期間
+	defaultAuditRetention = 90 * 24 * time.Hour
+	// defaultReminderLead 予定にリマインダーが設定されていない場合に開始の何分前に通知するか
+	defaultReminderLead = 10 * time.Minute
+	// defaultDebugMirrorInterval 確認用の宛先に送信する最短の間隔
+	defaultDebugMirrorInterval = time.Hour
+	// defaultAnniversaryDays 記念日の何日前からカウントダウンを表示するか
+	defaultAnniversaryDays = 7
+	// defaultOptionalSourceTimeout 任意の取得元の応答を待つデフォルトの時間
+	defaultOptionalSourceTimeout = 5 * time.Second
 )
 
 // SSMParameterGetter は AWS SSM Parameter Store からパラメータを取得する
@@ -22,14 +43,148 @@ type SSMParameterGetter interface {
 type Config struct {
 	// Google Calendar設定
 	GoogleCredentials string
-	CalendarID        string
+	// CalendarID 予定を取得するカレンダーのID（カンマ区切りで複数指定するとまとめて通知する）
+	CalendarID string
+
+	// Timezone 今日・明日の判定、予定の時刻の表示、リマインダーの登録に使うタイムゾーン（TZ_NAME、既定はAsia/Tokyo）
+	Timezone *time.Location
+
+	// 家族（パートナー）のカレンダー設定（任意、FamilyCalendarIDが空の場合は家族セクションを表示しない）
+	// タイトルのキーワードと終日予定の有無で表示する予定を絞り込む
+	FamilyCalendarID      string
+	FamilyIncludeKeywords []string
+	FamilyExcludeKeywords []string
+	FamilyExcludeAllDay   bool
+
+	// NotificationWriteBackがtrueの場合、通知した予定に通知済みの日付と通知インスタンス（通知先から決まる）を書き込み、
+	// 他の通知インスタンスが本日すでに通知済みの予定は通知しない（同じカレンダーを複数の通知インスタンスで共有する場合に使う）
+	NotificationWriteBack bool
 
 	// LINE API設定
 	LineChannelAccessToken string
 	LineUserID             string
 
+	// 送信したメッセージを確認用のLINEユーザーにも送る設定（任意、DebugMirrorToが空の場合は無効）
+	// DebugMirrorInterval以内の再送は間引く
+	DebugMirrorTo       string
+	DebugMirrorInterval time.Duration
+
+	// GitHub設定（締め切り表示用、任意）
+	GitHubToken      string
+	GitHubAPIBaseURL string
+
+	// 任意の取得元（GitHubの締め切り・家族のカレンダー）の応答を待つ時間（0以下の場合は打ち切らない）
+	// 時間内に応答しない取得元は、その予定のみ除外して通知する
+	OptionalSourceTimeout time.Duration
+
+	// APIエンドポイント設定（テスト・検証環境向けの上書き用）
+	GoogleCalendarEndpoint string
+	LineAPIBaseURL         string
+
+	// HTTP通信設定
+	UserAgent    string
+	CABundlePath string
+
+	// ICSエクスポート設定（任意、ICSBucketが空の場合は無効）
+	// 署名付きURLは実行ロールの認証情報で署名するため、ICSURLExpiryより前に認証情報の期限で失効する場合は短縮する
+	ICSBucket    string
+	ICSKeyPrefix string
+	ICSURLExpiry time.Duration
+
+	// 取得した予定の発行先EventBridgeバス（任意、空の場合は発行しない）
+	EventBusName string
+
+	// Alexaフラッシュブリーフィングの保存先（任意、FlashBriefingBucketが空の場合は無効）
+	FlashBriefingBucket string
+	FlashBriefingKey    string
+
+	// 予定ごとのリマインダーを登録するEventBridge Schedulerの設定（任意、ReminderScheduleGroupが空の場合は無効）
+	// ReminderTargetARNに指定したターゲット（この関数）をReminderRoleARNのロールで呼び出し、リマインダーを送信させる
+	ReminderScheduleGroup string
+	ReminderTargetARN     string
+	ReminderRoleARN       string
+	ReminderLead          time.Duration
+
+	// 通知済みの予定を他の通知モードで再び通知しない時間（0の場合は重複を抑止しない、StoreBackendが必要）
+	DedupWindow time.Duration
+
+	// 状態を保存するストア（StoreBackendが空の場合は使用しない）
+	// memory / dynamodb（StoreTable） / redis（RedisURL） / bolt（StorePath）
+	StoreBackend string
+	StoreTable   string
+	StorePath    string
+	RedisURL     string
+
+	// 送信ログ設定（AuditLogがtrueの場合、送信したメッセージをストアに保存する）
+	AuditLog       bool
+	AuditRetention time.Duration
+	AuditRedact    bool
+	// DeliveryCheck trueの場合、前日の送信ログとLINEが集計した送信数を突き合わせて記録する（AuditLogが必要）
+	DeliveryCheck bool
+	// IncrementalSync trueの場合、syncTokenをストアに保存してカレンダーの予定を差分で取得する（StoreBackendが必要）
+	IncrementalSync bool
+
+	// 名前付きの表示テンプレート（JSON、名前とフィーチャーフラグ）。ペイロードのscheduleで選択する
+	Schedules string
+
+	// フィーチャーフラグ（カンマ区切り、"-"を付けると無効）とAppConfig拡張機能のURL（任意）
+	FeatureFlags      []string
+	AppConfigFlagsURL string
+
+	// テンプレートのA/Bテスト（JSON配列、例: [{"name":"control","weight":3},{"name":"plain","weight":1,"flags":["plain_text"]}]）
+	// 送信先と日付ごとに重みの比率でバリアントを選び、そのフィーチャーフラグを適用する
+	TemplateVariants string
+
+	// メッセージ表示設定
+	AllDayPlacement   string
+	MaxEventsPerDay   int
+	MaxTitleLength    int
+	MaxLocationLength int
+	SplitDayMessages  bool
+	PlainText         bool
+	// NotifyWhenEmpty trueの場合、予定がない日も通知し、会議のない日を「会議のない一日」として表示する
+	NotifyWhenEmpty bool
+
+	// カテゴリ分類ルール（例: "会議:定例|MTG;移動:移動|出張"、空の場合は内訳を表示しない）
+	CategoryRules string
+
+	// 出社日の早い予定の警告（EarlyWarningBeforeが空の場合は無効、"HH:MM"形式）
+	EarlyWarningBefore string
+	OfficeKeywords     []string
+
+	// 本日が不在（休暇など）の日の通知の抑制（off / work / all、空の場合はall）
+	// 不在の予定はGoogleカレンダーの「不在」予定と、OutOfOfficeKeywordsをタイトルに含む終日予定
+	OutOfOfficeMute     string
+	OutOfOfficeKeywords []string
+
+	// タイトル書き換えルール（JSON配列、例: [{"pattern":"^\\[PRJ-\\d+\\]\\s*","replacement":""}]）
+	TitleRewriteRules string
+
+	// グループ通知でのメンション設定（参加者のメールアドレス→LINEユーザーID）
+	MentionMap map[string]string
+
+	// 参加者の表示名（参加者のメールアドレス→表示名）
+	// AttendeeNamesFileのJSONファイルの内容に、ATTENDEE_NAMESの値を上書きして使う
+	AttendeeNames     map[string]string
+	AttendeeNamesFile string
+
+	// カレンダーに登録していない記念日・誕生日（名前→MM-DD）
+	// 記念日のAnniversaryDays日前からカウントダウンを表示する
+	Anniversaries   map[string]string
+	AnniversaryDays int
+
+	// 通知メッセージのセクションの表示順（カンマ区切り、空の場合は既定の順序）
+	// 例: "tomorrow,today,deadlines,events"（含めなかったセクションは表示しない）
+	MessageSections []string
+
+	// 重要度スコア設定（カンマ区切り）
+	PriorityKeywords   []string
+	PriorityOrganizers []string
+
 	// その他設定
 	LogLevel string
+	// DryRun trueの場合はLINEに送信せず、メッセージをログに出力する（外部への書き込みも行わない）
+	DryRun bool
 
 	// AWS関連（本番環境でのみ使用）
 	ssmClient SSMParameterGetter
@@ -57,8 +212,189 @@ func loadLocalConfig() (*Config, error) {
 		CalendarID:             getEnvOrDefault("CALENDAR_ID", "primary"),
 		LineChannelAccessToken: getEnvOrDefault("LINE_CHANNEL_ACCESS_TOKEN", ""),
 		LineUserID:             getEnvOrDefault("LINE_USER_ID", ""),
+		GitHubToken:            getEnvOrDefault("GITHUB_TOKEN", ""),
+		GitHubAPIBaseURL:       getEnvOrDefault("GITHUB_API_BASE_URL", ""),
+		GoogleCalendarEndpoint: getEnvOrDefault("GOOGLE_CALENDAR_ENDPOINT", ""),
+		LineAPIBaseURL:         getEnvOrDefault("LINE_API_BASE_URL", ""),
 		LogLevel:               getEnvOrDefault("LOG_LEVEL", "INFO"),
+		UserAgent:              getEnvOrDefault("HTTP_USER_AGENT", ""),
+		CABundlePath:           getEnvOrDefault("CA_BUNDLE_PATH", ""),
+		Schedules:              getEnvOrDefault("SCHEDULES", ""),
+		DebugMirrorTo:          getEnvOrDefault("DEBUG_MIRROR_TO", ""),
+		AttendeeNamesFile:      getEnvOrDefault("ATTENDEE_NAMES_FILE", ""),
+		ICSBucket:              getEnvOrDefault("ICS_BUCKET", ""),
+		ICSKeyPrefix:           getEnvOrDefault("ICS_KEY_PREFIX", defaultICSKeyPrefix),
+		EventBusName:           getEnvOrDefault("EVENT_BUS_NAME", ""),
+		FlashBriefingBucket:    getEnvOrDefault("FLASH_BRIEFING_BUCKET", ""),
+		FlashBriefingKey:       getEnvOrDefault("FLASH_BRIEFING_KEY", ""),
+		TemplateVariants:       getEnvOrDefault("TEMPLATE_VARIANTS", ""),
+		ReminderScheduleGroup:  getEnvOrDefault("REMINDER_SCHEDULE_GROUP", ""),
+		ReminderTargetARN:      getEnvOrDefault("REMINDER_TARGET_ARN", ""),
+		ReminderRoleARN:        getEnvOrDefault("REMINDER_ROLE_ARN", ""),
+		StoreBackend:           getEnvOrDefault("STORE_BACKEND", ""),
+		StoreTable:             getEnvOrDefault("STORE_TABLE", ""),
+		StorePath:              getEnvOrDefault("STORE_PATH", ""),
+		RedisURL:               getEnvOrDefault("REDIS_URL", ""),
+		FeatureFlags:           getListEnv("FEATURE_FLAGS"),
+		AppConfigFlagsURL:      getEnvOrDefault("APPCONFIG_FLAGS_URL", ""),
+		AllDayPlacement:        getEnvOrDefault("ALL_DAY_PLACEMENT", ""),
+		CategoryRules:          getEnvOrDefault("CATEGORY_RULES", ""),
+		TitleRewriteRules:      getEnvOrDefault("TITLE_REWRITE_RULES", ""),
+		EarlyWarningBefore:     getEnvOrDefault("EARLY_WARNING_BEFORE", ""),
+		OfficeKeywords:         getListEnv("OFFICE_KEYWORDS"),
+		OutOfOfficeMute:        getEnvOrDefault("OOO_MUTE", ""),
+		OutOfOfficeKeywords:    getListEnv("OOO_KEYWORDS"),
+		PriorityKeywords:       getListEnv("PRIORITY_KEYWORDS"),
+		MessageSections:        getListEnv("MESSAGE_SECTIONS"),
+		FamilyCalendarID:       getEnvOrDefault("FAMILY_CALENDAR_ID", ""),
+		FamilyIncludeKeywords:  getListEnv("FAMILY_INCLUDE_KEYWORDS"),
+		FamilyExcludeKeywords:  getListEnv("FAMILY_EXCLUDE_KEYWORDS"),
+		PriorityOrganizers:     getListEnv("PRIORITY_ORGANIZERS"),
+	}
+
+	expiry, err := getDurationOrDefault("ICS_URL_EXPIRY", defaultICSURLExpiry)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ICSURLExpiry = expiry
+
+	maxEvents, err := getIntOrDefault("MAX_EVENTS_PER_DAY", 0)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MaxEventsPerDay = maxEvents
+
+	maxTitle, err := getIntOrDefault("MAX_TITLE_LENGTH", 0)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MaxTitleLength = maxTitle
+
+	maxLocation, err := getIntOrDefault("MAX_LOCATION_LENGTH", 0)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MaxLocationLength = maxLocation
+
+	mentionMap, err := getMapEnv("LINE_MENTION_MAP")
+	if err != nil {
+		return nil, err
+	}
+	cfg.MentionMap = mentionMap
+
+	attendeeNames, err := getMapEnv("ATTENDEE_NAMES")
+	if err != nil {
+		return nil, err
 	}
+	cfg.AttendeeNames = attendeeNames
+
+	anniversaries, err := getMapEnv("ANNIVERSARIES")
+	if err != nil {
+		return nil, err
+	}
+	cfg.Anniversaries = anniversaries
+
+	anniversaryDays, err := getIntOrDefault("ANNIVERSARY_DAYS", defaultAnniversaryDays)
+	if err != nil {
+		return nil, err
+	}
+	cfg.AnniversaryDays = anniversaryDays
+
+	splitDays, err := getBoolOrDefault("SPLIT_DAY_MESSAGES", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.SplitDayMessages = splitDays
+
+	plainText, err := getBoolOrDefault("PLAIN_TEXT", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.PlainText = plainText
+
+	notifyWhenEmpty, err := getBoolOrDefault("NOTIFY_WHEN_EMPTY", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.NotifyWhenEmpty = notifyWhenEmpty
+
+	auditLog, err := getBoolOrDefault("AUDIT_LOG", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.AuditLog = auditLog
+
+	deliveryCheck, err := getBoolOrDefault("DELIVERY_CHECK", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.DeliveryCheck = deliveryCheck
+
+	incrementalSync, err := getBoolOrDefault("INCREMENTAL_SYNC", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.IncrementalSync = incrementalSync
+
+	familyExcludeAllDay, err := getBoolOrDefault("FAMILY_EXCLUDE_ALL_DAY", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.FamilyExcludeAllDay = familyExcludeAllDay
+
+	notificationWriteBack, err := getBoolOrDefault("NOTIFICATION_WRITE_BACK", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.NotificationWriteBack = notificationWriteBack
+
+	auditRetention, err := getDurationOrDefault("AUDIT_RETENTION", defaultAuditRetention)
+	if err != nil {
+		return nil, err
+	}
+	cfg.AuditRetention = auditRetention
+
+	reminderLead, err := getDurationOrDefault("REMINDER_LEAD", defaultReminderLead)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ReminderLead = reminderLead
+
+	dedupWindow, err := getDurationOrDefault("DEDUP_WINDOW", 0)
+	if err != nil {
+		return nil, err
+	}
+	cfg.DedupWindow = dedupWindow
+
+	debugMirrorInterval, err := getDurationOrDefault("DEBUG_MIRROR_INTERVAL", defaultDebugMirrorInterval)
+	if err != nil {
+		return nil, err
+	}
+	cfg.DebugMirrorInterval = debugMirrorInterval
+
+	optionalSourceTimeout, err := getDurationOrDefault("OPTIONAL_SOURCE_TIMEOUT", defaultOptionalSourceTimeout)
+	if err != nil {
+		return nil, err
+	}
+	cfg.OptionalSourceTimeout = optionalSourceTimeout
+
+	timezone, err := getLocationOrDefault("TZ_NAME", dates.DefaultTimezone)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Timezone = timezone
+
+	auditRedact, err := getBoolOrDefault("AUDIT_REDACT", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.AuditRedact = auditRedact
+
+	dryRun, err := getBoolOrDefault("DRY_RUN", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.DryRun = dryRun
 
 	// 必須設定項目の確認
 	if cfg.GoogleCredentials == "" {
@@ -85,9 +421,142 @@ func loadAWSConfig() (*Config, error) {
 	ssmClient := ssm.NewFromConfig(awsConfig)
 
 	cfg := &Config{
-		CalendarID: getEnvOrDefault("CALENDAR_ID", "primary"),
-		LogLevel:   getEnvOrDefault("LOG_LEVEL", "INFO"),
-		ssmClient:  ssmClient,
+		CalendarID:             getEnvOrDefault("CALENDAR_ID", "primary"),
+		GitHubAPIBaseURL:       getEnvOrDefault("GITHUB_API_BASE_URL", ""),
+		GoogleCalendarEndpoint: getEnvOrDefault("GOOGLE_CALENDAR_ENDPOINT", ""),
+		LineAPIBaseURL:         getEnvOrDefault("LINE_API_BASE_URL", ""),
+		LogLevel:               getEnvOrDefault("LOG_LEVEL", "INFO"),
+		UserAgent:              getEnvOrDefault("HTTP_USER_AGENT", ""),
+		CABundlePath:           getEnvOrDefault("CA_BUNDLE_PATH", ""),
+		Schedules:              getEnvOrDefault("SCHEDULES", ""),
+		DebugMirrorTo:          getEnvOrDefault("DEBUG_MIRROR_TO", ""),
+		AttendeeNamesFile:      getEnvOrDefault("ATTENDEE_NAMES_FILE", ""),
+		ICSBucket:              getEnvOrDefault("ICS_BUCKET", ""),
+		ICSKeyPrefix:           getEnvOrDefault("ICS_KEY_PREFIX", defaultICSKeyPrefix),
+		EventBusName:           getEnvOrDefault("EVENT_BUS_NAME", ""),
+		FlashBriefingBucket:    getEnvOrDefault("FLASH_BRIEFING_BUCKET", ""),
+		FlashBriefingKey:       getEnvOrDefault("FLASH_BRIEFING_KEY", ""),
+		TemplateVariants:       getEnvOrDefault("TEMPLATE_VARIANTS", ""),
+		ReminderScheduleGroup:  getEnvOrDefault("REMINDER_SCHEDULE_GROUP", ""),
+		ReminderTargetARN:      getEnvOrDefault("REMINDER_TARGET_ARN", ""),
+		ReminderRoleARN:        getEnvOrDefault("REMINDER_ROLE_ARN", ""),
+		StoreBackend:           getEnvOrDefault("STORE_BACKEND", ""),
+		StoreTable:             getEnvOrDefault("STORE_TABLE", ""),
+		StorePath:              getEnvOrDefault("STORE_PATH", ""),
+		RedisURL:               getEnvOrDefault("REDIS_URL", ""),
+		FeatureFlags:           getListEnv("FEATURE_FLAGS"),
+		AppConfigFlagsURL:      getEnvOrDefault("APPCONFIG_FLAGS_URL", ""),
+		AllDayPlacement:        getEnvOrDefault("ALL_DAY_PLACEMENT", ""),
+		CategoryRules:          getEnvOrDefault("CATEGORY_RULES", ""),
+		TitleRewriteRules:      getEnvOrDefault("TITLE_REWRITE_RULES", ""),
+		EarlyWarningBefore:     getEnvOrDefault("EARLY_WARNING_BEFORE", ""),
+		OfficeKeywords:         getListEnv("OFFICE_KEYWORDS"),
+		OutOfOfficeMute:        getEnvOrDefault("OOO_MUTE", ""),
+		OutOfOfficeKeywords:    getListEnv("OOO_KEYWORDS"),
+		PriorityKeywords:       getListEnv("PRIORITY_KEYWORDS"),
+		MessageSections:        getListEnv("MESSAGE_SECTIONS"),
+		FamilyCalendarID:       getEnvOrDefault("FAMILY_CALENDAR_ID", ""),
+		FamilyIncludeKeywords:  getListEnv("FAMILY_INCLUDE_KEYWORDS"),
+		FamilyExcludeKeywords:  getListEnv("FAMILY_EXCLUDE_KEYWORDS"),
+		PriorityOrganizers:     getListEnv("PRIORITY_ORGANIZERS"),
+		ssmClient:              ssmClient,
+	}
+
+	cfg.ICSURLExpiry, err = getDurationOrDefault("ICS_URL_EXPIRY", defaultICSURLExpiry)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MaxEventsPerDay, err = getIntOrDefault("MAX_EVENTS_PER_DAY", 0)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MaxTitleLength, err = getIntOrDefault("MAX_TITLE_LENGTH", 0)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MaxLocationLength, err = getIntOrDefault("MAX_LOCATION_LENGTH", 0)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MentionMap, err = getMapEnv("LINE_MENTION_MAP")
+	if err != nil {
+		return nil, err
+	}
+	cfg.AttendeeNames, err = getMapEnv("ATTENDEE_NAMES")
+	if err != nil {
+		return nil, err
+	}
+	cfg.Anniversaries, err = getMapEnv("ANNIVERSARIES")
+	if err != nil {
+		return nil, err
+	}
+	cfg.AnniversaryDays, err = getIntOrDefault("ANNIVERSARY_DAYS", defaultAnniversaryDays)
+	if err != nil {
+		return nil, err
+	}
+	cfg.SplitDayMessages, err = getBoolOrDefault("SPLIT_DAY_MESSAGES", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.PlainText, err = getBoolOrDefault("PLAIN_TEXT", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.NotifyWhenEmpty, err = getBoolOrDefault("NOTIFY_WHEN_EMPTY", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.AuditLog, err = getBoolOrDefault("AUDIT_LOG", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.DeliveryCheck, err = getBoolOrDefault("DELIVERY_CHECK", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.IncrementalSync, err = getBoolOrDefault("INCREMENTAL_SYNC", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.FamilyExcludeAllDay, err = getBoolOrDefault("FAMILY_EXCLUDE_ALL_DAY", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.NotificationWriteBack, err = getBoolOrDefault("NOTIFICATION_WRITE_BACK", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.AuditRetention, err = getDurationOrDefault("AUDIT_RETENTION", defaultAuditRetention)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ReminderLead, err = getDurationOrDefault("REMINDER_LEAD", defaultReminderLead)
+	if err != nil {
+		return nil, err
+	}
+	cfg.DedupWindow, err = getDurationOrDefault("DEDUP_WINDOW", 0)
+	if err != nil {
+		return nil, err
+	}
+	cfg.DebugMirrorInterval, err = getDurationOrDefault("DEBUG_MIRROR_INTERVAL", defaultDebugMirrorInterval)
+	if err != nil {
+		return nil, err
+	}
+	cfg.OptionalSourceTimeout, err = getDurationOrDefault("OPTIONAL_SOURCE_TIMEOUT", defaultOptionalSourceTimeout)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Timezone, err = getLocationOrDefault("TZ_NAME", dates.DefaultTimezone)
+	if err != nil {
+		return nil, err
+	}
+	cfg.AuditRedact, err = getBoolOrDefault("AUDIT_REDACT", false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.DryRun, err = getBoolOrDefault("DRY_RUN", false)
+	if err != nil {
+		return nil, err
 	}
 
 	// Parameter Storeから機密情報を取得
@@ -145,6 +614,15 @@ func (cfg *Config) loadFromParameterStore() error {
 	cfg.CalendarID = calendarID
 	fmt.Printf("Calendar ID loaded: %s\n", cfg.CalendarID)
 
+	// GitHubトークンは締め切り表示を使う場合のみ取得する
+	if githubTokenParam := getEnvOrDefault("SSM_GITHUB_TOKEN_PARAM", ""); githubTokenParam != "" {
+		githubToken, err := cfg.getParameter(ctx, githubTokenParam, true) // SecureString用にwithDecryption=true
+		if err != nil {
+			return fmt.Errorf("GitHubトークンの取得に失敗しました: %v", err)
+		}
+		cfg.GitHubToken = githubToken
+	}
+
 	return nil
 }
 
@@ -175,6 +653,16 @@ func (cfg *Config) getParameter(ctx context.Context, paramName string, withDecry
 	return value, nil
 }
 
+// CalendarIDs 予定を取得するカレンダーのIDの一覧
+func (cfg *Config) CalendarIDs() []string {
+	return splitList(cfg.CalendarID)
+}
+
+// IsDebug ログレベルがDEBUGかどうか
+func (cfg *Config) IsDebug() bool {
+	return strings.EqualFold(cfg.LogLevel, "DEBUG")
+}
+
 // GetGoogleCredentialsJSON Google認証情報をJSONとして解析
 func (cfg *Config) GetGoogleCredentialsJSON() (map[string]interface{}, error) {
 	var credentials map[string]interface{}
@@ -191,3 +679,87 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getDurationOrDefault 環境変数を time.Duration として取得し、存在しない場合はデフォルト値を返す
+func getDurationOrDefault(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := getEnvOrDefault(key, "")
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s環境変数の値が不正です: %q", key, value)
+	}
+	return d, nil
+}
+
+// getIntOrDefault 環境変数を0以上の整数として取得し、存在しない場合はデフォルト値を返す
+func getIntOrDefault(key string, defaultValue int) (int, error) {
+	value := getEnvOrDefault(key, "")
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s環境変数の値が不正です: %q", key, value)
+	}
+	return n, nil
+}
+
+// getLocationOrDefault IANAのタイムゾーン名（"America/New_York"など）の環境変数を取得する
+func getLocationOrDefault(key, defaultValue string) (*time.Location, error) {
+	name := getEnvOrDefault(key, defaultValue)
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%sの値が不正です: %q（Asia/Tokyo のようなIANAのタイムゾーン名で指定してください）", key, name)
+	}
+	return loc, nil
+}
+
+// getListEnv カンマ区切りの環境変数をリストとして取得する（空要素は除外）
+func getListEnv(key string) []string {
+	return splitList(getEnvOrDefault(key, ""))
+}
+
+// splitList カンマ区切りの値を前後の空白を除いた一覧にする（空の要素は除く）
+func splitList(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getMapEnv "key1=value1,key2=value2" 形式の環境変数をmapとして取得する
+func getMapEnv(key string) (map[string]string, error) {
+	entries := getListEnv(key)
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	values := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		k, v, ok := strings.Cut(entry, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("%s環境変数の値が不正です: %q", key, entry)
+		}
+		values[k] = v
+	}
+	return values, nil
+}
+
+// getBoolOrDefault 環境変数を真偽値として取得し、存在しない場合はデフォルト値を返す
+func getBoolOrDefault(key string, defaultValue bool) (bool, error) {
+	value := getEnvOrDefault(key, "")
+	if value == "" {
+		return defaultValue, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s環境変数の値が不正です: %q", key, value)
+	}
+	return b, nil
+}
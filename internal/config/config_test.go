@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
@@ -62,6 +63,19 @@ func TestGetGoogleCredentialsJSON_Invalid(t *testing.T) {
 	assert.Contains(t, err.Error(), "Google認証情報のJSON解析に失敗しました")
 }
 
+// --- IsDebug テスト ---
+
+func TestIsDebug(t *testing.T) {
+	assert.True(t, (&Config{LogLevel: "DEBUG"}).IsDebug())
+	assert.True(t, (&Config{LogLevel: "debug"}).IsDebug())
+	assert.False(t, (&Config{LogLevel: "INFO"}).IsDebug())
+}
+
+func TestCalendarIDs(t *testing.T) {
+	assert.Equal(t, []string{"primary"}, (&Config{CalendarID: "primary"}).CalendarIDs())
+	assert.Equal(t, []string{"work@example.com", "family@example.com"}, (&Config{CalendarID: "work@example.com, family@example.com,"}).CalendarIDs())
+}
+
 // --- loadLocalConfig テスト ---
 
 func TestLoadLocalConfig_MissingRequired(t *testing.T) {
@@ -169,3 +183,97 @@ func TestLoadFromParameterStore(t *testing.T) {
 	assert.Equal(t, "calendar-id-value", cfg.CalendarID)
 	mockSSM.AssertExpectations(t)
 }
+
+func TestGetLocationOrDefault(t *testing.T) {
+	t.Setenv("TEST_TZ", "")
+	loc, err := getLocationOrDefault("TEST_TZ", "Asia/Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", loc.String())
+
+	t.Setenv("TEST_TZ", "Europe/London")
+	loc, err = getLocationOrDefault("TEST_TZ", "Asia/Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/London", loc.String())
+
+	t.Setenv("TEST_TZ", "Mars/Olympus")
+	_, err = getLocationOrDefault("TEST_TZ", "Asia/Tokyo")
+	assert.ErrorContains(t, err, "TEST_TZの値が不正です")
+}
+
+func TestGetDurationOrDefault(t *testing.T) {
+	t.Setenv("TEST_DURATION", "")
+	d, err := getDurationOrDefault("TEST_DURATION", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, d)
+
+	t.Setenv("TEST_DURATION", "90m")
+	d, err = getDurationOrDefault("TEST_DURATION", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, d)
+
+	for _, invalid := range []string{"abc", "-1h", "0s"} {
+		t.Setenv("TEST_DURATION", invalid)
+		_, err = getDurationOrDefault("TEST_DURATION", time.Hour)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestGetIntOrDefault(t *testing.T) {
+	t.Setenv("TEST_INT", "")
+	n, err := getIntOrDefault("TEST_INT", 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	t.Setenv("TEST_INT", "10")
+	n, err = getIntOrDefault("TEST_INT", 3)
+	require.NoError(t, err)
+	assert.Equal(t, 10, n)
+
+	for _, invalid := range []string{"abc", "-1", "1.5"} {
+		t.Setenv("TEST_INT", invalid)
+		_, err = getIntOrDefault("TEST_INT", 3)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestGetListEnv(t *testing.T) {
+	t.Setenv("TEST_LIST", " 面接, review ,,")
+	assert.Equal(t, []string{"面接", "review"}, getListEnv("TEST_LIST"))
+
+	t.Setenv("TEST_LIST", "")
+	assert.Empty(t, getListEnv("TEST_LIST"))
+}
+
+func TestGetMapEnv(t *testing.T) {
+	t.Setenv("TEST_MAP", "alice@example.com=U123, bob@example.com = U456")
+	values, err := getMapEnv("TEST_MAP")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"alice@example.com": "U123", "bob@example.com": "U456"}, values)
+
+	t.Setenv("TEST_MAP", "")
+	values, err = getMapEnv("TEST_MAP")
+	require.NoError(t, err)
+	assert.Nil(t, values)
+
+	for _, invalid := range []string{"alice@example.com", "=U123", "alice@example.com="} {
+		t.Setenv("TEST_MAP", invalid)
+		_, err = getMapEnv("TEST_MAP")
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestGetBoolOrDefault(t *testing.T) {
+	t.Setenv("TEST_BOOL", "")
+	b, err := getBoolOrDefault("TEST_BOOL", true)
+	require.NoError(t, err)
+	assert.True(t, b)
+
+	t.Setenv("TEST_BOOL", "false")
+	b, err = getBoolOrDefault("TEST_BOOL", true)
+	require.NoError(t, err)
+	assert.False(t, b)
+
+	t.Setenv("TEST_BOOL", "yes")
+	_, err = getBoolOrDefault("TEST_BOOL", false)
+	assert.Error(t, err)
+}
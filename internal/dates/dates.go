@@ -0,0 +1,76 @@
+package dates
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimezone 設定がない場合に日付の計算・表示に使うタイムゾーン
+const DefaultTimezone = "Asia/Tokyo"
+
+// location 日付の計算・表示に使うタイムゾーン
+var location atomic.Pointer[time.Location]
+
+// Location 日付の計算・表示に使うタイムゾーン（SetLocationで設定しない場合はDefaultTimezone）
+func Location() *time.Location {
+	if loc := location.Load(); loc != nil {
+		return loc
+	}
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		// タイムゾーンデータベースがない環境でも日本時間で動作させる
+		return time.FixedZone("JST", 9*60*60)
+	}
+	return loc
+}
+
+// SetLocation 日付の計算・表示に使うタイムゾーンを設定する（起動時に設定の値で呼び出す）
+func SetLocation(loc *time.Location) {
+	location.Store(loc)
+}
+
+// StartOfDay 指定時刻をそのタイムゾーンにおける当日0時に正規化
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// AddDays 指定時刻のn日後の0時を返す
+// 24時間単位の加算と異なり、夏時間の切り替えで1日が23時間・25時間になる日でも日付がずれない
+func AddDays(t time.Time, n int) time.Time {
+	return StartOfDay(t.AddDate(0, 0, n))
+}
+
+// Today 現在時刻をlocに変換した当日0時を返す
+func Today(now time.Time, loc *time.Location) time.Time {
+	return StartOfDay(now.In(loc))
+}
+
+// DaysBetween fromの日付からtoの日付までの日数（カレンダー上の日付の差）
+// 夏時間の切り替えを挟んでも、経過時間ではなく日付で数える
+func DaysBetween(from, to time.Time) int {
+	f := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	t := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(t.Sub(f).Hours() / 24)
+}
+
+// ParseDate "2006-01-02" 形式の日付をlocにおける当日0時として解析する
+// UTCで解析してからlocに変換すると、UTCより西のタイムゾーンでは前日になってしまう
+func ParseDate(s string, loc *time.Location) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", s, loc)
+}
+
+// ParseClock "08:30" 形式の時刻を0時からの経過時間として解析する
+func ParseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("時刻の形式が不正です (HH:MM): %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// SinceStartOfDay 指定時刻のそのタイムゾーンにおける0時からの経過時間
+// 時計の表示上の時刻から算出するため、夏時間の切り替え日でもParseClockの値とそのまま比較できる
+func SinceStartOfDay(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
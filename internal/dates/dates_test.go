@@ -0,0 +1,132 @@
+package dates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartOfDay(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	input := time.Date(2024, 1, 15, 23, 59, 59, 999, jst)
+
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, jst), StartOfDay(input))
+}
+
+func TestToday_ConvertsToLocation(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	// UTCで前日15:00 = JSTで当日0:00
+	now := time.Date(2024, 1, 14, 15, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, jst), Today(now, jst))
+}
+
+func TestAddDays(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name     string
+		input    time.Time
+		days     int
+		expected time.Time
+	}{
+		{"翌日", time.Date(2024, 1, 15, 0, 0, 0, 0, jst), 1, time.Date(2024, 1, 16, 0, 0, 0, 0, jst)},
+		{"月末", time.Date(2024, 1, 31, 0, 0, 0, 0, jst), 1, time.Date(2024, 2, 1, 0, 0, 0, 0, jst)},
+		{"年末", time.Date(2024, 12, 31, 0, 0, 0, 0, jst), 1, time.Date(2025, 1, 1, 0, 0, 0, 0, jst)},
+		{"うるう日", time.Date(2024, 2, 28, 0, 0, 0, 0, jst), 1, time.Date(2024, 2, 29, 0, 0, 0, 0, jst)},
+		{"時刻付きは0時に正規化", time.Date(2024, 1, 15, 10, 30, 0, 0, jst), 1, time.Date(2024, 1, 16, 0, 0, 0, 0, jst)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, AddDays(tt.input, tt.days))
+		})
+	}
+}
+
+func TestAddDays_AcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// 2024/3/10 は夏時間開始で23時間しかない
+	springForward := time.Date(2024, 3, 10, 0, 0, 0, 0, ny)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, ny), AddDays(springForward, 1))
+	assert.Equal(t, 1, springForward.Add(24*time.Hour).Hour(), "24時間加算では翌日1時になってしまう")
+
+	// 2024/11/3 は夏時間終了で25時間ある
+	fallBack := time.Date(2024, 11, 3, 0, 0, 0, 0, ny)
+	assert.Equal(t, time.Date(2024, 11, 4, 0, 0, 0, 0, ny), AddDays(fallBack, 1))
+	assert.Equal(t, 3, fallBack.Add(24*time.Hour).Day(), "24時間加算では同じ日に留まる")
+}
+
+func TestParseDate(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	got, err := ParseDate("2024-03-10", ny)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, ny), got)
+
+	// UTCで解析してから変換すると前日になる
+	utc, err := time.Parse("2006-01-02", "2024-03-10")
+	require.NoError(t, err)
+	assert.Equal(t, 9, utc.In(ny).Day())
+
+	_, err = ParseDate("2024/03/10", ny)
+	assert.Error(t, err)
+}
+
+func TestParseClock(t *testing.T) {
+	d, err := ParseClock("08:30")
+	require.NoError(t, err)
+	assert.Equal(t, 8*time.Hour+30*time.Minute, d)
+
+	for _, invalid := range []string{"", "8時", "25:00", "08:60"} {
+		_, err := ParseClock(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestSinceStartOfDay(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	assert.Equal(t, 8*time.Hour+30*time.Minute, SinceStartOfDay(time.Date(2024, 1, 15, 8, 30, 0, 0, jst)))
+}
+
+func TestSinceStartOfDay_AcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// 夏時間開始日の8:30は0時から7時間30分しか経過していないが、表示上の時刻で比較する
+	morning := time.Date(2024, 3, 10, 8, 30, 0, 0, ny)
+	assert.Equal(t, 8*time.Hour+30*time.Minute, SinceStartOfDay(morning))
+	assert.Equal(t, 7*time.Hour+30*time.Minute, morning.Sub(StartOfDay(morning)))
+}
+
+func TestDaysBetween(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, DaysBetween(time.Date(2024, 1, 15, 23, 0, 0, 0, ny), time.Date(2024, 1, 15, 1, 0, 0, 0, ny)))
+	assert.Equal(t, 1, DaysBetween(time.Date(2024, 3, 9, 0, 0, 0, 0, ny), time.Date(2024, 3, 10, 0, 0, 0, 0, ny)))
+	assert.Equal(t, 366, DaysBetween(time.Date(2024, 1, 1, 0, 0, 0, 0, ny), time.Date(2025, 1, 1, 0, 0, 0, 0, ny)))
+	assert.Equal(t, -1, DaysBetween(time.Date(2024, 1, 2, 0, 0, 0, 0, ny), time.Date(2024, 1, 1, 0, 0, 0, 0, ny)))
+}
+
+func TestLocation(t *testing.T) {
+	t.Cleanup(func() { SetLocation(nil) })
+
+	assert.Equal(t, DefaultTimezone, Location().String())
+
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	SetLocation(ny)
+	assert.Equal(t, ny, Location())
+	// 日本時間では翌日でも、設定したタイムゾーンの日付で今日を判定する
+	now := time.Date(2024, 1, 15, 20, 0, 0, 0, ny)
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, ny), Today(now, Location()))
+
+	SetLocation(nil)
+	assert.Equal(t, DefaultTimezone, Location().String())
+}
@@ -11,4 +11,59 @@ type Event struct {
 	IsAllDay    bool
 	Location    string
 	Description string
+
+	// ConferenceURL カレンダーに登録されたビデオ会議の参加URL（Google Meetなど）
+	ConferenceURL string
+	// IsOnline ビデオ会議の参加URLがある（場所・説明文に記載されたものも含む）
+	IsOnline bool
+
+	// AttendeeCount 参加者数（取得元が参加者情報を持たない場合は0）
+	AttendeeCount int
+	// Attendees 参加者のメールアドレス（会議室などのリソースは含まない）
+	Attendees []string
+	// Organizer 主催者のメールアドレス
+	Organizer string
+	// AttendeeNames 表示名が設定されている参加者の表示名（設定がない場合は空）
+	AttendeeNames []string
+
+	// Importance 予定の重要度スコア（大きいほど重要、scoringパッケージで算出）
+	Importance int
+
+	// Source イベントの取得元を示すラベル（例: "Google", "GitHub"）
+	Source string
+
+	// WorkingLocation 勤務場所を表す予定の場合の種別（勤務場所の予定でなければ空）
+	WorkingLocation WorkingLocation
+
+	// Reminders 予定に設定されたリマインダー（開始の何分前に通知するか、未設定の場合は空）
+	Reminders []time.Duration
+
+	// IsOutOfOffice 不在（休暇など）を表す予定かどうか
+	IsOutOfOffice bool
+
+	// IsDeadline 締め切り（GitHubのマイルストーン期限など）として扱うかどうか
+	IsDeadline bool
+
+	// IsFamily 家族（パートナー）のカレンダーの予定として別のセクションに表示するかどうか
+	IsFamily bool
+
+	// CalendarID 予定が登録されているカレンダーのID（Googleカレンダー以外の取得元は空）
+	CalendarID string
+	// NotifiedOn 通知済みとしてカレンダーに記録された日（記録がない場合はゼロ値）
+	// 同じカレンダーを共有する他の通知インスタンスが記録した場合も含む
+	NotifiedOn time.Time
+	// NotifiedBy NotifiedOnを記録した通知インスタンスの識別子
+	NotifiedBy string
 }
+
+// WorkingLocation 勤務場所の種別
+type WorkingLocation string
+
+const (
+	// WorkingLocationOffice オフィス勤務
+	WorkingLocationOffice WorkingLocation = "office"
+	// WorkingLocationHome 在宅勤務
+	WorkingLocationHome WorkingLocation = "home"
+	// WorkingLocationCustom その他の場所での勤務
+	WorkingLocationCustom WorkingLocation = "custom"
+)
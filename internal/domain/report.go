@@ -0,0 +1,80 @@
+package domain
+
+import "time"
+
+// DaySchedule 1日分の予定
+type DaySchedule struct {
+	// Date 対象日（通知のタイムゾーンの0時）
+	Date   time.Time
+	Events []Event
+}
+
+// DayStats 1日分の予定の件数
+type DayStats struct {
+	// Total 締め切りを除く予定の件数
+	Total int
+	// AllDay 終日の予定の件数
+	AllDay int
+	// Deadlines 締め切りの件数
+	Deadlines int
+}
+
+// Stats 予定の件数を集計
+func (d DaySchedule) Stats() DayStats {
+	var stats DayStats
+	for _, event := range d.Events {
+		switch {
+		case event.IsDeadline:
+			stats.Deadlines++
+		case event.IsAllDay:
+			stats.Total++
+			stats.AllDay++
+		default:
+			stats.Total++
+		}
+	}
+	return stats
+}
+
+// WarningKind 警告の種類
+type WarningKind string
+
+// WarningEarlyOffice 出社日の最初の予定が早い時間に始まる
+const WarningEarlyOffice WarningKind = "early_office"
+
+// Warning 通知の先頭で知らせる注意事項
+type Warning struct {
+	Kind WarningKind
+	// Event 警告の対象の予定
+	Event Event
+}
+
+// ScheduleReport 通知する予定をまとめた集約
+// ユースケースが取得結果から作成し、各通知先・出力形式はこれをもとに表示を組み立てる
+type ScheduleReport struct {
+	// GeneratedAt レポートの作成時刻
+	GeneratedAt time.Time
+	Today       DaySchedule
+	Tomorrow    DaySchedule
+	// Warnings ユースケースが予定から判定した注意事項
+	Warnings []Warning
+}
+
+// NewScheduleReport 今日と明日の予定からScheduleReportを作成
+func NewScheduleReport(generatedAt, today, tomorrow time.Time, todayEvents, tomorrowEvents []Event) ScheduleReport {
+	return ScheduleReport{
+		GeneratedAt: generatedAt,
+		Today:       DaySchedule{Date: today, Events: todayEvents},
+		Tomorrow:    DaySchedule{Date: tomorrow, Events: tomorrowEvents},
+	}
+}
+
+// Days 対象日を日付順に返す
+func (r ScheduleReport) Days() []DaySchedule {
+	return []DaySchedule{r.Today, r.Tomorrow}
+}
+
+// IsEmpty 両日とも予定がないかどうか
+func (r ScheduleReport) IsEmpty() bool {
+	return len(r.Today.Events) == 0 && len(r.Tomorrow.Events) == 0
+}
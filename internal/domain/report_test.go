@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDaySchedule_Stats(t *testing.T) {
+	day := DaySchedule{Events: []Event{
+		{Title: "朝会"},
+		{Title: "休暇", IsAllDay: true},
+		{Title: "提出", IsDeadline: true},
+		{Title: "終日の締め切り", IsAllDay: true, IsDeadline: true},
+	}}
+
+	assert.Equal(t, DayStats{Total: 2, AllDay: 1, Deadlines: 2}, day.Stats())
+}
+
+func TestScheduleReport(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	today := time.Date(2024, 12, 31, 0, 0, 0, 0, jst)
+	tomorrow := time.Date(2025, 1, 1, 0, 0, 0, 0, jst)
+
+	empty := NewScheduleReport(today, today, tomorrow, nil, []Event{})
+	assert.True(t, empty.IsEmpty())
+
+	report := NewScheduleReport(today, today, tomorrow, nil, []Event{{Title: "初詣"}})
+	assert.False(t, report.IsEmpty())
+	days := report.Days()
+	assert.Equal(t, today, days[0].Date)
+	assert.Equal(t, tomorrow, days[1].Date)
+	assert.Equal(t, "初詣", days[1].Events[0].Title)
+}
@@ -0,0 +1,94 @@
+// Package experiment は通知メッセージのテンプレートを重み付きで切り替えるA/Bテストを提供する
+// 各バリアントはフィーチャーフラグの組み合わせで表し、選ばれたバリアント名は送信ログに残す
+package experiment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/flags"
+)
+
+// Variant テンプレートのバリアント
+type Variant struct {
+	Name string `json:"name"`
+	// Weight 選ばれる比率（0以下のバリアントは選ばれない）
+	Weight int `json:"weight"`
+	// Flags このバリアントで有効にするフィーチャーフラグ（"-"を付けると無効）
+	Flags []string `json:"flags,omitempty"`
+}
+
+// ParseVariants [{"name":"control","weight":3},{"name":"plain","weight":1,"flags":["plain_text"]}] 形式のJSONからバリアントを解析する
+func ParseVariants(s string) ([]Variant, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var variants []Variant
+	if err := json.Unmarshal([]byte(s), &variants); err != nil {
+		return nil, fmt.Errorf("テンプレートのバリアントのJSON解析に失敗しました: %v", err)
+	}
+
+	names := make(map[string]bool, len(variants))
+	for _, v := range variants {
+		if v.Name == "" {
+			return nil, fmt.Errorf("テンプレートのバリアントの名前が空です")
+		}
+		if names[v.Name] {
+			return nil, fmt.Errorf("テンプレートのバリアント名が重複しています: %s", v.Name)
+		}
+		names[v.Name] = true
+	}
+	return variants, nil
+}
+
+// Choose keyに応じて重みの比率でバリアントを1つ選ぶ
+// 同じkeyには常に同じバリアントを返す（送信先と日付をkeyにすると、その日の再実行でも表示が変わらない）
+// 選べるバリアントがない場合はfalseを返す
+func Choose(variants []Variant, key string) (Variant, bool) {
+	total := 0
+	for _, v := range variants {
+		if v.Weight > 0 {
+			total += v.Weight
+		}
+	}
+	if total == 0 {
+		return Variant{}, false
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	point := int(h.Sum32() % uint32(total))
+	for _, v := range variants {
+		if v.Weight <= 0 {
+			continue
+		}
+		if point < v.Weight {
+			return v, true
+		}
+		point -= v.Weight
+	}
+	return Variant{}, false
+}
+
+// FlagSet バリアントで有効・無効にするフィーチャーフラグ
+func (v Variant) FlagSet() *flags.Flags {
+	return flags.Parse(v.Flags)
+}
+
+// contextKey contextに保存する際のキー
+type contextKey struct{}
+
+// WithVariant 選ばれたバリアント名を保存したcontextを返す
+func WithVariant(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, contextKey{}, name)
+}
+
+// FromContext contextに保存されたバリアント名を返す（未設定の場合は空文字）
+func FromContext(ctx context.Context) string {
+	name, _ := ctx.Value(contextKey{}).(string)
+	return name
+}
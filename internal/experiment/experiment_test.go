@@ -0,0 +1,71 @@
+package experiment
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVariants(t *testing.T) {
+	variants, err := ParseVariants(`[{"name":"control","weight":3},{"name":"plain","weight":1,"flags":["plain_text","-split_day_messages"]}]`)
+	require.NoError(t, err)
+	require.Len(t, variants, 2)
+	assert.Equal(t, Variant{Name: "control", Weight: 3}, variants[0])
+	assert.True(t, variants[1].FlagSet().Enabled("plain_text"))
+	assert.False(t, variants[1].FlagSet().Enabled("split_day_messages"))
+
+	variants, err = ParseVariants(" ")
+	require.NoError(t, err)
+	assert.Empty(t, variants)
+}
+
+func TestParseVariants_Invalid(t *testing.T) {
+	for _, input := range []string{
+		`{"name":"control"}`,
+		`[{"weight":1}]`,
+		`[{"name":"a","weight":1},{"name":"a","weight":2}]`,
+	} {
+		_, err := ParseVariants(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestChoose_Deterministic(t *testing.T) {
+	variants := []Variant{{Name: "control", Weight: 1}, {Name: "plain", Weight: 1}}
+
+	first, ok := Choose(variants, "U123:2024-01-15")
+	require.True(t, ok)
+	for i := 0; i < 10; i++ {
+		again, _ := Choose(variants, "U123:2024-01-15")
+		assert.Equal(t, first, again)
+	}
+}
+
+func TestChoose_Weights(t *testing.T) {
+	variants := []Variant{{Name: "control", Weight: 3}, {Name: "plain", Weight: 1}, {Name: "disabled", Weight: 0}}
+
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		v, ok := Choose(variants, fmt.Sprintf("U%d:2024-01-15", i))
+		require.True(t, ok)
+		counts[v.Name]++
+	}
+	assert.Zero(t, counts["disabled"])
+	assert.InDelta(t, 3000, counts["control"], 200)
+	assert.InDelta(t, 1000, counts["plain"], 200)
+}
+
+func TestChoose_NoVariants(t *testing.T) {
+	_, ok := Choose(nil, "key")
+	assert.False(t, ok)
+	_, ok = Choose([]Variant{{Name: "off", Weight: 0}}, "key")
+	assert.False(t, ok)
+}
+
+func TestWithVariant(t *testing.T) {
+	assert.Empty(t, FromContext(context.Background()))
+	assert.Equal(t, "plain", FromContext(WithVariant(context.Background(), "plain")))
+}
@@ -0,0 +1,315 @@
+package fakes
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// CalendarServer Google Calendar APIのEvents.ListとOAuthトークン発行を模倣するテスト用サーバー
+// syncTokenを指定した場合は、トークンの発行以降に追加・変更・キャンセルされたイベントを返す
+type CalendarServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	events      map[string][]*calendar.Event
+	pageSize    int
+	failStatus  int
+	listCalls   int
+	syncCalls   int
+	notModified int
+	location    *time.Location
+
+	// seq イベントを変更するたびに増やす連番（syncTokenはこの値を表す）
+	seq int
+	// changedAt イベントを最後に変更したときのseq
+	changedAt map[*calendar.Event]int
+	// minSyncSeq これより前に発行したsyncTokenは期限切れ(410 Gone)として扱う
+	minSyncSeq int
+}
+
+// NewCalendarServer Google Calendarの偽サーバーを起動する（テスト終了時に自動で停止）
+func NewCalendarServer(t testing.TB) *CalendarServer {
+	t.Helper()
+
+	s := &CalendarServer{
+		events:    make(map[string][]*calendar.Event),
+		pageSize:  250,
+		location:  time.FixedZone("JST", 9*60*60),
+		changedAt: make(map[*calendar.Event]int),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", s.handleToken)
+	mux.HandleFunc("GET /calendar/v3/calendars/{calendarID}/events", s.handleListEvents)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Endpoint calendar.NewServiceに渡すAPIエンドポイント
+func (s *CalendarServer) Endpoint() string {
+	return s.URL + "/calendar/v3/"
+}
+
+// AddEvents カレンダーにイベントを追加する
+func (s *CalendarServer) AddEvents(calendarID string, events ...*calendar.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events[calendarID] = append(s.events[calendarID], events...)
+	for _, event := range events {
+		s.seq++
+		s.changedAt[event] = s.seq
+	}
+}
+
+// UpdateEvent 同じIDのイベントを置き換える（存在しない場合は追加する）
+func (s *CalendarServer) UpdateEvent(calendarID string, event *calendar.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	s.changedAt[event] = s.seq
+	for i, existing := range s.events[calendarID] {
+		if existing.Id == event.Id {
+			delete(s.changedAt, existing)
+			s.events[calendarID][i] = event
+			return
+		}
+	}
+	s.events[calendarID] = append(s.events[calendarID], event)
+}
+
+// CancelEvent イベントをキャンセル済みにする（showDeletedまたはsyncTokenを指定した場合のみ返す）
+func (s *CalendarServer) CancelEvent(calendarID, eventID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range s.events[calendarID] {
+		if event.Id == eventID {
+			s.seq++
+			s.changedAt[event] = s.seq
+			event.Status = "cancelled"
+		}
+	}
+}
+
+// ExpireSyncTokens これまでに発行したsyncTokenを期限切れにする（以降の差分取得は410 Goneになる）
+func (s *CalendarServer) ExpireSyncTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.minSyncSeq = s.seq + 1
+}
+
+// SetPageSize 1ページあたりの件数を設定する（maxResultsより小さい場合に優先）
+func (s *CalendarServer) SetPageSize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageSize = size
+}
+
+// FailWith 以降のEvents.Listを指定したステータスで失敗させる
+func (s *CalendarServer) FailWith(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failStatus = status
+}
+
+// ListCalls Events.Listが呼ばれた回数を返す
+func (s *CalendarServer) ListCalls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listCalls
+}
+
+// SyncCalls syncTokenを指定したEvents.List（差分取得）が呼ばれた回数を返す
+func (s *CalendarServer) SyncCalls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.syncCalls
+}
+
+// NotModifiedCount If-None-Matchに一致して304を返した回数を返す
+func (s *CalendarServer) NotModifiedCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.notModified
+}
+
+// ServiceAccountJSON このサーバーをトークン発行先とするサービスアカウント認証情報を生成する
+func (s *CalendarServer) ServiceAccountJSON(t testing.TB) []byte {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("RSA鍵の生成に失敗しました: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: mustMarshalPKCS8(t, key),
+	})
+
+	creds, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "fake-project",
+		"private_key_id": "fake-key-id",
+		"private_key":    string(keyPEM),
+		"client_email":   "notifier@fake-project.iam.gserviceaccount.com",
+		"client_id":      "1234567890",
+		"token_uri":      s.URL + "/token",
+	})
+	if err != nil {
+		t.Fatalf("認証情報のJSON変換に失敗しました: %v", err)
+	}
+	return creds
+}
+
+// mustMarshalPKCS8 秘密鍵をPKCS#8形式に変換する
+func mustMarshalPKCS8(t testing.TB, key *rsa.PrivateKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("秘密鍵の変換に失敗しました: %v", err)
+	}
+	return der
+}
+
+// handleToken JWTグラントに対してアクセストークンを発行する
+func (s *CalendarServer) handleToken(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "fake-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	})
+}
+
+// handleListEvents timeMin/timeMax（またはsyncToken）で絞り込んだイベントをページングして返す
+func (s *CalendarServer) handleListEvents(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listCalls++
+
+	if s.failStatus != 0 {
+		writeJSON(w, s.failStatus, map[string]any{
+			"error": map[string]any{
+				"code":    s.failStatus,
+				"message": http.StatusText(s.failStatus),
+			},
+		})
+		return
+	}
+
+	query := r.URL.Query()
+	var matched []*calendar.Event
+	if syncToken := query.Get("syncToken"); syncToken != "" {
+		s.syncCalls++
+		// syncTokenは期間の指定と併用できない
+		if query.Has("timeMin") || query.Has("timeMax") {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": map[string]any{"code": 400, "message": "Bad Request"}})
+			return
+		}
+		since, err := strconv.Atoi(strings.TrimPrefix(syncToken, "sync-"))
+		if err != nil || since < s.minSyncSeq {
+			writeJSON(w, http.StatusGone, map[string]any{"error": map[string]any{"code": 410, "message": "Sync token is no longer valid, a full sync is required."}})
+			return
+		}
+
+		// トークンの発行以降に変更されたイベントを抽出（キャンセル済みを含む）
+		for _, event := range s.events[r.PathValue("calendarID")] {
+			if s.changedAt[event] > since {
+				matched = append(matched, event)
+			}
+		}
+	} else {
+		// 期間の指定は任意（省略した場合は制限しない）
+		timeMin, timeMax := time.Time{}, time.Unix(1<<62, 0)
+		var err error
+		if query.Has("timeMin") {
+			if timeMin, err = time.Parse(time.RFC3339, query.Get("timeMin")); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]any{"error": map[string]any{"code": 400, "message": "Bad Request"}})
+				return
+			}
+		}
+		if query.Has("timeMax") {
+			if timeMax, err = time.Parse(time.RFC3339, query.Get("timeMax")); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]any{"error": map[string]any{"code": 400, "message": "Bad Request"}})
+				return
+			}
+		}
+
+		// 期間と重なるイベントを抽出（キャンセル済みはshowDeletedを指定した場合のみ）
+		showDeleted := query.Get("showDeleted") == "true"
+		for _, event := range s.events[r.PathValue("calendarID")] {
+			if event.Status == "cancelled" && !showDeleted {
+				continue
+			}
+			start, end := parseEventDateTime(event.Start, s.location), parseEventDateTime(event.End, s.location)
+			if start.Before(timeMax) && end.After(timeMin) {
+				matched = append(matched, event)
+			}
+		}
+	}
+
+	pageSize := s.pageSize
+	if maxResults, err := strconv.Atoi(query.Get("maxResults")); err == nil && maxResults < pageSize {
+		pageSize = maxResults
+	}
+	offset, _ := strconv.Atoi(query.Get("pageToken"))
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	end := offset + pageSize
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	resp := &calendar.Events{
+		Kind:  "calendar#events",
+		Items: matched[offset:end],
+	}
+	if end < len(matched) {
+		resp.NextPageToken = strconv.Itoa(end)
+	} else {
+		// 最後のページには次回の差分取得に使うsyncTokenを付ける
+		resp.NextSyncToken = "sync-" + strconv.Itoa(s.seq)
+	}
+
+	// レスポンス内容からETagを算出し、変更がなければ304を返す
+	resp.Etag = etagOf(resp)
+	if r.Header.Get("If-None-Match") == resp.Etag {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// parseEventDateTime EventDateTimeを時刻に変換する（終日イベントはカレンダーのタイムゾーンの0時として扱う）
+func parseEventDateTime(dt *calendar.EventDateTime, loc *time.Location) time.Time {
+	if dt == nil {
+		return time.Time{}
+	}
+	if dt.DateTime != "" {
+		t, _ := time.Parse(time.RFC3339, dt.DateTime)
+		return t
+	}
+	t, _ := time.ParseInLocation("2006-01-02", dt.Date, loc)
+	return t
+}
+
+// etagOf レスポンス内容のハッシュからETagを生成する
+func etagOf(events *calendar.Events) string {
+	data, _ := json.Marshal(events.Items)
+	sum := sha256.Sum256(append(data, events.NextPageToken...))
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
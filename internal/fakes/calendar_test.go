@@ -0,0 +1,53 @@
+package fakes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/calendar/v3"
+)
+
+// listEvents 偽サーバーのEvents.Listを直接呼び出すヘルパー
+func listEvents(t *testing.T, s *CalendarServer, pageToken string) *calendar.Events {
+	t.Helper()
+
+	query := url.Values{
+		"timeMin":   {"2024-01-15T00:00:00+09:00"},
+		"timeMax":   {"2024-01-16T00:00:00+09:00"},
+		"pageToken": {pageToken},
+	}
+	resp, err := http.Get(s.Endpoint() + "calendars/primary/events?" + query.Encode())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var events calendar.Events
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&events))
+	return &events
+}
+
+func TestCalendarServer_Paging(t *testing.T) {
+	s := NewCalendarServer(t)
+	s.SetPageSize(2)
+	s.AddEvents("primary",
+		&calendar.Event{Id: "1", Start: &calendar.EventDateTime{DateTime: "2024-01-15T09:00:00+09:00"}, End: &calendar.EventDateTime{DateTime: "2024-01-15T10:00:00+09:00"}},
+		&calendar.Event{Id: "2", Start: &calendar.EventDateTime{Date: "2024-01-15"}, End: &calendar.EventDateTime{Date: "2024-01-16"}},
+		&calendar.Event{Id: "3", Start: &calendar.EventDateTime{DateTime: "2024-01-15T18:00:00+09:00"}, End: &calendar.EventDateTime{DateTime: "2024-01-15T19:00:00+09:00"}},
+		// 期間外のイベント
+		&calendar.Event{Id: "4", Start: &calendar.EventDateTime{Date: "2024-01-16"}, End: &calendar.EventDateTime{Date: "2024-01-17"}},
+	)
+
+	first := listEvents(t, s, "")
+	assert.Len(t, first.Items, 2)
+	require.NotEmpty(t, first.NextPageToken)
+
+	second := listEvents(t, s, first.NextPageToken)
+	require.Len(t, second.Items, 1)
+	assert.Equal(t, "3", second.Items[0].Id)
+	assert.Empty(t, second.NextPageToken)
+	assert.Equal(t, 2, s.ListCalls())
+}
@@ -0,0 +1,101 @@
+package fakes
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// LINEMessage 受信したメッセージオブジェクト
+type LINEMessage struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// LINERequest LINEサーバーが受信したリクエスト
+type LINERequest struct {
+	Path          string
+	Authorization string
+	To            string        `json:"to"`
+	ReplyToken    string        `json:"replyToken"`
+	Messages      []LINEMessage `json:"messages"`
+}
+
+// LINEServer LINE Messaging APIのpush/replyを模倣するテスト用サーバー
+type LINEServer struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	requests   []LINERequest
+	failStatus int
+	failBody   string
+}
+
+// NewLINEServer LINEの偽サーバーを起動する（テスト終了時に自動で停止）
+func NewLINEServer(t testing.TB) *LINEServer {
+	t.Helper()
+
+	s := &LINEServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v2/bot/message/push", s.handleMessage)
+	mux.HandleFunc("POST /v2/bot/message/reply", s.handleMessage)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// FailWith 以降のリクエストを指定したステータスとボディで失敗させる
+func (s *LINEServer) FailWith(status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failStatus = status
+	s.failBody = body
+}
+
+// Requests 受信したリクエストの一覧を返す
+func (s *LINEServer) Requests() []LINERequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]LINERequest(nil), s.requests...)
+}
+
+// handleMessage push/replyリクエストを記録して応答する
+func (s *LINEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "Authentication failed. Confirm that the access token in the authorization header is valid."})
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		return
+	}
+	req := LINERequest{Path: r.URL.Path, Authorization: r.Header.Get("Authorization")}
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "The request body has 1 error(s)"})
+		return
+	}
+	s.requests = append(s.requests, req)
+
+	if s.failStatus != 0 {
+		w.WriteHeader(s.failStatus)
+		_, _ = w.Write([]byte(s.failBody))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+// writeJSON JSONレスポンスを書き込む
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,23 @@
+package fakes
+
+import (
+	"context"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+// CalendarRepository 固定の予定またはエラーを返すテスト用の取得元（usecase.CalendarRepositoryを満たす）
+type CalendarRepository struct {
+	Events []domain.Event
+	Err    error
+}
+
+// GetEvents 日付にかかわらず固定の予定を返す
+func (r CalendarRepository) GetEvents(_ context.Context, _ time.Time) ([]domain.Event, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	// 呼び出し側での変更が元データに影響しないようコピーを返す
+	return append([]domain.Event(nil), r.Events...), nil
+}
@@ -0,0 +1,107 @@
+// Package flags は新しい表示形式などを段階的に有効化するためのフィーチャーフラグを提供する
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Flags 有効・無効を切り替えるフィーチャーフラグの集合
+// nilのFlagsはすべてのフラグを無効として扱う
+type Flags struct {
+	enabled map[string]bool
+}
+
+// New フラグ名と有効・無効の対応からFlagsを作成
+func New(enabled map[string]bool) *Flags {
+	copied := make(map[string]bool, len(enabled))
+	for name, on := range enabled {
+		copied[name] = on
+	}
+	return &Flags{enabled: copied}
+}
+
+// Parse フラグ名の一覧からFlagsを作成
+// 先頭に"-"を付けたフラグは明示的に無効にする（例: "flex_messages,-plain_text"）
+func Parse(names []string) *Flags {
+	enabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if strings.HasPrefix(name, "-") {
+			enabled[strings.TrimPrefix(name, "-")] = false
+			continue
+		}
+		enabled[name] = true
+	}
+	return &Flags{enabled: enabled}
+}
+
+// Enabled フラグが有効かどうかを返す（未定義のフラグは無効）
+func (f *Flags) Enabled(name string) bool {
+	if f == nil {
+		return false
+	}
+	return f.enabled[name]
+}
+
+// Merge otherで定義されたフラグで上書きした新しいFlagsを返す
+func (f *Flags) Merge(other *Flags) *Flags {
+	merged := New(nil)
+	if f != nil {
+		for name, on := range f.enabled {
+			merged.enabled[name] = on
+		}
+	}
+	if other != nil {
+		for name, on := range other.enabled {
+			merged.enabled[name] = on
+		}
+	}
+	return merged
+}
+
+// appConfigFlag AppConfigのフィーチャーフラグ形式の値
+type appConfigFlag struct {
+	Enabled bool `json:"enabled"`
+}
+
+// LoadAppConfig AWS AppConfig Lambda拡張機能のエンドポイントからフラグを取得
+// urlは http://localhost:2772/applications/<app>/environments/<env>/configurations/<profile> の形式
+func LoadAppConfig(ctx context.Context, client *http.Client, url string) (*Flags, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("AppConfigからのフラグ取得に失敗しました: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("AppConfigのレスポンス読み込みに失敗しました: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("AppConfigからのフラグ取得に失敗しました (Status: %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var values map[string]appConfigFlag
+	if err := json.Unmarshal(body, &values); err != nil {
+		return nil, fmt.Errorf("AppConfigのフラグを解析できませんでした: %v", err)
+	}
+
+	enabled := make(map[string]bool, len(values))
+	for name, value := range values {
+		enabled[name] = value.Enabled
+	}
+	return &Flags{enabled: enabled}, nil
+}
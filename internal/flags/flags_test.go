@@ -0,0 +1,63 @@
+package flags
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	f := Parse([]string{"flex_messages", " plain_text ", "-split_day_messages", ""})
+
+	assert.True(t, f.Enabled("flex_messages"))
+	assert.True(t, f.Enabled("plain_text"))
+	assert.False(t, f.Enabled("split_day_messages"))
+	assert.False(t, f.Enabled("undefined"))
+}
+
+func TestNilFlags(t *testing.T) {
+	var f *Flags
+	assert.False(t, f.Enabled("flex_messages"))
+	assert.True(t, f.Merge(Parse([]string{"flex_messages"})).Enabled("flex_messages"))
+}
+
+func TestMerge(t *testing.T) {
+	base := Parse([]string{"flex_messages", "plain_text"})
+	override := New(map[string]bool{"plain_text": false, "split_day_messages": true})
+
+	merged := base.Merge(override)
+	assert.True(t, merged.Enabled("flex_messages"))
+	assert.False(t, merged.Enabled("plain_text"))
+	assert.True(t, merged.Enabled("split_day_messages"))
+
+	// 元のFlagsは変更しない
+	assert.True(t, base.Enabled("plain_text"))
+}
+
+func TestLoadAppConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/applications/notifier/environments/prod/configurations/flags", r.URL.Path)
+		_, _ = w.Write([]byte(`{"flex_messages":{"enabled":true},"plain_text":{"enabled":false}}`))
+	}))
+	defer server.Close()
+
+	f, err := LoadAppConfig(context.Background(), server.Client(), server.URL+"/applications/notifier/environments/prod/configurations/flags")
+	require.NoError(t, err)
+	assert.True(t, f.Enabled("flex_messages"))
+	assert.False(t, f.Enabled("plain_text"))
+}
+
+func TestLoadAppConfig_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("not found"))
+	}))
+	defer server.Close()
+
+	_, err := LoadAppConfig(context.Background(), server.Client(), server.URL)
+	assert.ErrorContains(t, err, "Status: 404")
+}
@@ -0,0 +1,89 @@
+package gateway
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
+)
+
+// Anniversary 毎年同じ日に迎える記念日・誕生日
+type Anniversary struct {
+	Name  string
+	Month time.Month
+	Day   int
+}
+
+// ParseAnniversaries 名前と「MM-DD」形式の日付の対応から記念日の一覧を作成（名前順）
+func ParseAnniversaries(nameToDate map[string]string) ([]Anniversary, error) {
+	anniversaries := make([]Anniversary, 0, len(nameToDate))
+	for name, date := range nameToDate {
+		// うるう日も受け付けるため、うるう年の日付として解析する
+		parsed, err := time.Parse("2006-01-02", "2024-"+date)
+		if err != nil {
+			return nil, fmt.Errorf("記念日 %s の日付が不正です: %q（MM-DD形式で指定してください）", name, date)
+		}
+		anniversaries = append(anniversaries, Anniversary{Name: name, Month: parsed.Month(), Day: parsed.Day()})
+	}
+	sort.Slice(anniversaries, func(i, j int) bool { return anniversaries[i].Name < anniversaries[j].Name })
+	return anniversaries, nil
+}
+
+// next today以降で最初に記念日を迎える日（todayと同じタイムゾーンの0時）
+// うるう日の記念日は、うるう年でない年は2月28日として扱う
+func (a Anniversary) next(today time.Time) time.Time {
+	for year := today.Year(); ; year++ {
+		day := a.Day
+		if a.Month == time.February && day == 29 && !isLeapYear(year) {
+			day = 28
+		}
+		date := time.Date(year, a.Month, day, 0, 0, 0, 0, today.Location())
+		if !date.Before(today) {
+			return date
+		}
+	}
+}
+
+// isLeapYear うるう年かどうか
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// AnniversaryCountdown カレンダーに登録していない記念日までのカウントダウンを本日の予定の先頭に表示する設定
+type AnniversaryCountdown struct {
+	Anniversaries []Anniversary
+	// Within 記念日の何日前から表示するか（0の場合は当日のみ）
+	Within int
+}
+
+// lines 表示期間内の記念日のカウントダウン行を近い順に返す
+func (c *AnniversaryCountdown) lines(today time.Time) []string {
+	if c == nil {
+		return nil
+	}
+	today = dates.StartOfDay(today)
+
+	type countdown struct {
+		name string
+		days int
+	}
+	var upcoming []countdown
+	for _, anniversary := range c.Anniversaries {
+		days := dates.DaysBetween(today, anniversary.next(today))
+		if days <= c.Within {
+			upcoming = append(upcoming, countdown{name: anniversary.Name, days: days})
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool { return upcoming[i].days < upcoming[j].days })
+
+	lines := make([]string, len(upcoming))
+	for i, u := range upcoming {
+		if u.days == 0 {
+			lines[i] = fmt.Sprintf("🎂 今日は%sです", u.name)
+		} else {
+			lines[i] = fmt.Sprintf("🎂 %sまであと%d日", u.name, u.days)
+		}
+	}
+	return lines
+}
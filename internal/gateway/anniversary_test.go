@@ -0,0 +1,74 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+func TestParseAnniversaries(t *testing.T) {
+	anniversaries, err := ParseAnniversaries(map[string]string{"母の誕生日": "03-14", "結婚記念日": "02-29"})
+	require.NoError(t, err)
+	assert.Equal(t, []Anniversary{
+		{Name: "母の誕生日", Month: time.March, Day: 14},
+		{Name: "結婚記念日", Month: time.February, Day: 29},
+	}, anniversaries)
+
+	_, err = ParseAnniversaries(map[string]string{"母の誕生日": "3/14"})
+	assert.Error(t, err)
+	_, err = ParseAnniversaries(map[string]string{"母の誕生日": "02-30"})
+	assert.Error(t, err)
+}
+
+func TestAnniversaryCountdown_Lines(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	countdown := &AnniversaryCountdown{
+		Anniversaries: []Anniversary{
+			{Name: "母の誕生日", Month: time.March, Day: 14},
+			{Name: "結婚記念日", Month: time.March, Day: 10},
+			{Name: "父の誕生日", Month: time.March, Day: 31},
+		},
+		Within: 7,
+	}
+
+	assert.Equal(t, []string{"🎂 今日は結婚記念日です", "🎂 母の誕生日まであと4日"}, countdown.lines(time.Date(2025, 3, 10, 7, 0, 0, 0, jst)))
+	assert.Empty(t, countdown.lines(time.Date(2025, 3, 15, 7, 0, 0, 0, jst)))
+	assert.Nil(t, (*AnniversaryCountdown)(nil).lines(time.Now()))
+}
+
+func TestAnniversaryCountdown_LeapDayAndYearEnd(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	countdown := &AnniversaryCountdown{
+		Anniversaries: []Anniversary{
+			{Name: "結婚記念日", Month: time.February, Day: 29},
+			{Name: "元日", Month: time.January, Day: 1},
+		},
+		Within: 3,
+	}
+
+	// うるう年でない年は2月28日に迎える
+	assert.Equal(t, []string{"🎂 結婚記念日まであと1日"}, countdown.lines(time.Date(2025, 2, 27, 0, 0, 0, 0, jst)))
+	assert.Equal(t, []string{"🎂 結婚記念日まであと2日"}, countdown.lines(time.Date(2024, 2, 27, 0, 0, 0, 0, jst)))
+	// 年をまたいで数える
+	assert.Equal(t, []string{"🎂 元日まであと2日"}, countdown.lines(time.Date(2024, 12, 30, 0, 0, 0, 0, jst)))
+}
+
+func TestTextRenderer_Anniversaries(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	today := time.Date(2025, 3, 10, 7, 0, 0, 0, jst)
+	report := domain.NewScheduleReport(today, today, today.AddDate(0, 0, 1), nil, nil)
+	format := MessageFormat{Anniversaries: &AnniversaryCountdown{
+		Anniversaries: []Anniversary{{Name: "母の誕生日", Month: time.March, Day: 14}},
+		Within:        7,
+	}}
+
+	got := TextRenderer{Format: format}.Render(report)[0]
+	assert.Contains(t, got, "Google Calendar LINE Notifier\n\n🎂 母の誕生日まであと4日\n\n本日 3/10(月): 予定なし")
+
+	format.PlainText = true
+	assert.Contains(t, TextRenderer{Format: format}.Render(report)[0], "\n母の誕生日まであと4日\n")
+}
@@ -0,0 +1,72 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/usecase"
+)
+
+// LoadAttendeeNames {"mom@gmail.com":"母"} 形式のJSONファイルから参加者の表示名を読み込む
+func LoadAttendeeNames(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("参加者の表示名ファイルの読み込みに失敗しました: %v", err)
+	}
+	var names map[string]string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("参加者の表示名ファイルのJSON解析に失敗しました (%s): %v", path, err)
+	}
+	return names, nil
+}
+
+// AttendeeNameRepository 参加者のメールアドレスを設定された表示名に置き換えて予定に添えるデコレーター
+// 表示名が設定されていない参加者は表示しない（メンションなどに使うAttendeesはそのまま残す）
+type AttendeeNameRepository struct {
+	next  usecase.CalendarRepository
+	names map[string]string
+}
+
+// NewAttendeeNameRepository メールアドレスと表示名の対応を指定してAttendeeNameRepositoryを作成
+// メールアドレスの大文字・小文字は区別しない
+func NewAttendeeNameRepository(next usecase.CalendarRepository, names map[string]string) *AttendeeNameRepository {
+	normalized := make(map[string]string, len(names))
+	for email, name := range names {
+		if email, name = strings.ToLower(strings.TrimSpace(email)), strings.TrimSpace(name); email != "" && name != "" {
+			normalized[email] = name
+		}
+	}
+	return &AttendeeNameRepository{next: next, names: normalized}
+}
+
+// GetEvents 指定日のイベントを取得し、参加者の表示名を設定して返す
+func (r *AttendeeNameRepository) GetEvents(ctx context.Context, targetDate time.Time) ([]domain.Event, error) {
+	events, err := r.next.GetEvents(ctx, targetDate)
+	if err != nil {
+		return nil, err
+	}
+	for i := range events {
+		events[i].AttendeeNames = r.displayNames(events[i].Attendees)
+	}
+	return events, nil
+}
+
+// displayNames 参加者のうち表示名が設定されているものを参加者の順に返す（同じ表示名は1回のみ）
+func (r *AttendeeNameRepository) displayNames(attendees []string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, email := range attendees {
+		name, ok := r.names[strings.ToLower(email)]
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,68 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/fakes"
+)
+
+func TestAttendeeNameRepository_GetEvents(t *testing.T) {
+	next := fakes.CalendarRepository{Events: []domain.Event{
+		{Title: "家族会議", Attendees: []string{"Mom@Gmail.com", "stranger@example.com", "dad@gmail.com", "mom@gmail.com"}},
+		{Title: "定例", Attendees: []string{"boss@example.com"}},
+	}}
+	repo := NewAttendeeNameRepository(next, map[string]string{"mom@gmail.com": "母", " DAD@gmail.com ": "父"})
+
+	events, err := repo.GetEvents(context.Background(), time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"母", "父"}, events[0].AttendeeNames)
+	assert.Len(t, events[0].Attendees, 4, "メンション用のメールアドレスは残す")
+	assert.Empty(t, events[1].AttendeeNames)
+}
+
+func TestAttendeeNameRepository_Error(t *testing.T) {
+	repo := NewAttendeeNameRepository(fakes.CalendarRepository{Err: errors.New("forbidden")}, map[string]string{"mom@gmail.com": "母"})
+
+	_, err := repo.GetEvents(context.Background(), time.Now())
+	assert.Error(t, err)
+}
+
+func TestLoadAttendeeNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "names.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"mom@gmail.com":"母"}`), 0o600))
+
+	names, err := LoadAttendeeNames(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"mom@gmail.com": "母"}, names)
+
+	require.NoError(t, os.WriteFile(path, []byte(`["mom@gmail.com"]`), 0o600))
+	_, err = LoadAttendeeNames(path)
+	assert.Error(t, err)
+
+	_, err = LoadAttendeeNames(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestAttendeeNames_Display(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	today := time.Date(2024, 1, 15, 7, 0, 0, 0, jst)
+	events := []domain.Event{{
+		Title:         "家族会議",
+		StartTime:     time.Date(2024, 1, 15, 19, 0, 0, 0, jst),
+		EndTime:       time.Date(2024, 1, 15, 20, 0, 0, 0, jst),
+		AttendeeNames: []string{"母", "父"},
+	}}
+	report := domain.NewScheduleReport(today, today, today.AddDate(0, 0, 1), events, nil)
+
+	assert.Contains(t, TextRenderer{}.Render(report)[0], "🔸 19:00〜20:00 家族会議\n   👤 母、父\n")
+	assert.Contains(t, TextRenderer{Format: MessageFormat{PlainText: true}}.Render(report)[0], "   参加者: 母、父\n")
+}
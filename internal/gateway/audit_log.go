@@ -0,0 +1,130 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/experiment"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/runid"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
+)
+
+// auditSchema 送信ログの保存形式のバージョン
+// AuditRecordのフィールドの意味を変える場合はバージョンを上げ、Migrationsに移行手順を追加する
+var auditSchema = store.Schema{Name: "送信ログ", Version: 1}
+
+// AuditRecord 送信したメッセージの記録
+type AuditRecord struct {
+	SentAt    time.Time `json:"sentAt"`
+	Channel   string    `json:"channel"`
+	Recipient string    `json:"recipient"`
+	RetryKey  string    `json:"retryKey,omitempty"`
+	RunID     string    `json:"runId,omitempty"`
+	// Variant A/Bテストで選ばれたテンプレートのバリアント名
+	Variant  string         `json:"variant,omitempty"`
+	Messages []AuditMessage `json:"messages"`
+}
+
+// AuditMessage 送信したメッセージ1件の記録
+// 伏せ字設定の場合は本文を保存せず、文字数とハッシュのみを残す
+type AuditMessage struct {
+	Type   string `json:"type"`
+	Text   string `json:"text,omitempty"`
+	Length int    `json:"length"`
+	SHA256 string `json:"sha256"`
+}
+
+// AuditLog 送信したメッセージを送信先・日付ごとにストアへ保存する送信ログ
+// 「3/4に何が送られたか」を後から確認するために使う
+type AuditLog struct {
+	store     store.Store
+	retention time.Duration
+	redact    bool
+	clock     clock.Clock
+}
+
+// NewAuditLog 保存期間と伏せ字の有無を指定してAuditLogを作成
+func NewAuditLog(s store.Store, retention time.Duration, redact bool, clk clock.Clock) *AuditLog {
+	return &AuditLog{store: s, retention: retention, redact: redact, clock: clk}
+}
+
+// Record 送信したメッセージを記録
+func (a *AuditLog) Record(ctx context.Context, channel, recipient, retryKey string, messages []lineMessage) error {
+	now := a.clock.Now()
+	record := AuditRecord{
+		SentAt:    now,
+		Channel:   channel,
+		Recipient: recipient,
+		RetryKey:  retryKey,
+		RunID:     runid.FromContext(ctx),
+		Variant:   experiment.FromContext(ctx),
+		Messages:  make([]AuditMessage, len(messages)),
+	}
+	for i, message := range messages {
+		sum := sha256.Sum256([]byte(message.Text))
+		record.Messages[i] = AuditMessage{
+			Type:   message.Type,
+			Length: utf8.RuneCountInString(message.Text),
+			SHA256: hex.EncodeToString(sum[:]),
+		}
+		if !a.redact {
+			record.Messages[i].Text = message.Text
+		}
+	}
+
+	records, err := a.Records(ctx, recipient, now)
+	if err != nil {
+		return err
+	}
+
+	data, err := auditSchema.Encode(append(records, record))
+	if err != nil {
+		return err
+	}
+	return a.store.Put(ctx, auditKey(recipient, now), data, a.retention)
+}
+
+// Records 指定した送信先・日付（JST）に送信したメッセージの記録を取得
+func (a *AuditLog) Records(ctx context.Context, recipient string, day time.Time) ([]AuditRecord, error) {
+	data, err := a.store.Get(ctx, auditKey(recipient, day))
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []AuditRecord
+	if _, err := auditSchema.Decode(data, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// auditKey 送信先と日付（JST）ごとの送信ログのキー
+// LINEの送信数の集計と突き合わせるため、設定されたタイムゾーンに関わらずLINEの集計日（日本時間）で区切る
+func auditKey(recipient string, day time.Time) string {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	return store.Key(store.NamespaceAudit, recipient+":"+day.In(jst).Format("2006-01-02"))
+}
+
+// SetAuditLog 送信したメッセージを記録するAuditLogを設定
+func (n *LINENotifier) SetAuditLog(audit *AuditLog) {
+	n.audit = audit
+}
+
+// recordAudit 送信したメッセージを送信ログに記録（失敗しても送信結果には影響させない）
+func (n *LINENotifier) recordAudit(ctx context.Context, retryKey string, messages []lineMessage) {
+	if n.audit == nil {
+		return
+	}
+	if err := n.audit.Record(ctx, "LINE", n.userID, retryKey, messages); err != nil {
+		fmt.Printf("Warning: 送信ログの記録に失敗しました: %v\n", err)
+	}
+}
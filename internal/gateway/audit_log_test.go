@@ -0,0 +1,102 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/experiment"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/runid"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
+)
+
+func TestAuditLog_Record(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 3, 4, 7, 0, 0, 0, jst)
+	clk := clock.Fixed(now)
+	audit := NewAuditLog(store.NewMemoryStore(clk), 90*24*time.Hour, false, clk)
+
+	ctx := experiment.WithVariant(runid.WithRunID(context.Background(), "run-1"), "plain")
+	require.NoError(t, audit.Record(ctx, "LINE", "U123", "key-1", []lineMessage{{Type: "text", Text: "本日の予定"}}))
+	require.NoError(t, audit.Record(ctx, "LINE", "U123", "", []lineMessage{{Type: "text", Text: "明日の予定"}}))
+
+	records, err := audit.Records(ctx, "U123", time.Date(2024, 3, 4, 0, 0, 0, 0, jst))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "U123", records[0].Recipient)
+	assert.Equal(t, "key-1", records[0].RetryKey)
+	assert.Equal(t, "run-1", records[0].RunID)
+	assert.Equal(t, "plain", records[0].Variant)
+	assert.Equal(t, "本日の予定", records[0].Messages[0].Text)
+	assert.Equal(t, 5, records[0].Messages[0].Length)
+	assert.Equal(t, "明日の予定", records[1].Messages[0].Text)
+
+	// 別の日・別の送信先の記録は含まない
+	records, err = audit.Records(ctx, "U123", time.Date(2024, 3, 5, 0, 0, 0, 0, jst))
+	require.NoError(t, err)
+	assert.Empty(t, records)
+	records, err = audit.Records(ctx, "U999", now)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestAuditLog_ReadsUnversionedRecords(t *testing.T) {
+	clk := clock.Fixed(time.Date(2024, 3, 4, 7, 0, 0, 0, time.UTC))
+	s := store.NewMemoryStore(clk)
+	audit := NewAuditLog(s, time.Hour, false, clk)
+
+	// バージョン管理を始める前の形式（記録の配列をそのまま保存）
+	ctx := context.Background()
+	require.NoError(t, s.Put(ctx, auditKey("U123", clk.Now()), []byte(`[{"channel":"LINE","recipient":"U123","messages":[{"type":"text","text":"旧形式","length":3}]}]`), time.Hour))
+	require.NoError(t, audit.Record(ctx, "LINE", "U123", "", []lineMessage{{Type: "text", Text: "新形式"}}))
+
+	records, err := audit.Records(ctx, "U123", clk.Now())
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "旧形式", records[0].Messages[0].Text)
+	assert.Equal(t, "新形式", records[1].Messages[0].Text)
+}
+
+func TestAuditLog_Redact(t *testing.T) {
+	clk := clock.Fixed(time.Date(2024, 3, 4, 7, 0, 0, 0, time.UTC))
+	audit := NewAuditLog(store.NewMemoryStore(clk), time.Hour, true, clk)
+
+	ctx := context.Background()
+	require.NoError(t, audit.Record(ctx, "LINE", "U123", "", []lineMessage{{Type: "text", Text: "abc"}}))
+
+	records, err := audit.Records(ctx, "U123", clk.Now())
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Empty(t, records[0].Messages[0].Text)
+	assert.Equal(t, 3, records[0].Messages[0].Length)
+	assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", records[0].Messages[0].SHA256)
+}
+
+func TestLINENotifier_RecordsOnlySuccessfulPushes(t *testing.T) {
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 3, 4, 7, 0, 0, 0, time.UTC)
+	clk := clock.Fixed(now)
+	audit := NewAuditLog(store.NewMemoryStore(clk), time.Hour, false, clk)
+	n := newTestLINENotifier("test-token", "U123", server.Client(), server.URL, clk.Now)
+	n.SetAuditLog(audit)
+
+	require.Error(t, n.sendPushMessage(context.Background(), "失敗した通知"))
+	status = http.StatusOK
+	require.NoError(t, n.sendPushMessage(context.Background(), "成功した通知"))
+
+	records, err := audit.Records(context.Background(), "U123", now)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "成功した通知", records[0].Messages[0].Text)
+}
@@ -0,0 +1,82 @@
+package gateway
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/textutil"
+)
+
+// chunkOmittedNotice 通数の上限を超えて省略したときに最後のメッセージへ付ける表示
+const chunkOmittedNotice = "\n…以下省略"
+
+// ChunkPolicy 通知先ごとのメッセージ長・通数の上限
+// Rendererで作成したメッセージに適用し、予定の多い日も通知先の上限に収まるよう分割する
+type ChunkPolicy struct {
+	// MaxLength 1通あたりの最大文字数（rune単位、0以下は分割しない）
+	MaxLength int
+	// MaxChunks 1回の通知で送る最大通数（0以下は無制限）
+	MaxChunks int
+}
+
+// LINEChunkPolicy LINE Messaging APIのテキストメッセージ（5000文字、1リクエスト5通まで）
+var LINEChunkPolicy = ChunkPolicy{MaxLength: 5000, MaxChunks: 5}
+
+// Apply 上限を超えるメッセージを行単位で分割する
+// 1行が上限を超える場合は文字単位で分割し、通数の上限を超えた分は省略する
+func (p ChunkPolicy) Apply(texts []string) []string {
+	if p.MaxLength <= 0 {
+		return texts
+	}
+
+	var chunks []string
+	for _, text := range texts {
+		chunks = append(chunks, p.split(text)...)
+	}
+	if p.MaxChunks <= 0 || len(chunks) <= p.MaxChunks {
+		return chunks
+	}
+
+	chunks = chunks[:p.MaxChunks]
+	last := len(chunks) - 1
+	room := p.MaxLength - utf8.RuneCountInString(chunkOmittedNotice)
+	chunks[last] = textutil.Truncate(chunks[last], room) + chunkOmittedNotice
+	return chunks
+}
+
+// split 1通のメッセージを上限以内の長さに分割する
+func (p ChunkPolicy) split(text string) []string {
+	if utf8.RuneCountInString(text) <= p.MaxLength {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	length := 0
+	flush := func() {
+		// 分割位置の空行は落とす（空のメッセージは送信できない）
+		if chunk := strings.Trim(current.String(), "\n"); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		current.Reset()
+		length = 0
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		lineLength := utf8.RuneCountInString(line)
+		if length+lineLength > p.MaxLength {
+			flush()
+		}
+		// 1行で上限を超える場合は文字単位で切る
+		for lineLength > p.MaxLength {
+			runes := []rune(line)
+			chunks = append(chunks, string(runes[:p.MaxLength]))
+			line = string(runes[p.MaxLength:])
+			lineLength -= p.MaxLength
+		}
+		current.WriteString(line)
+		length += lineLength
+	}
+	flush()
+	return chunks
+}
@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunkPolicy_Apply(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ChunkPolicy
+		texts  []string
+		want   []string
+	}{
+		{name: "上限以内はそのまま", policy: ChunkPolicy{MaxLength: 10}, texts: []string{"予定A", "予定B"}, want: []string{"予定A", "予定B"}},
+		{name: "上限なし", policy: ChunkPolicy{}, texts: []string{strings.Repeat("あ", 100)}, want: []string{strings.Repeat("あ", 100)}},
+		{name: "行単位で分割", policy: ChunkPolicy{MaxLength: 8}, texts: []string{"本日:\n予定A\n予定B\n予定C"}, want: []string{"本日:\n予定A", "予定B\n予定C"}},
+		{name: "長い行は文字単位で分割", policy: ChunkPolicy{MaxLength: 4}, texts: []string{"あいうえおか"}, want: []string{"あいうえ", "おか"}},
+		{name: "分割位置の空行を落とす", policy: ChunkPolicy{MaxLength: 4}, texts: []string{"予定A\n\n予定B"}, want: []string{"予定A", "予定B"}},
+		{
+			name:   "通数の上限を超えた分は省略",
+			policy: ChunkPolicy{MaxLength: 10, MaxChunks: 2},
+			texts:  []string{"一\n二", "三三三三三三三三三三三", "四"},
+			want:   []string{"一\n二", "三三三…" + chunkOmittedNotice},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.Apply(tt.texts))
+		})
+	}
+}
+
+func TestLINEChunkPolicy_LongDay(t *testing.T) {
+	text := strings.Repeat("🔸 10:00〜11:00 定例会議\n", 2000)
+
+	chunks := LINEChunkPolicy.Apply([]string{text})
+
+	assert.Len(t, chunks, LINEChunkPolicy.MaxChunks)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, utf8.RuneCountInString(chunk), LINEChunkPolicy.MaxLength)
+	}
+	assert.True(t, strings.HasSuffix(chunks[len(chunks)-1], chunkOmittedNotice))
+}
@@ -0,0 +1,32 @@
+package gateway
+
+import (
+	"fmt"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+// warningLines ユースケースが判定した注意事項を表示する行を返す
+func warningLines(warnings []domain.Warning) []string {
+	var lines []string
+	for _, warning := range warnings {
+		if line := warningLine(warning); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// warningLine 注意事項を1行で表示する（表示しない種類の場合は空文字）
+func warningLine(warning domain.Warning) string {
+	switch warning.Kind {
+	case domain.WarningEarlyOffice:
+		event := warning.Event
+		where := ""
+		if event.Location != "" {
+			where = fmt.Sprintf(" (%s)", event.Location)
+		}
+		return fmt.Sprintf("⏰ 早めの出社に注意: %s %s%s", event.StartTime.Format("15:04"), event.Title, where)
+	}
+	return ""
+}
@@ -0,0 +1,25 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+func TestWarningLines(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	start := time.Date(2024, 1, 15, 8, 30, 0, 0, jst)
+
+	lines := warningLines([]domain.Warning{
+		{Kind: domain.WarningEarlyOffice, Event: domain.Event{Title: "設計レビュー", StartTime: start, Location: "本社 5F"}},
+		{Kind: domain.WarningEarlyOffice, Event: domain.Event{Title: "朝会", StartTime: start.Add(15 * time.Minute)}},
+		{Kind: "unknown"},
+	})
+	assert.Equal(t, []string{
+		"⏰ 早めの出社に注意: 08:30 設計レビュー (本社 5F)",
+		"⏰ 早めの出社に注意: 08:45 朝会",
+	}, lines)
+}
@@ -0,0 +1,121 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/usecase"
+)
+
+// CalendarSource CompositeCalendarRepositoryが集約する取得元
+type CalendarSource struct {
+	// Label イベントのSourceに設定するラベル
+	Label string
+	// Repository イベントの取得元
+	Repository usecase.CalendarRepository
+	// Required trueの場合、取得に失敗すると全体をエラーにする
+	// falseの場合は警告を出してその取得元のイベントのみ除外する
+	Required bool
+	// Timeout 取得を待つ時間（0以下の場合は打ち切らない）
+	// 時間内に応答しない場合は、取得元の処理の終了を待たずに失敗として扱う
+	Timeout time.Duration
+}
+
+// CompositeCalendarRepository 複数の取得元から並行してイベントを取得し、時刻順にマージするリポジトリ
+type CompositeCalendarRepository struct {
+	sources []CalendarSource
+}
+
+// NewCompositeCalendarRepository 複数の取得元を束ねたリポジトリを作成
+func NewCompositeCalendarRepository(sources ...CalendarSource) *CompositeCalendarRepository {
+	return &CompositeCalendarRepository{sources: sources}
+}
+
+// GetEvents 全取得元のイベントを取得し、Sourceラベルを付けて開始時刻順に返す
+func (r *CompositeCalendarRepository) GetEvents(ctx context.Context, targetDate time.Time) ([]domain.Event, error) {
+	results := make([][]domain.Event, len(r.sources))
+	errs := make([]error, len(r.sources))
+
+	var g errgroup.Group
+	for i, source := range r.sources {
+		g.Go(func() error {
+			events, err := source.fetch(ctx, targetDate)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", source.Label, err)
+				return nil
+			}
+			for j := range events {
+				events[j].Source = source.Label
+			}
+			results[i] = events
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	merged := make([]domain.Event, 0)
+	var failed []error
+	for i, source := range r.sources {
+		if errs[i] != nil {
+			if source.Required {
+				return nil, errs[i]
+			}
+			fmt.Printf("Warning: %s からのイベント取得に失敗したため除外します: %v\n", source.Label, errs[i])
+			failed = append(failed, errs[i])
+			continue
+		}
+		merged = append(merged, results[i]...)
+	}
+
+	// 全ての取得元が失敗した場合はエラー
+	if len(r.sources) > 0 && len(failed) == len(r.sources) {
+		return nil, errors.Join(failed...)
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].StartTime.Before(merged[j].StartTime)
+	})
+	return merged, nil
+}
+
+// fetch Timeoutの時間内で取得元からイベントを取得する
+// 取得元でパニックが発生した場合もエラーとして返し、他の取得元の結果は失わない
+func (s CalendarSource) fetch(ctx context.Context, targetDate time.Time) ([]domain.Event, error) {
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		events []domain.Event
+		err    error
+	}
+	// タイムアウト後に取得元が応答しても待ち受けはいないため、バッファ付きにして送信で止まらないようにする
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- result{err: fmt.Errorf("取得中にパニックが発生しました: %v", p)}
+			}
+		}()
+		events, err := s.Repository.GetEvents(ctx, targetDate)
+		done <- result{events: events, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.events, r.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && s.Timeout > 0 {
+			return nil, fmt.Errorf("%v以内に応答がありませんでした: %w", s.Timeout, ctx.Err())
+		}
+		return nil, ctx.Err()
+	}
+}
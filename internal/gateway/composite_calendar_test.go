@@ -0,0 +1,120 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/fakes"
+)
+
+func TestCompositeCalendarRepository_MergesAndLabels(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	at := func(hour int) time.Time { return time.Date(2024, 1, 15, hour, 0, 0, 0, jst) }
+
+	repo := NewCompositeCalendarRepository(
+		CalendarSource{Label: "仕事", Repository: fakes.CalendarRepository{Events: []domain.Event{
+			{Title: "朝会", StartTime: at(9)},
+			{Title: "レビュー", StartTime: at(15)},
+		}}, Required: true},
+		CalendarSource{Label: "家族", Repository: fakes.CalendarRepository{Events: []domain.Event{
+			{Title: "保育園送り", StartTime: at(8)},
+			{Title: "夕食", StartTime: at(19)},
+		}}},
+	)
+
+	result, err := repo.GetEvents(context.Background(), at(0))
+	require.NoError(t, err)
+	require.Len(t, result, 4)
+
+	titles := []string{result[0].Title, result[1].Title, result[2].Title, result[3].Title}
+	assert.Equal(t, []string{"保育園送り", "朝会", "レビュー", "夕食"}, titles)
+	assert.Equal(t, "家族", result[0].Source)
+	assert.Equal(t, "仕事", result[1].Source)
+}
+
+func TestCompositeCalendarRepository_OptionalSourceFailure(t *testing.T) {
+	repo := NewCompositeCalendarRepository(
+		CalendarSource{Label: "Google", Repository: fakes.CalendarRepository{Events: []domain.Event{{Title: "朝会"}}}, Required: true},
+		CalendarSource{Label: "GitHub", Repository: fakes.CalendarRepository{Err: errors.New("GitHub API error")}},
+	)
+
+	result, err := repo.GetEvents(context.Background(), time.Now())
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "Google", result[0].Source)
+}
+
+func TestCompositeCalendarRepository_RequiredSourceFailure(t *testing.T) {
+	repo := NewCompositeCalendarRepository(
+		CalendarSource{Label: "Google", Repository: fakes.CalendarRepository{Err: errors.New("calendar API error")}, Required: true},
+		CalendarSource{Label: "GitHub", Repository: fakes.CalendarRepository{Events: []domain.Event{{Title: "締め切り"}}}},
+	)
+
+	_, err := repo.GetEvents(context.Background(), time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Google: calendar API error")
+}
+
+func TestCompositeCalendarRepository_AllOptionalSourcesFail(t *testing.T) {
+	repo := NewCompositeCalendarRepository(
+		CalendarSource{Label: "A", Repository: fakes.CalendarRepository{Err: errors.New("error A")}},
+		CalendarSource{Label: "B", Repository: fakes.CalendarRepository{Err: errors.New("error B")}},
+	)
+
+	_, err := repo.GetEvents(context.Background(), time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error A")
+	assert.Contains(t, err.Error(), "error B")
+}
+
+// blockingEventsGetter はコンテキストを無視して解放されるまで応答しない取得元
+type blockingEventsGetter struct {
+	release chan struct{}
+}
+
+func (b *blockingEventsGetter) GetEvents(_ context.Context, _ time.Time) ([]domain.Event, error) {
+	<-b.release
+	return []domain.Event{{Title: "遅れて届いた予定"}}, nil
+}
+
+// panickingEventsGetter は取得中にパニックする取得元
+type panickingEventsGetter struct{}
+
+func (panickingEventsGetter) GetEvents(_ context.Context, _ time.Time) ([]domain.Event, error) {
+	panic("unexpected response")
+}
+
+func TestCompositeCalendarRepository_OptionalSourceTimeout(t *testing.T) {
+	slow := &blockingEventsGetter{release: make(chan struct{})}
+	defer close(slow.release)
+	repo := NewCompositeCalendarRepository(
+		CalendarSource{Label: "Google", Repository: fakes.CalendarRepository{Events: []domain.Event{{Title: "朝会"}}}, Required: true},
+		CalendarSource{Label: "GitHub", Repository: slow, Timeout: 10 * time.Millisecond},
+	)
+
+	result, err := repo.GetEvents(context.Background(), time.Now())
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "朝会", result[0].Title)
+}
+
+func TestCompositeCalendarRepository_OptionalSourcePanic(t *testing.T) {
+	repo := NewCompositeCalendarRepository(
+		CalendarSource{Label: "Google", Repository: fakes.CalendarRepository{Events: []domain.Event{{Title: "朝会"}}}, Required: true},
+		CalendarSource{Label: "Family", Repository: panickingEventsGetter{}},
+	)
+
+	result, err := repo.GetEvents(context.Background(), time.Now())
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+
+	_, err = CalendarSource{Label: "Family", Repository: panickingEventsGetter{}}.fetch(context.Background(), time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected response")
+}
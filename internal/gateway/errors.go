@@ -0,0 +1,174 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+)
+
+// maxErrorBodyBytes エラーレスポンスとして読み込むボディの上限サイズ
+const maxErrorBodyBytes = 64 * 1024
+
+// ErrorKind 外部API呼び出しエラーの分類
+type ErrorKind int
+
+const (
+	// ErrorKindUnknown 分類不能なエラー
+	ErrorKindUnknown ErrorKind = iota
+	// ErrorKindAuth 認証・認可エラー（トークン失効、権限不足など）
+	ErrorKindAuth
+	// ErrorKindRateLimit レート制限超過
+	ErrorKindRateLimit
+	// ErrorKindTransient 一時的なエラー（5xx、タイムアウト、通信断など）
+	ErrorKindTransient
+	// ErrorKindInvalidRequest リクエスト内容の不備によるエラー
+	ErrorKindInvalidRequest
+)
+
+// String ログ出力用の分類名を返す
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindAuth:
+		return "auth"
+	case ErrorKindRateLimit:
+		return "rate_limit"
+	case ErrorKindTransient:
+		return "transient"
+	case ErrorKindInvalidRequest:
+		return "invalid_request"
+	default:
+		return "unknown"
+	}
+}
+
+// APIError 外部API呼び出しの失敗を表すエラー
+type APIError struct {
+	Service    string
+	StatusCode int
+	Kind       ErrorKind
+	Message    string
+	Err        error
+}
+
+// Error エラーメッセージを返す
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("%s APIリクエストの送信に失敗しました: %s", e.Service, e.Message)
+	}
+	return fmt.Sprintf("%s API呼び出しが失敗しました (Status: %d): %s", e.Service, e.StatusCode, e.Message)
+}
+
+// Unwrap 元のエラーを返す
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable 再試行で回復する可能性があるかどうか
+func (e *APIError) Retryable() bool {
+	return e.Kind == ErrorKindRateLimit || e.Kind == ErrorKindTransient
+}
+
+// IsRetryable エラーが再試行可能なAPIエラーかどうかを判定
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Retryable()
+}
+
+// ErrorKindOf エラーの分類を返す（APIErrorでない場合はErrorKindUnknown）
+func ErrorKindOf(err error) ErrorKind {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Kind
+	}
+	return ErrorKindUnknown
+}
+
+// classifyStatus HTTPステータスコードからエラーの分類を判定
+func classifyStatus(statusCode int) ErrorKind {
+	switch {
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+		return ErrorKindAuth
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorKindRateLimit
+	case statusCode == http.StatusRequestTimeout, statusCode >= 500:
+		return ErrorKindTransient
+	case statusCode >= 400:
+		return ErrorKindInvalidRequest
+	default:
+		return ErrorKindUnknown
+	}
+}
+
+// classifyGoogleError Google APIのエラーをAPIErrorに変換
+func classifyGoogleError(err error) error {
+	var gerr *googleapi.Error
+	if !errors.As(err, &gerr) {
+		return err
+	}
+
+	kind := classifyStatus(gerr.Code)
+	// Google APIはレート制限を403で返す場合がある
+	for _, item := range gerr.Errors {
+		if item.Reason == "rateLimitExceeded" || item.Reason == "userRateLimitExceeded" {
+			kind = ErrorKindRateLimit
+			break
+		}
+	}
+
+	message := gerr.Message
+	if message == "" {
+		message = truncateBody([]byte(gerr.Body))
+	}
+
+	return &APIError{
+		Service:    "Google Calendar",
+		StatusCode: gerr.Code,
+		Kind:       kind,
+		Message:    message,
+		Err:        err,
+	}
+}
+
+// readErrorBody エラーレスポンスのボディを上限サイズまで読み込む
+func readErrorBody(r io.Reader) ([]byte, error) {
+	return io.ReadAll(io.LimitReader(r, maxErrorBodyBytes))
+}
+
+// truncateBody ログ・エラーメッセージ用にボディを短く切り詰める
+func truncateBody(body []byte) string {
+	const maxLen = 512
+	if len(body) > maxLen {
+		return strings.ToValidUTF8(string(body[:maxLen]), "") + "..."
+	}
+	return string(body)
+}
+
+// limitErrorBodyTransport エラーレスポンスのボディ読み込み量を制限するRoundTripper
+type limitErrorBodyTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip 非2xxレスポンスのボディを上限サイズで打ち切る
+func (t *limitErrorBodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body = &limitedReadCloser{
+			Reader: io.LimitReader(resp.Body, maxErrorBodyBytes),
+			Closer: resp.Body,
+		}
+	}
+	return resp, nil
+}
+
+// limitedReadCloser 読み込み量を制限しつつ元のボディをCloseする
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
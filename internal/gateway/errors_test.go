@@ -0,0 +1,116 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+)
+
+// --- classifyStatus テスト ---
+
+func TestClassifyStatus(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		expected   ErrorKind
+	}{
+		{http.StatusUnauthorized, ErrorKindAuth},
+		{http.StatusForbidden, ErrorKindAuth},
+		{http.StatusTooManyRequests, ErrorKindRateLimit},
+		{http.StatusRequestTimeout, ErrorKindTransient},
+		{http.StatusInternalServerError, ErrorKindTransient},
+		{http.StatusServiceUnavailable, ErrorKindTransient},
+		{http.StatusBadRequest, ErrorKindInvalidRequest},
+		{http.StatusOK, ErrorKindUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			assert.Equal(t, tt.expected, classifyStatus(tt.statusCode))
+		})
+	}
+}
+
+// --- IsRetryable テスト ---
+
+func TestIsRetryable(t *testing.T) {
+	rateLimited := &APIError{Service: "LINE", StatusCode: 429, Kind: ErrorKindRateLimit}
+	auth := &APIError{Service: "LINE", StatusCode: 401, Kind: ErrorKindAuth}
+
+	assert.True(t, IsRetryable(rateLimited))
+	assert.True(t, IsRetryable(fmt.Errorf("wrapped: %w", rateLimited)))
+	assert.False(t, IsRetryable(auth))
+	assert.False(t, IsRetryable(errors.New("plain error")))
+}
+
+// --- classifyGoogleError テスト ---
+
+func TestClassifyGoogleError_RateLimitReason(t *testing.T) {
+	gerr := &googleapi.Error{
+		Code:    http.StatusForbidden,
+		Message: "Rate Limit Exceeded",
+		Errors:  []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}},
+	}
+
+	err := classifyGoogleError(gerr)
+	assert.Equal(t, ErrorKindRateLimit, ErrorKindOf(err))
+	assert.Contains(t, err.Error(), "Rate Limit Exceeded")
+	assert.ErrorIs(t, err, gerr)
+}
+
+func TestClassifyGoogleError_Auth(t *testing.T) {
+	err := classifyGoogleError(&googleapi.Error{Code: http.StatusUnauthorized, Body: "unauthorized"})
+	assert.Equal(t, ErrorKindAuth, ErrorKindOf(err))
+	assert.Contains(t, err.Error(), "unauthorized")
+}
+
+func TestClassifyGoogleError_NonGoogleError(t *testing.T) {
+	original := errors.New("network error")
+	assert.Equal(t, original, classifyGoogleError(original))
+}
+
+// --- readErrorBody テスト ---
+
+func TestReadErrorBody_Limited(t *testing.T) {
+	body, err := readErrorBody(strings.NewReader(strings.Repeat("a", maxErrorBodyBytes*2)))
+	require.NoError(t, err)
+	assert.Len(t, body, maxErrorBodyBytes)
+}
+
+// --- limitErrorBodyTransport テスト ---
+
+// roundTripperFunc 関数をRoundTripperとして扱うテスト用アダプタ
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestLimitErrorBodyTransport(t *testing.T) {
+	largeBody := strings.Repeat("x", maxErrorBodyBytes*2)
+	transport := &limitErrorBodyTransport{
+		base: roundTripperFunc(func(_ *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Body:       io.NopCloser(strings.NewReader(largeBody)),
+			}, nil
+		}),
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	require.NoError(t, err)
+
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Len(t, body, maxErrorBodyBytes)
+}
@@ -0,0 +1,70 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
+)
+
+// EventDedup 複数の通知モードで共有する予定単位の重複抑止
+// ある通知モードで通知した予定を記録し、一定時間内は他のモード（リマインダーなど）で同じ予定を通知しないようにする
+type EventDedup struct {
+	store  store.Store
+	window time.Duration
+	clock  clock.Clock
+}
+
+// NewEventDedup 重複を抑止する時間を指定してEventDedupを作成
+func NewEventDedup(s store.Store, window time.Duration, clk clock.Clock) *EventDedup {
+	return &EventDedup{store: s, window: window, clock: clk}
+}
+
+// SendScheduleNotification 通知した本日と翌日の予定を通知済みとして記録する
+// 補助的な出力先として、同じ予定を扱う他の出力先より前に実行する
+func (d *EventDedup) SendScheduleNotification(ctx context.Context, report domain.ScheduleReport) error {
+	value := []byte(d.clock.Now().UTC().Format(time.RFC3339))
+	var failed int
+	for _, day := range report.Days() {
+		for _, event := range day.Events {
+			if event.ID == "" || event.IsDeadline || event.IsFamily {
+				continue
+			}
+			if err := d.store.Put(ctx, eventDedupKey(event), value, d.window); err != nil {
+				fmt.Printf("Warning: 予定 %s の通知済みの記録に失敗しました: %v\n", event.ID, err)
+				failed++
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("予定%d件の通知済みの記録に失敗しました", failed)
+	}
+	return nil
+}
+
+// Covered 予定がat時点で重複抑止の時間内に通知済みかどうか
+// 記録を読み込めない場合は通知済みとして扱わない（通知の漏れより重複を許容する）
+func (d *EventDedup) Covered(ctx context.Context, event domain.Event, at time.Time) bool {
+	value, err := d.store.Get(ctx, eventDedupKey(event))
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			fmt.Printf("Warning: 予定 %s の通知済みの記録の取得に失敗しました: %v\n", event.ID, err)
+		}
+		return false
+	}
+	notifiedAt, err := time.Parse(time.RFC3339, string(value))
+	if err != nil {
+		return false
+	}
+	return at.Sub(notifiedAt) < d.window
+}
+
+// eventDedupKey 予定の重複抑止のキー
+// 開始時刻を含めるため、時刻が変更された予定は改めて通知する
+func eventDedupKey(event domain.Event) string {
+	return store.Key(store.NamespaceDedup, event.ID+"|"+event.StartTime.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,55 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
+)
+
+func TestEventDedup(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, jst)
+	clk := clock.Fixed(now)
+	dedup := NewEventDedup(store.NewMemoryStore(clk), time.Hour, clk)
+
+	standup := domain.Event{ID: "standup", Title: "朝会", StartTime: time.Date(2024, 1, 15, 7, 30, 0, 0, jst)}
+	family := domain.Event{ID: "pickup", Title: "お迎え", StartTime: time.Date(2024, 1, 15, 7, 40, 0, 0, jst), IsFamily: true}
+	report := domain.NewScheduleReport(now, now, now.AddDate(0, 0, 1), []domain.Event{standup, family}, nil)
+	require.NoError(t, dedup.SendScheduleNotification(context.Background(), report))
+
+	ctx := context.Background()
+	assert.True(t, dedup.Covered(ctx, standup, now.Add(20*time.Minute)))
+	assert.False(t, dedup.Covered(ctx, standup, now.Add(time.Hour)), "重複抑止の時間を過ぎたら通知する")
+	assert.False(t, dedup.Covered(ctx, family, now.Add(20*time.Minute)), "家族の予定は記録しない")
+
+	moved := standup
+	moved.StartTime = standup.StartTime.Add(30 * time.Minute)
+	assert.False(t, dedup.Covered(ctx, moved, now.Add(20*time.Minute)), "時刻が変わった予定は改めて通知する")
+}
+
+func TestReminderScheduler_Dedup(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, jst)
+	clk := clock.Fixed(now)
+	dedup := NewEventDedup(store.NewMemoryStore(clk), time.Hour, clk)
+
+	standup := domain.Event{ID: "standup", Title: "朝会", StartTime: time.Date(2024, 1, 15, 7, 30, 0, 0, jst)}
+	review := domain.Event{ID: "review", Title: "設計レビュー", StartTime: time.Date(2024, 1, 15, 14, 0, 0, 0, jst)}
+	report := domain.NewScheduleReport(now, now, now.AddDate(0, 0, 1), []domain.Event{standup, review}, nil)
+	require.NoError(t, dedup.SendScheduleNotification(context.Background(), report))
+
+	client := &fakeSchedulerClient{}
+	s := NewReminderScheduler(client, "reminders", "arn:aws:lambda:target", "arn:aws:iam::role/scheduler", 10*time.Minute, clk)
+	s.SetDedup(dedup)
+	require.NoError(t, s.SendScheduleNotification(context.Background(), report))
+
+	require.Len(t, client.created, 1, "朝の通知の直後の朝会のリマインダーは登録しない")
+	assert.Contains(t, *client.created[0].Target.Input, `"eventId":"review"`)
+}
@@ -0,0 +1,68 @@
+package gateway
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/sanitize"
+)
+
+// meetingProvider ビデオ会議サービスとその参加URLのパターン
+type meetingProvider struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// meetingProviders 認識するビデオ会議サービス
+var meetingProviders = []meetingProvider{
+	{name: "Zoom", pattern: regexp.MustCompile(`^https://([a-z0-9-]+\.)?zoom\.us/(j|my|w)/`)},
+	{name: "Teams", pattern: regexp.MustCompile(`^https://teams\.(microsoft|live)\.com/(l/meetup-join|meet)/`)},
+	{name: "Meet", pattern: regexp.MustCompile(`^https://meet\.google\.com/[a-z]{3}-[a-z]{4}-[a-z]{3}`)},
+	{name: "Webex", pattern: regexp.MustCompile(`^https://[a-z0-9-]+\.webex\.com/`)},
+}
+
+// eventLink 予定に表示するリンクを1つ選び、ラベル付きで返す（なければ空文字）
+// ビデオ会議の参加URLを優先し、なければ場所、説明文の順で最初に見つかったURLを使う
+func eventLink(event domain.Event) string {
+	url := eventLinkURL(event)
+	if provider := meetingProviderOf(url); provider != "" {
+		return provider + ": " + url
+	}
+	return url
+}
+
+// eventLinkURL 予定に表示するURLを1つ選んで返す（なければ空文字）
+func eventLinkURL(event domain.Event) string {
+	candidates := sanitize.ExtractURLs(event.ConferenceURL)
+	candidates = append(candidates, sanitize.ExtractURLs(event.Location)...)
+	candidates = append(candidates, sanitize.ExtractURLs(sanitize.HTMLToText(event.Description))...)
+
+	for _, url := range candidates {
+		if meetingProviderOf(url) != "" {
+			return url
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0]
+	}
+	return ""
+}
+
+// meetingProviderOf URLがビデオ会議の参加URLであればサービス名を返す
+func meetingProviderOf(url string) string {
+	lower := strings.ToLower(url)
+	for _, provider := range meetingProviders {
+		if provider.pattern.MatchString(lower) {
+			return provider.name
+		}
+	}
+	return ""
+}
+
+// isURLOnly テキストがURLのみで構成されているかどうか
+func isURLOnly(text string) bool {
+	text = strings.TrimSpace(text)
+	urls := sanitize.ExtractURLs(text)
+	return len(urls) == 1 && urls[0] == text
+}
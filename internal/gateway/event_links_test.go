@@ -0,0 +1,63 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+func TestMeetingProviderOf(t *testing.T) {
+	tests := map[string]string{
+		"https://zoom.us/j/1234567890?pwd=abc":                       "Zoom",
+		"https://us02web.zoom.us/j/1234567890":                       "Zoom",
+		"https://teams.microsoft.com/l/meetup-join/19%3ameeting_abc": "Teams",
+		"https://teams.live.com/meet/9876":                           "Teams",
+		"https://meet.google.com/abc-defg-hij":                       "Meet",
+		"https://example.webex.com/meet/someone":                     "Webex",
+		"https://zoom.us/pricing":                                    "",
+		"https://docs.google.com/document/d/xyz":                     "",
+	}
+
+	for url, want := range tests {
+		t.Run(url, func(t *testing.T) {
+			assert.Equal(t, want, meetingProviderOf(url))
+		})
+	}
+}
+
+func TestEventLink(t *testing.T) {
+	tests := []struct {
+		name  string
+		event domain.Event
+		want  string
+	}{
+		{
+			name:  "会議URLを資料URLより優先",
+			event: domain.Event{Description: "資料 https://docs.example.com/a\n参加 https://us02web.zoom.us/j/123"},
+			want:  "Zoom: https://us02web.zoom.us/j/123",
+		},
+		{
+			name:  "カレンダーの会議情報を優先",
+			event: domain.Event{ConferenceURL: "https://meet.google.com/abc-defg-hij", Description: "https://zoom.us/j/123"},
+			want:  "Meet: https://meet.google.com/abc-defg-hij",
+		},
+		{
+			name:  "会議URLがなければ最初のURL",
+			event: domain.Event{Location: "本社 https://maps.example.com/hq", Description: "https://docs.example.com/a"},
+			want:  "https://maps.example.com/hq",
+		},
+		{
+			name:  "URLなし",
+			event: domain.Event{Location: "会議室A"},
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, eventLink(tt.event))
+		})
+	}
+}
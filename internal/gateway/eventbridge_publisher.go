@@ -0,0 +1,133 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/usecase"
+)
+
+const (
+	// eventBridgeSource EventBridgeに発行するイベントのソース
+	eventBridgeSource = "google-calendar-line-notifier"
+	// eventBridgeDetailType EventBridgeに発行するイベントの種別
+	eventBridgeDetailType = "CalendarEventsFetched"
+)
+
+// EventBridgePutEventsAPI は EventBridge へのイベント発行を抽象化する
+type EventBridgePutEventsAPI interface {
+	PutEvents(ctx context.Context, params *eventbridge.PutEventsInput, optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error)
+}
+
+// publishedEvents EventBridgeに発行するイベントの詳細（detail）
+type publishedEvents struct {
+	Date   string           `json:"date"`
+	Events []publishedEvent `json:"events"`
+}
+
+// publishedEvent 他システム向けに正規化した予定
+type publishedEvent struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	AllDay     bool      `json:"allDay"`
+	Location   string    `json:"location,omitempty"`
+	Source     string    `json:"source,omitempty"`
+	IsDeadline bool      `json:"isDeadline,omitempty"`
+	Importance int       `json:"importance,omitempty"`
+}
+
+// EventBridgePublisher 取得した予定をEventBridgeのイベントバスに発行する
+type EventBridgePublisher struct {
+	client  EventBridgePutEventsAPI
+	busName string
+}
+
+// NewEventBridgePublisher イベントバス名を指定してEventBridgePublisherを作成
+func NewEventBridgePublisher(client EventBridgePutEventsAPI, busName string) *EventBridgePublisher {
+	return &EventBridgePublisher{client: client, busName: busName}
+}
+
+// Publish 指定日の予定を1件のイベントとして発行する
+func (p *EventBridgePublisher) Publish(ctx context.Context, targetDate time.Time, events []domain.Event) error {
+	detail := publishedEvents{
+		Date:   targetDate.Format("2006-01-02"),
+		Events: make([]publishedEvent, 0, len(events)),
+	}
+	for _, event := range events {
+		detail.Events = append(detail.Events, publishedEvent{
+			ID:         event.ID,
+			Title:      event.Title,
+			Start:      event.StartTime,
+			End:        event.EndTime,
+			AllDay:     event.IsAllDay,
+			Location:   event.Location,
+			Source:     event.Source,
+			IsDeadline: event.IsDeadline,
+			Importance: event.Importance,
+		})
+	}
+
+	body, err := json.Marshal(detail)
+	if err != nil {
+		return fmt.Errorf("イベント詳細のJSON変換に失敗しました: %v", err)
+	}
+
+	output, err := p.client.PutEvents(ctx, &eventbridge.PutEventsInput{
+		Entries: []types.PutEventsRequestEntry{{
+			EventBusName: aws.String(p.busName),
+			Source:       aws.String(eventBridgeSource),
+			DetailType:   aws.String(eventBridgeDetailType),
+			Detail:       aws.String(string(body)),
+			Time:         aws.Time(targetDate),
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("EventBridgeへのイベント発行に失敗しました: %v", err)
+	}
+	if output.FailedEntryCount > 0 {
+		message := ""
+		if len(output.Entries) > 0 && output.Entries[0].ErrorMessage != nil {
+			message = *output.Entries[0].ErrorMessage
+		}
+		return fmt.Errorf("EventBridgeへのイベント発行に失敗しました: %s", message)
+	}
+	return nil
+}
+
+// EventsPublisher 取得した予定を外部に発行する
+type EventsPublisher interface {
+	Publish(ctx context.Context, targetDate time.Time, events []domain.Event) error
+}
+
+// PublishingCalendarRepository 取得した予定をEventsPublisherにも発行するデコレーター
+// 発行に失敗しても予定の取得結果はそのまま返す
+type PublishingCalendarRepository struct {
+	next      usecase.CalendarRepository
+	publisher EventsPublisher
+}
+
+// NewPublishingCalendarRepository nextから取得した予定をpublisherに発行するリポジトリを作成
+func NewPublishingCalendarRepository(next usecase.CalendarRepository, publisher EventsPublisher) *PublishingCalendarRepository {
+	return &PublishingCalendarRepository{next: next, publisher: publisher}
+}
+
+// GetEvents 指定日のイベントを取得し、発行してから返す
+func (r *PublishingCalendarRepository) GetEvents(ctx context.Context, targetDate time.Time) ([]domain.Event, error) {
+	events, err := r.next.GetEvents(ctx, targetDate)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.publisher.Publish(ctx, targetDate, events); err != nil {
+		fmt.Printf("Warning: 予定の発行に失敗しました: %v\n", err)
+	}
+	return events, nil
+}
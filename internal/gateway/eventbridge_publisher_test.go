@@ -0,0 +1,102 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/fakes"
+)
+
+type fakeEventBridgeClient struct {
+	input  *eventbridge.PutEventsInput
+	output *eventbridge.PutEventsOutput
+	err    error
+}
+
+func (f *fakeEventBridgeClient) PutEvents(_ context.Context, params *eventbridge.PutEventsInput, _ ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error) {
+	f.input = params
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.output != nil {
+		return f.output, nil
+	}
+	return &eventbridge.PutEventsOutput{}, nil
+}
+
+func TestEventBridgePublisher_Publish(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, jst)
+	client := &fakeEventBridgeClient{}
+	publisher := NewEventBridgePublisher(client, "home-bus")
+
+	err := publisher.Publish(context.Background(), date, []domain.Event{
+		{ID: "ev1", Title: "定例", StartTime: date.Add(10 * time.Hour), EndTime: date.Add(11 * time.Hour), Source: "Google", Importance: 2},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, client.input.Entries, 1)
+	entry := client.input.Entries[0]
+	assert.Equal(t, "home-bus", *entry.EventBusName)
+	assert.Equal(t, "google-calendar-line-notifier", *entry.Source)
+	assert.Equal(t, "CalendarEventsFetched", *entry.DetailType)
+
+	var detail publishedEvents
+	require.NoError(t, json.Unmarshal([]byte(*entry.Detail), &detail))
+	assert.Equal(t, "2024-01-15", detail.Date)
+	require.Len(t, detail.Events, 1)
+	assert.Equal(t, "定例", detail.Events[0].Title)
+	assert.Equal(t, "Google", detail.Events[0].Source)
+	assert.True(t, detail.Events[0].Start.Equal(date.Add(10*time.Hour)))
+}
+
+func TestEventBridgePublisher_FailedEntry(t *testing.T) {
+	client := &fakeEventBridgeClient{output: &eventbridge.PutEventsOutput{
+		FailedEntryCount: 1,
+		Entries:          []types.PutEventsResultEntry{{ErrorMessage: aws.String("bus not found")}},
+	}}
+
+	err := NewEventBridgePublisher(client, "missing").Publish(context.Background(), time.Now(), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bus not found")
+}
+
+type stubEventsPublisher struct {
+	published []domain.Event
+	err       error
+}
+
+func (s *stubEventsPublisher) Publish(_ context.Context, _ time.Time, events []domain.Event) error {
+	s.published = events
+	return s.err
+}
+
+func TestPublishingCalendarRepository_GetEvents(t *testing.T) {
+	events := []domain.Event{{Title: "定例"}}
+	repo := fakes.CalendarRepository{Events: events}
+
+	t.Run("取得した予定を発行する", func(t *testing.T) {
+		publisher := &stubEventsPublisher{}
+		got, err := NewPublishingCalendarRepository(repo, publisher).GetEvents(context.Background(), time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, events, got)
+		assert.Equal(t, events, publisher.published)
+	})
+
+	t.Run("発行に失敗しても予定を返す", func(t *testing.T) {
+		publisher := &stubEventsPublisher{err: errors.New("throttled")}
+		got, err := NewPublishingCalendarRepository(repo, publisher).GetEvents(context.Background(), time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, events, got)
+	})
+}
@@ -0,0 +1,53 @@
+package gateway
+
+import (
+	"sync"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// maxEventsCacheEntries キャッシュするレスポンスの上限件数
+const maxEventsCacheEntries = 64
+
+// sharedEventsCache Lambdaのウォームスタート間で共有するEvents.Listのキャッシュ
+var sharedEventsCache = newEventsCache()
+
+// eventsCacheEntry ETagとともに保持するEvents.Listのレスポンス
+type eventsCacheEntry struct {
+	etag  string
+	items []*calendar.Event
+}
+
+// eventsCache カレンダー・期間ごとにEvents.Listのレスポンスを保持するメモリキャッシュ
+type eventsCache struct {
+	mu      sync.Mutex
+	entries map[string]eventsCacheEntry
+}
+
+// newEventsCache 空のキャッシュを作成
+func newEventsCache() *eventsCache {
+	return &eventsCache{entries: make(map[string]eventsCacheEntry)}
+}
+
+// get キーに対応するキャッシュを取得
+func (c *eventsCache) get(key string) (eventsCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+// put レスポンスをキャッシュに保存（上限に達した場合は全件破棄してから保存）
+func (c *eventsCache) put(key string, entry eventsCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= maxEventsCacheEntries {
+		c.entries = make(map[string]eventsCacheEntry)
+	}
+	c.entries[key] = entry
+}
+
+// eventsCacheKey カレンダーIDと期間からキャッシュキーを生成
+func eventsCacheKey(calendarID, timeMin, timeMax string) string {
+	return calendarID + "|" + timeMin + "|" + timeMax
+}
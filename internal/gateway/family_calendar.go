@@ -0,0 +1,84 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/usecase"
+)
+
+// EventFilter タイトルのキーワードで予定を絞り込む条件
+// ゼロ値は全ての予定を対象にする
+type EventFilter struct {
+	// Include 空でない場合、タイトルにいずれかのキーワードを含む予定のみを対象にする
+	Include []string
+	// Exclude タイトルにいずれかのキーワードを含む予定は対象外にする
+	Exclude []string
+	// ExcludeAllDay trueの場合、終日の予定は対象外にする
+	ExcludeAllDay bool
+}
+
+// Match 予定が絞り込み条件に一致するかどうか
+func (f EventFilter) Match(event domain.Event) bool {
+	if f.ExcludeAllDay && event.IsAllDay {
+		return false
+	}
+	if containsAny(event.Title, f.Exclude) {
+		return false
+	}
+	return len(f.Include) == 0 || containsAny(event.Title, f.Include)
+}
+
+// containsAny sにkeywordsのいずれかが含まれるかどうか（空のキーワードは無視する）
+func containsAny(s string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if keyword != "" && strings.Contains(s, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// FamilyCalendarRepository 家族（パートナー）のカレンダーの予定を絞り込み、家族の予定として印を付けるデコレーター
+// 家族の予定は通知メッセージの「家族」セクションに分けて表示する
+type FamilyCalendarRepository struct {
+	next   usecase.CalendarRepository
+	filter EventFilter
+}
+
+// NewFamilyCalendarRepository nextから取得した予定のうちfilterに一致するものを家族の予定として返すリポジトリを作成
+func NewFamilyCalendarRepository(next usecase.CalendarRepository, filter EventFilter) *FamilyCalendarRepository {
+	return &FamilyCalendarRepository{next: next, filter: filter}
+}
+
+// GetEvents 指定日の家族の予定を取得
+func (r *FamilyCalendarRepository) GetEvents(ctx context.Context, targetDate time.Time) ([]domain.Event, error) {
+	events, err := r.next.GetEvents(ctx, targetDate)
+	if err != nil {
+		return nil, err
+	}
+
+	family := make([]domain.Event, 0, len(events))
+	for _, event := range events {
+		if !r.filter.Match(event) {
+			continue
+		}
+		event.IsFamily = true
+		family = append(family, event)
+	}
+	return family, nil
+}
+
+// splitFamily イベントを自分の予定と家族の予定に分ける
+func splitFamily(events []domain.Event) (own, family []domain.Event) {
+	for _, event := range events {
+		if event.IsFamily {
+			family = append(family, event)
+		} else {
+			own = append(own, event)
+		}
+	}
+	return own, family
+}
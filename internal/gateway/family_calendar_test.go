@@ -0,0 +1,61 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/fakes"
+)
+
+func TestEventFilter_Match(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter EventFilter
+		event  domain.Event
+		want   bool
+	}{
+		{name: "ゼロ値は全て対象", event: domain.Event{Title: "買い物"}, want: true},
+		{name: "Includeに一致", filter: EventFilter{Include: []string{"保育園", "学校"}}, event: domain.Event{Title: "保育園お迎え"}, want: true},
+		{name: "Includeに一致しない", filter: EventFilter{Include: []string{"保育園"}}, event: domain.Event{Title: "ジム"}, want: false},
+		{name: "Excludeを優先", filter: EventFilter{Include: []string{"保育園"}, Exclude: []string{"[private]"}}, event: domain.Event{Title: "保育園 [private]"}, want: false},
+		{name: "終日を除外", filter: EventFilter{ExcludeAllDay: true}, event: domain.Event{Title: "出張", IsAllDay: true}, want: false},
+		{name: "空のキーワードは無視", filter: EventFilter{Exclude: []string{""}}, event: domain.Event{Title: "買い物"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Match(tt.event))
+		})
+	}
+}
+
+func TestFamilyCalendarRepository_GetEvents(t *testing.T) {
+	next := fakes.CalendarRepository{Events: []domain.Event{
+		{Title: "保育園お迎え"},
+		{Title: "ジム"},
+		{Title: "授業参観", IsAllDay: true},
+	}}
+	repo := NewFamilyCalendarRepository(next, EventFilter{Exclude: []string{"ジム"}})
+
+	events, err := repo.GetEvents(context.Background(), time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "保育園お迎え", events[0].Title)
+	assert.Equal(t, "授業参観", events[1].Title)
+	for _, event := range events {
+		assert.True(t, event.IsFamily)
+	}
+}
+
+func TestFamilyCalendarRepository_Error(t *testing.T) {
+	repo := NewFamilyCalendarRepository(fakes.CalendarRepository{Err: errors.New("forbidden")}, EventFilter{})
+
+	_, err := repo.GetEvents(context.Background(), time.Now())
+	assert.Error(t, err)
+}
@@ -0,0 +1,130 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/sanitize"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/textutil"
+)
+
+const (
+	// DefaultFlashBriefingKey フラッシュブリーフィングのフィードを保存するデフォルトのS3キー
+	DefaultFlashBriefingKey = "briefing/flash-briefing.json"
+	// flashBriefingMaxText フラッシュブリーフィングの本文の最大文字数
+	flashBriefingMaxText = 4500
+)
+
+// flashBriefingItem Alexaフラッシュブリーフィングのフィード項目
+type flashBriefingItem struct {
+	UID            string `json:"uid"`
+	UpdateDate     string `json:"updateDate"`
+	TitleText      string `json:"titleText"`
+	MainText       string `json:"mainText"`
+	RedirectionURL string `json:"redirectionUrl"`
+}
+
+// FlashBriefingWriter 予定を読み上げ用の文章にしてAlexaフラッシュブリーフィングのフィードとしてS3に保存する
+// フィードのURL（S3の公開URLやCloudFront経由のURL）をAlexaスキルに登録して使う
+type FlashBriefingWriter struct {
+	client S3PutObjectAPI
+	bucket string
+	key    string
+}
+
+// NewFlashBriefingWriter 保存先のバケットとキーを指定してFlashBriefingWriterを作成
+// keyが空の場合はDefaultFlashBriefingKeyを使用する
+func NewFlashBriefingWriter(client S3PutObjectAPI, bucket, key string) *FlashBriefingWriter {
+	if key == "" {
+		key = DefaultFlashBriefingKey
+	}
+	return &FlashBriefingWriter{client: client, bucket: bucket, key: key}
+}
+
+// SendScheduleNotification 本日と翌日の予定をフラッシュブリーフィングのフィードとして保存
+func (w *FlashBriefingWriter) SendScheduleNotification(ctx context.Context, report domain.ScheduleReport) error {
+	today := report.Today.Date
+
+	item := flashBriefingItem{
+		UID:            "urn:google-calendar-line-notifier:" + today.Format("2006-01-02"),
+		UpdateDate:     report.GeneratedAt.UTC().Format("2006-01-02T15:04:05.0Z"),
+		TitleText:      fmt.Sprintf("%sの予定", today.Format("1月2日")),
+		MainText:       textutil.Truncate(briefingText(report), flashBriefingMaxText),
+		RedirectionURL: googleCalendarDayURL(today),
+	}
+
+	body, err := json.Marshal([]flashBriefingItem{item})
+	if err != nil {
+		return fmt.Errorf("フラッシュブリーフィングのJSON変換に失敗しました: %v", err)
+	}
+
+	_, err = w.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:       aws.String(w.bucket),
+		Key:          aws.String(w.key),
+		Body:         bytes.NewReader(body),
+		ContentType:  aws.String("application/json; charset=utf-8"),
+		CacheControl: aws.String("max-age=300"),
+	})
+	if err != nil {
+		return fmt.Errorf("フラッシュブリーフィングのアップロードに失敗しました: %v", err)
+	}
+	return nil
+}
+
+// briefingText 読み上げ用の予定の文章を作成
+func briefingText(report domain.ScheduleReport) string {
+	var builder strings.Builder
+	appendBriefingDay(&builder, "今日", report.Today)
+	builder.WriteString(" ")
+	appendBriefingDay(&builder, "明日", report.Tomorrow)
+	return builder.String()
+}
+
+// appendBriefingDay 1日分の予定を読み上げ用の文章で追加
+func appendBriefingDay(builder *strings.Builder, label string, day domain.DaySchedule) {
+	own, family := splitFamily(day.Events)
+	schedules, deadlines := splitDeadlines(own)
+	dayText := fmt.Sprintf("%s、%s%s曜日", label, day.Date.Format("1月2日"), getWeekdayJapanese(day.Date.Weekday()))
+
+	if len(schedules) == 0 {
+		builder.WriteString(fmt.Sprintf("%sの予定はありません。", dayText))
+	} else {
+		builder.WriteString(fmt.Sprintf("%sの予定は%d件です。", dayText, len(schedules)))
+		for _, event := range schedules {
+			title := strings.TrimSpace(sanitize.StripEmoji(event.Title))
+			if event.IsAllDay {
+				builder.WriteString(fmt.Sprintf("終日、%s。", title))
+			} else {
+				builder.WriteString(fmt.Sprintf("%s、%s。", spokenTime(event.StartTime), title))
+			}
+		}
+	}
+
+	for _, deadline := range deadlines {
+		builder.WriteString(fmt.Sprintf("締め切り、%s。", strings.TrimSpace(sanitize.StripEmoji(deadline.Title))))
+	}
+	for _, event := range family {
+		title := strings.TrimSpace(sanitize.StripEmoji(event.Title))
+		if event.IsAllDay {
+			builder.WriteString(fmt.Sprintf("家族の予定、終日、%s。", title))
+		} else {
+			builder.WriteString(fmt.Sprintf("家族の予定、%s、%s。", spokenTime(event.StartTime), title))
+		}
+	}
+}
+
+// spokenTime 時刻を「10時30分から」のような読み上げ用の表記にする
+func spokenTime(t time.Time) string {
+	if t.Minute() == 0 {
+		return fmt.Sprintf("%d時から", t.Hour())
+	}
+	return fmt.Sprintf("%d時%d分から", t.Hour(), t.Minute())
+}
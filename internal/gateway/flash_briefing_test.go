@@ -0,0 +1,86 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+func TestFlashBriefingWriter_SendScheduleNotification(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, jst)
+	client := &fakeS3Client{}
+	writer := NewFlashBriefingWriter(client, "briefing-bucket", "")
+
+	today := []domain.Event{
+		{Title: "休暇", StartTime: time.Date(2024, 1, 15, 0, 0, 0, 0, jst), IsAllDay: true},
+		{Title: "🎉 定例会議", StartTime: time.Date(2024, 1, 15, 10, 30, 0, 0, jst)},
+		{Title: "レポート提出", StartTime: time.Date(2024, 1, 15, 18, 0, 0, 0, jst), IsDeadline: true},
+	}
+
+	report := domain.NewScheduleReport(now, time.Date(2024, 1, 15, 0, 0, 0, 0, jst), time.Date(2024, 1, 16, 0, 0, 0, 0, jst), today, nil)
+	err := writer.SendScheduleNotification(context.Background(), report)
+	require.NoError(t, err)
+
+	assert.Equal(t, "briefing-bucket", *client.input.Bucket)
+	assert.Equal(t, DefaultFlashBriefingKey, *client.input.Key)
+	assert.Equal(t, "application/json; charset=utf-8", *client.input.ContentType)
+
+	var items []flashBriefingItem
+	require.NoError(t, json.Unmarshal([]byte(client.body), &items))
+	require.Len(t, items, 1)
+	assert.Equal(t, "urn:google-calendar-line-notifier:2024-01-15", items[0].UID)
+	assert.Equal(t, "2024-01-14T22:00:00.0Z", items[0].UpdateDate)
+	assert.Equal(t, "1月15日の予定", items[0].TitleText)
+	assert.Equal(t,
+		"今日、1月15日月曜日の予定は2件です。終日、休暇。10時30分から、定例会議。締め切り、レポート提出。 明日、1月16日火曜日の予定はありません。",
+		items[0].MainText)
+}
+
+func TestFlashBriefingWriter_UploadError(t *testing.T) {
+	client := &fakeS3Client{err: errors.New("access denied")}
+	writer := NewFlashBriefingWriter(client, "briefing-bucket", "custom/feed.json")
+
+	err := writer.SendScheduleNotification(context.Background(), domain.ScheduleReport{})
+	assert.ErrorContains(t, err, "フラッシュブリーフィングのアップロードに失敗しました")
+}
+
+type recordingNotifier struct {
+	calls int
+	err   error
+}
+
+func (r *recordingNotifier) SendScheduleNotification(_ context.Context, _ domain.ScheduleReport) error {
+	r.calls++
+	return r.err
+}
+
+func TestMultiNotifier(t *testing.T) {
+	t.Run("補助的な出力先の失敗は無視する", func(t *testing.T) {
+		primary := &recordingNotifier{}
+		failing := &recordingNotifier{err: errors.New("boom")}
+		other := &recordingNotifier{}
+
+		err := NewMultiNotifier(primary, failing, other).SendScheduleNotification(context.Background(), domain.ScheduleReport{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, primary.calls)
+		assert.Equal(t, 1, failing.calls)
+		assert.Equal(t, 1, other.calls)
+	})
+
+	t.Run("主の通知先が失敗した場合は補助的な出力先に送らない", func(t *testing.T) {
+		primary := &recordingNotifier{err: errors.New("line down")}
+		secondary := &recordingNotifier{}
+
+		err := NewMultiNotifier(primary, secondary).SendScheduleNotification(context.Background(), domain.ScheduleReport{})
+		assert.EqualError(t, err, "line down")
+		assert.Equal(t, 0, secondary.calls)
+	})
+}
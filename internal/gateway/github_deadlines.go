@@ -0,0 +1,162 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+const (
+	// DefaultGitHubAPIBaseURL GitHub REST APIのデフォルトのベースURL
+	DefaultGitHubAPIBaseURL = "https://api.github.com"
+	// githubIssuesPerPage 1ページあたりの取得件数
+	githubIssuesPerPage = 100
+	// githubMaxPages 取得するページ数の上限
+	githubMaxPages = 10
+)
+
+// githubIssue GitHub Issues APIのレスポンス構造体（必要な項目のみ）
+type githubIssue struct {
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	HTMLURL    string `json:"html_url"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Milestone *struct {
+		Title string     `json:"title"`
+		DueOn *time.Time `json:"due_on"`
+	} `json:"milestone"`
+}
+
+// GitHubDeadlineRepository 自分にアサインされたIssue/PRのマイルストーン期限を締め切りとして取得するCalendarRepositoryの実装
+type GitHubDeadlineRepository struct {
+	token      string
+	baseURL    string
+	httpClient *http.Client
+	timezone   *time.Location
+}
+
+// NewGitHubDeadlineRepository GitHub締め切りリポジトリを作成
+// baseURLが空の場合はDefaultGitHubAPIBaseURL、transportがnilの場合はhttp.DefaultTransportを使用する
+func NewGitHubDeadlineRepository(token, baseURL string, transport http.RoundTripper, timezone *time.Location) *GitHubDeadlineRepository {
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPIBaseURL
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &GitHubDeadlineRepository{
+		token:   token,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   30 * time.Second,
+		},
+		timezone: timezone,
+	}
+}
+
+// GetEvents 指定された日が期限のマイルストーンに紐づくIssue/PRを締め切りとして取得
+func (r *GitHubDeadlineRepository) GetEvents(ctx context.Context, targetDate time.Time) ([]domain.Event, error) {
+	issues, err := r.listAssignedIssues(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := targetDate.In(r.timezone).Format("2006-01-02")
+	deadlines := make([]domain.Event, 0)
+	for _, issue := range issues {
+		if issue.Milestone == nil || issue.Milestone.DueOn == nil {
+			continue
+		}
+		dueOn := issue.Milestone.DueOn.In(r.timezone)
+		if dueOn.Format("2006-01-02") != target {
+			continue
+		}
+		deadlines = append(deadlines, convertGitHubIssue(issue, dueOn))
+	}
+	return deadlines, nil
+}
+
+// listAssignedIssues 自分にアサインされたオープンなIssue/PRを全ページ取得
+func (r *GitHubDeadlineRepository) listAssignedIssues(ctx context.Context) ([]githubIssue, error) {
+	var issues []githubIssue
+	for page := 1; page <= githubMaxPages; page++ {
+		url := fmt.Sprintf("%s/issues?filter=assigned&state=open&per_page=%d&page=%d", r.baseURL, githubIssuesPerPage, page)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", r.token))
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+		pageIssues, err := r.doListRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, pageIssues...)
+		if len(pageIssues) < githubIssuesPerPage {
+			break
+		}
+	}
+	return issues, nil
+}
+
+// doListRequest Issues APIを呼び出してレスポンスを解析
+func (r *GitHubDeadlineRepository) doListRequest(req *http.Request) ([]githubIssue, error) {
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, &APIError{
+			Service: "GitHub",
+			Kind:    ErrorKindTransient,
+			Message: err.Error(),
+			Err:     err,
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := readErrorBody(resp.Body)
+		kind := classifyStatus(resp.StatusCode)
+		// GitHubはレート制限超過を403で返す
+		if resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			kind = ErrorKindRateLimit
+		}
+		return nil, &APIError{
+			Service:    "GitHub",
+			StatusCode: resp.StatusCode,
+			Kind:       kind,
+			Message:    truncateBody(body),
+		}
+	}
+
+	var issues []githubIssue
+	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
+		return nil, fmt.Errorf("GitHub APIレスポンスの解析に失敗しました: %v", err)
+	}
+	return issues, nil
+}
+
+// convertGitHubIssue GitHubのIssue/PRを締め切りイベントに変換
+func convertGitHubIssue(issue githubIssue, dueOn time.Time) domain.Event {
+	title := fmt.Sprintf("%s#%d %s [%s]", issue.Repository.FullName, issue.Number, issue.Title, issue.Milestone.Title)
+	dueDate := dates.StartOfDay(dueOn)
+
+	return domain.Event{
+		ID:          issue.HTMLURL,
+		Title:       title,
+		StartTime:   dueDate,
+		EndTime:     dates.AddDays(dueDate, 1),
+		IsAllDay:    true,
+		Description: issue.HTMLURL,
+		IsDeadline:  true,
+	}
+}
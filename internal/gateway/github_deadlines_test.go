@@ -0,0 +1,108 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// githubIssuesJSON テスト用のIssues APIレスポンス
+const githubIssuesJSON = `[
+  {
+    "number": 12,
+    "title": "リリースノート作成",
+    "html_url": "https://github.com/example/app/issues/12",
+    "repository": {"full_name": "example/app"},
+    "milestone": {"title": "v1.0", "due_on": "2024-01-15T08:00:00Z"}
+  },
+  {
+    "number": 34,
+    "title": "翌日期限のPR",
+    "html_url": "https://github.com/example/app/pull/34",
+    "repository": {"full_name": "example/app"},
+    "milestone": {"title": "v1.1", "due_on": "2024-01-16T08:00:00Z"}
+  },
+  {
+    "number": 56,
+    "title": "マイルストーンなし",
+    "html_url": "https://github.com/example/app/issues/56",
+    "repository": {"full_name": "example/app"},
+    "milestone": null
+  }
+]`
+
+func TestGitHubDeadlineRepository_GetEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/issues", r.URL.Path)
+		assert.Equal(t, "assigned", r.URL.Query().Get("filter"))
+		assert.Equal(t, "Bearer gh-token", r.Header.Get("Authorization"))
+		_, err := w.Write([]byte(githubIssuesJSON))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	repo := NewGitHubDeadlineRepository("gh-token", server.URL, nil, jst)
+
+	result, err := repo.GetEvents(context.Background(), time.Date(2024, 1, 15, 0, 0, 0, 0, jst))
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "example/app#12 リリースノート作成 [v1.0]", result[0].Title)
+	assert.True(t, result[0].IsDeadline)
+	assert.True(t, result[0].IsAllDay)
+	assert.Equal(t, 15, result[0].StartTime.Day())
+}
+
+func TestGitHubDeadlineRepository_Pagination(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("page") == "1" {
+			// 1ページ目は上限件数分を返す
+			_, _ = w.Write([]byte("["))
+			for i := 0; i < githubIssuesPerPage; i++ {
+				if i > 0 {
+					_, _ = w.Write([]byte(","))
+				}
+				_, _ = fmt.Fprintf(w, `{"number":%d,"title":"issue","repository":{"full_name":"example/app"}}`, i)
+			}
+			_, _ = w.Write([]byte("]"))
+			return
+		}
+		_, err := w.Write([]byte(githubIssuesJSON))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	repo := NewGitHubDeadlineRepository("gh-token", server.URL, nil, jst)
+
+	result, err := repo.GetEvents(context.Background(), time.Date(2024, 1, 16, 0, 0, 0, 0, jst))
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	require.Len(t, result, 1)
+	assert.Contains(t, result[0].Title, "翌日期限のPR")
+}
+
+func TestGitHubDeadlineRepository_RateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusForbidden)
+		_, err := w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	repo := NewGitHubDeadlineRepository("gh-token", server.URL, nil, jst)
+
+	_, err := repo.GetEvents(context.Background(), time.Date(2024, 1, 15, 0, 0, 0, 0, jst))
+	require.Error(t, err)
+	assert.Equal(t, ErrorKindRateLimit, ErrorKindOf(err))
+}
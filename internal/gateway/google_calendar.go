@@ -3,13 +3,20 @@ package gateway
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"sort"
 	"time"
 
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
 	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
 )
 
 // EventsProvider はカレンダーイベントの取得を抽象化する
@@ -20,97 +27,205 @@ type EventsProvider interface {
 // googleEventsProvider は Google Calendar API を使用した EventsProvider の実装
 type googleEventsProvider struct {
 	service *calendar.Service
+	cache   *eventsCache
 }
 
+// eventsPageSize Events.Listの1ページあたりの件数
+const eventsPageSize = 50
+
+// ListEvents 期間内のイベントを取得する
+// 1ページに収まらない場合はNextPageTokenを辿って全件を取得する
+// 前回取得時のETagを送信し、変更がなければ(304 Not Modified)キャッシュ済みのイベントを返す
+// （ETagはカレンダー全体の変更で変わるため、1ページ目のETagで全ページ分をキャッシュする）
 func (p *googleEventsProvider) ListEvents(calendarID, timeMin, timeMax string) ([]*calendar.Event, error) {
-	eventsCall := p.service.Events.List(calendarID).
-		TimeMin(timeMin).
-		TimeMax(timeMax).
-		SingleEvents(true).
-		OrderBy("startTime").
-		MaxResults(50)
+	eventsCall := p.listCall(calendarID, timeMin, timeMax)
+
+	key := eventsCacheKey(calendarID, timeMin, timeMax)
+	cached, hasCache := p.cache.get(key)
+	if hasCache {
+		eventsCall = eventsCall.IfNoneMatch(cached.etag)
+	}
 
 	events, err := eventsCall.Do()
 	if err != nil {
+		if hasCache && googleapi.IsNotModified(err) {
+			return cached.items, nil
+		}
 		return nil, err
 	}
-	return events.Items, nil
+
+	items := events.Items
+	for pageToken := events.NextPageToken; pageToken != ""; {
+		page, err := p.listCall(calendarID, timeMin, timeMax).PageToken(pageToken).Do()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+		pageToken = page.NextPageToken
+	}
+
+	if events.Etag != "" {
+		p.cache.put(key, eventsCacheEntry{etag: events.Etag, items: items})
+	}
+	return items, nil
+}
+
+// listCall 期間内のイベントを開始時刻順に取得するEvents.Listの呼び出しを作成
+func (p *googleEventsProvider) listCall(calendarID, timeMin, timeMax string) *calendar.EventsListCall {
+	return p.service.Events.List(calendarID).
+		TimeMin(timeMin).
+		TimeMax(timeMax).
+		SingleEvents(true).
+		OrderBy("startTime").
+		MaxResults(eventsPageSize)
 }
 
 // GoogleCalendarRepository Google Calendar APIを使用したCalendarRepositoryの実装
+// 複数のカレンダーを指定した場合は、全カレンダーの予定をまとめて取得する
 type GoogleCalendarRepository struct {
-	provider   EventsProvider
-	calendarID string
-	timezone   *time.Location
+	provider    EventsProvider
+	calendarIDs []string
+	timezone    *time.Location
 }
 
-// NewGoogleCalendarRepository Google Calendarリポジトリを作成
-func NewGoogleCalendarRepository(credentialsJSON []byte, calendarID string) (*GoogleCalendarRepository, error) {
-	// JST固定でタイムゾーンを設定
-	timezone, err := time.LoadLocation("Asia/Tokyo")
+// NewGoogleCalendarRepository 取得するカレンダーのIDを指定してGoogle Calendarリポジトリを作成
+// endpointが空の場合はGoogle Calendar APIのデフォルトエンドポイント、
+// transportがnilの場合はhttp.DefaultTransportを使用する
+func NewGoogleCalendarRepository(credentialsJSON []byte, calendarIDs []string, endpoint string, transport http.RoundTripper) (*GoogleCalendarRepository, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	provider, err := newGoogleEventsProvider(credentialsJSON, endpoint, transport, calendar.CalendarReadonlyScope)
 	if err != nil {
-		return nil, fmt.Errorf("JSTタイムゾーンの読み込みに失敗しました: %v", err)
+		return nil, err
 	}
+	return NewGoogleCalendarRepositoryWithProvider(provider, calendarIDs, dates.Location()), nil
+}
 
-	// サービスアカウント認証でCalendar APIクライアントを作成
+// newGoogleEventsProvider サービスアカウント認証でCalendar APIクライアントを作成
+func newGoogleEventsProvider(credentialsJSON []byte, endpoint string, base http.RoundTripper, scope string) (*googleEventsProvider, error) {
 	creds, err := google.CredentialsFromJSON(
 		context.Background(),
 		credentialsJSON,
-		calendar.CalendarReadonlyScope,
+		scope,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("google認証情報の読み込みに失敗しました: %v", err)
 	}
 
-	service, err := calendar.NewService(
-		context.Background(),
-		option.WithCredentials(creds),
-	)
+	// エラーレスポンスの読み込み量を制限したHTTPクライアントで認証する
+	baseClient := &http.Client{
+		Transport: &limitErrorBodyTransport{base: base},
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, baseClient)
+
+	opts := []option.ClientOption{
+		option.WithHTTPClient(oauth2.NewClient(ctx, creds.TokenSource)),
+	}
+	if endpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoint))
+	}
+
+	service, err := calendar.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("google Calendar APIサービスの作成に失敗しました: %v", err)
 	}
 
-	provider := &googleEventsProvider{service: service}
-	return NewGoogleCalendarRepositoryWithProvider(provider, calendarID, timezone), nil
+	return &googleEventsProvider{service: service, cache: sharedEventsCache}, nil
 }
 
 // NewGoogleCalendarRepositoryWithProvider EventsProviderを指定してリポジトリを作成（テスト用）
-func NewGoogleCalendarRepositoryWithProvider(provider EventsProvider, calendarID string, timezone *time.Location) *GoogleCalendarRepository {
+func NewGoogleCalendarRepositoryWithProvider(provider EventsProvider, calendarIDs []string, timezone *time.Location) *GoogleCalendarRepository {
 	return &GoogleCalendarRepository{
-		provider:   provider,
-		calendarID: calendarID,
-		timezone:   timezone,
+		provider:    provider,
+		calendarIDs: calendarIDs,
+		timezone:    timezone,
+	}
+}
+
+// SetSyncStore 予定をsyncTokenによる差分取得で取得するように設定
+// syncTokenと取得した予定はカレンダーごとにストアに保存する
+func (r *GoogleCalendarRepository) SetSyncStore(s store.Store) {
+	syncer, ok := r.provider.(EventsSyncer)
+	if !ok {
+		fmt.Printf("Warning: このカレンダーの取得元は差分取得に対応していないため、毎回全件を取得します\n")
+		return
 	}
+	r.provider = NewSyncingEventsProvider(syncer, s)
 }
 
-// GetEvents 指定された日の予定を取得
+// GetEvents 指定された日の予定を全カレンダーから取得し、開始時刻順に返す
+// 複数のカレンダーに同じ予定（招待された会議など）がある場合は、先に指定したカレンダーの予定のみ返す
 func (r *GoogleCalendarRepository) GetEvents(_ context.Context, targetDate time.Time) ([]domain.Event, error) {
-	// JST固定で開始時刻と終了時刻を設定
-	jst, _ := time.LoadLocation("Asia/Tokyo")
+	// カレンダーのタイムゾーンで開始時刻と終了時刻を設定
+	// 夏時間の切り替え日は1日が23時間・25時間になるため、24時間の加算ではなく翌日の0時を終了時刻にする
 
-	// 開始時刻: 指定日の00:00:00 (JST) - inclusive
-	startTimeInJST := time.Date(
+	// 開始時刻: 指定日の00:00:00 - inclusive
+	startTime := time.Date(
 		targetDate.Year(), targetDate.Month(), targetDate.Day(),
-		0, 0, 0, 0, jst,
+		0, 0, 0, 0, r.timezone,
 	)
 
-	// 終了時刻: 翌日の00:00:00 (JST) - exclusive
-	endTimeInJST := startTimeInJST.Add(24 * time.Hour)
+	// 終了時刻: 翌日の00:00:00 - exclusive
+	endTime := dates.AddDays(startTime, 1)
 
 	// RFC3339形式に変換（タイムゾーン情報付き）
-	timeMinStr := startTimeInJST.Format(time.RFC3339)
-	timeMaxStr := endTimeInJST.Format(time.RFC3339)
+	timeMinStr := startTime.Format(time.RFC3339)
+	timeMaxStr := endTime.Format(time.RFC3339)
 
+	results := make([][]domain.Event, len(r.calendarIDs))
+	var g errgroup.Group
+	for i, calendarID := range r.calendarIDs {
+		g.Go(func() error {
+			events, err := r.listEvents(calendarID, timeMinStr, timeMaxStr)
+			results[i] = events
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	if len(results) == 1 {
+		return results[0], nil
+	}
+
+	merged := make([]domain.Event, 0)
+	seen := make(map[string]bool)
+	for _, events := range results {
+		for _, event := range events {
+			if event.ID != "" && seen[event.ID] {
+				continue
+			}
+			seen[event.ID] = true
+			merged = append(merged, event)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].StartTime.Before(merged[j].StartTime)
+	})
+	return merged, nil
+}
+
+// listEvents 1つのカレンダーの期間内の予定を取得
+func (r *GoogleCalendarRepository) listEvents(calendarID, timeMin, timeMax string) ([]domain.Event, error) {
 	// EventsProvider経由でイベントを取得
-	items, err := r.provider.ListEvents(r.calendarID, timeMinStr, timeMaxStr)
+	items, err := r.provider.ListEvents(calendarID, timeMin, timeMax)
 	if err != nil {
-		return nil, fmt.Errorf("カレンダーイベントの取得に失敗しました: %v", err)
+		if len(r.calendarIDs) > 1 {
+			return nil, fmt.Errorf("カレンダー %s のイベントの取得に失敗しました: %w", calendarID, classifyGoogleError(err))
+		}
+		return nil, fmt.Errorf("カレンダーイベントの取得に失敗しました: %w", classifyGoogleError(err))
 	}
 
 	// イベントを変換
 	domainEvents := make([]domain.Event, 0, len(items))
 	for _, event := range items {
-		domainEvent, err := r.convertToEvent(event)
+		// キャンセル済みのイベントは通知対象外
+		if event.Status == "cancelled" {
+			continue
+		}
+		domainEvent, err := r.convertToEvent(calendarID, event)
 		if err != nil {
 			fmt.Printf("Warning: イベントの変換をスキップしました: %v\n", err)
 			continue
@@ -122,12 +237,30 @@ func (r *GoogleCalendarRepository) GetEvents(_ context.Context, targetDate time.
 }
 
 // convertToEvent Google Calendar APIのイベントをドメインエンティティに変換
-func (r *GoogleCalendarRepository) convertToEvent(event *calendar.Event) (domain.Event, error) {
+func (r *GoogleCalendarRepository) convertToEvent(calendarID string, event *calendar.Event) (domain.Event, error) {
 	domainEvent := domain.Event{
 		ID:          event.Id,
+		CalendarID:  calendarID,
 		Title:       event.Summary,
 		Location:    event.Location,
 		Description: event.Description,
+
+		AttendeeCount: len(event.Attendees),
+	}
+	if event.Organizer != nil {
+		domainEvent.Organizer = event.Organizer.Email
+	}
+	domainEvent.ConferenceURL = conferenceURL(event)
+	domainEvent.IsOnline = domainEvent.ConferenceURL != "" || meetingProviderOf(eventLinkURL(domainEvent)) != ""
+	domainEvent.WorkingLocation = workingLocation(event)
+	domainEvent.IsOutOfOffice = event.EventType == "outOfOffice"
+	domainEvent.Reminders = reminders(event)
+	domainEvent.NotifiedOn, domainEvent.NotifiedBy = notifiedStatus(event, r.timezone)
+	for _, attendee := range event.Attendees {
+		if attendee.Resource || attendee.Email == "" {
+			continue
+		}
+		domainEvent.Attendees = append(domainEvent.Attendees, attendee.Email)
 	}
 
 	// タイトルが空の場合は「（無題）」に設定
@@ -146,11 +279,11 @@ func (r *GoogleCalendarRepository) convertToEvent(event *calendar.Event) (domain
 		domainEvent.IsAllDay = false
 	} else if event.Start.Date != "" {
 		// 終日イベント
-		startTime, err := time.Parse("2006-01-02", event.Start.Date)
+		startTime, err := dates.ParseDate(event.Start.Date, r.timezone)
 		if err != nil {
 			return domain.Event{}, fmt.Errorf("開始日の解析に失敗しました: %v", err)
 		}
-		domainEvent.StartTime = startTime.In(r.timezone)
+		domainEvent.StartTime = startTime
 		domainEvent.IsAllDay = true
 	} else {
 		return domain.Event{}, fmt.Errorf("開始時刻が設定されていません")
@@ -166,14 +299,56 @@ func (r *GoogleCalendarRepository) convertToEvent(event *calendar.Event) (domain
 		domainEvent.EndTime = endTime.In(r.timezone)
 	} else if event.End.Date != "" {
 		// 終日イベント
-		endTime, err := time.Parse("2006-01-02", event.End.Date)
+		endTime, err := dates.ParseDate(event.End.Date, r.timezone)
 		if err != nil {
 			return domain.Event{}, fmt.Errorf("終了日の解析に失敗しました: %v", err)
 		}
-		domainEvent.EndTime = endTime.In(r.timezone)
+		domainEvent.EndTime = endTime
 	} else {
 		return domain.Event{}, fmt.Errorf("終了時刻が設定されていません")
 	}
 
 	return domainEvent, nil
 }
+
+// conferenceURL イベントに設定されたビデオ会議の参加URLを返す（なければ空文字）
+func conferenceURL(event *calendar.Event) string {
+	if event.ConferenceData != nil {
+		for _, entryPoint := range event.ConferenceData.EntryPoints {
+			if entryPoint.EntryPointType == "video" && entryPoint.Uri != "" {
+				return entryPoint.Uri
+			}
+		}
+	}
+	return event.HangoutLink
+}
+
+// reminders イベント個別に設定されたポップアップ通知のリマインダーを返す
+// カレンダーのデフォルトのリマインダーを使う予定は空を返す
+func reminders(event *calendar.Event) []time.Duration {
+	if event.Reminders == nil || event.Reminders.UseDefault {
+		return nil
+	}
+	var result []time.Duration
+	for _, override := range event.Reminders.Overrides {
+		if override.Method == "popup" {
+			result = append(result, time.Duration(override.Minutes)*time.Minute)
+		}
+	}
+	return result
+}
+
+// workingLocation 勤務場所の予定であればその種別を返す（勤務場所の予定でなければ空）
+func workingLocation(event *calendar.Event) domain.WorkingLocation {
+	if event.EventType != "workingLocation" || event.WorkingLocationProperties == nil {
+		return ""
+	}
+	switch event.WorkingLocationProperties.Type {
+	case "officeLocation":
+		return domain.WorkingLocationOffice
+	case "homeOffice":
+		return domain.WorkingLocationHome
+	default:
+		return domain.WorkingLocationCustom
+	}
+}
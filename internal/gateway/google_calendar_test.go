@@ -3,6 +3,9 @@ package gateway
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"os"
 	"testing"
 	"time"
 
@@ -10,8 +13,11 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
 
 	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/fakes"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/testutil"
 )
 
 // MockEventsProvider は EventsProvider のテスト用モック
@@ -31,7 +37,7 @@ func (m *MockEventsProvider) ListEvents(calendarID, timeMin, timeMax string) ([]
 
 func TestConvertToEvent_TimedEvent(t *testing.T) {
 	jst, _ := time.LoadLocation("Asia/Tokyo")
-	repo := NewGoogleCalendarRepositoryWithProvider(nil, "test", jst)
+	repo := NewGoogleCalendarRepositoryWithProvider(nil, []string{"test"}, jst)
 
 	event := &calendar.Event{
 		Id:       "1",
@@ -41,7 +47,7 @@ func TestConvertToEvent_TimedEvent(t *testing.T) {
 		End:      &calendar.EventDateTime{DateTime: "2024-01-15T11:00:00+09:00"},
 	}
 
-	result, err := repo.convertToEvent(event)
+	result, err := repo.convertToEvent("test", event)
 	require.NoError(t, err)
 	assert.Equal(t, "1", result.ID)
 	assert.Equal(t, "テストイベント", result.Title)
@@ -51,9 +57,98 @@ func TestConvertToEvent_TimedEvent(t *testing.T) {
 	assert.Equal(t, 11, result.EndTime.Hour())
 }
 
+func TestConvertToEvent_AttendeesAndOrganizer(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	repo := NewGoogleCalendarRepositoryWithProvider(nil, []string{"test"}, jst)
+
+	event := &calendar.Event{
+		Id:        "1",
+		Summary:   "定例",
+		Start:     &calendar.EventDateTime{DateTime: "2024-01-15T10:00:00+09:00"},
+		End:       &calendar.EventDateTime{DateTime: "2024-01-15T11:00:00+09:00"},
+		Organizer: &calendar.EventOrganizer{Email: "boss@example.com"},
+		Attendees: []*calendar.EventAttendee{
+			{Email: "a@example.com"},
+			{Email: "b@example.com"},
+			{Email: "room@resource.calendar.google.com", Resource: true},
+		},
+	}
+
+	result, err := repo.convertToEvent("test", event)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.AttendeeCount)
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, result.Attendees)
+	assert.Equal(t, "boss@example.com", result.Organizer)
+}
+
+func TestWorkingLocation(t *testing.T) {
+	workingLocationEvent := func(locationType string) *calendar.Event {
+		return &calendar.Event{
+			EventType:                 "workingLocation",
+			WorkingLocationProperties: &calendar.EventWorkingLocationProperties{Type: locationType},
+		}
+	}
+
+	assert.Equal(t, domain.WorkingLocationOffice, workingLocation(workingLocationEvent("officeLocation")))
+	assert.Equal(t, domain.WorkingLocationHome, workingLocation(workingLocationEvent("homeOffice")))
+	assert.Equal(t, domain.WorkingLocationCustom, workingLocation(workingLocationEvent("customLocation")))
+	assert.Empty(t, workingLocation(&calendar.Event{EventType: "default"}))
+}
+
+func TestReminders(t *testing.T) {
+	assert.Equal(t, []time.Duration{10 * time.Minute, time.Hour}, reminders(&calendar.Event{
+		Reminders: &calendar.EventReminders{Overrides: []*calendar.EventReminder{
+			{Method: "popup", Minutes: 10},
+			{Method: "email", Minutes: 30},
+			{Method: "popup", Minutes: 60},
+		}},
+	}))
+	assert.Empty(t, reminders(&calendar.Event{Reminders: &calendar.EventReminders{UseDefault: true}}))
+	assert.Empty(t, reminders(&calendar.Event{}))
+}
+
+func TestConferenceURL(t *testing.T) {
+	assert.Equal(t, "https://meet.google.com/abc-defg-hij", conferenceURL(&calendar.Event{
+		ConferenceData: &calendar.ConferenceData{EntryPoints: []*calendar.EntryPoint{
+			{EntryPointType: "phone", Uri: "tel:+81-3-0000-0000"},
+			{EntryPointType: "video", Uri: "https://meet.google.com/abc-defg-hij"},
+		}},
+	}))
+	assert.Equal(t, "https://meet.google.com/xyz-abcd-efg", conferenceURL(&calendar.Event{HangoutLink: "https://meet.google.com/xyz-abcd-efg"}))
+	assert.Empty(t, conferenceURL(&calendar.Event{}))
+}
+
+func TestConvertToEvent_IsOnline(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	repo := NewGoogleCalendarRepositoryWithProvider(nil, []string{"test"}, jst)
+	timed := func(event *calendar.Event) *calendar.Event {
+		event.Start = &calendar.EventDateTime{DateTime: "2024-01-15T08:45:00+09:00"}
+		event.End = &calendar.EventDateTime{DateTime: "2024-01-15T09:00:00+09:00"}
+		return event
+	}
+
+	tests := []struct {
+		name  string
+		event *calendar.Event
+		want  bool
+	}{
+		{name: "Google Meet", event: timed(&calendar.Event{HangoutLink: "https://meet.google.com/abc-defg-hij"}), want: true},
+		{name: "場所に会議URL", event: timed(&calendar.Event{Location: "https://zoom.us/j/123"}), want: true},
+		{name: "説明文に会議URL", event: timed(&calendar.Event{Description: "参加: https://teams.microsoft.com/l/meetup-join/abc"}), want: true},
+		{name: "会議室", event: timed(&calendar.Event{Location: "本社 5F"}), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.convertToEvent("test", tt.event)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.IsOnline)
+		})
+	}
+}
+
 func TestConvertToEvent_AllDayEvent(t *testing.T) {
 	jst, _ := time.LoadLocation("Asia/Tokyo")
-	repo := NewGoogleCalendarRepositoryWithProvider(nil, "test", jst)
+	repo := NewGoogleCalendarRepositoryWithProvider(nil, []string{"test"}, jst)
 
 	event := &calendar.Event{
 		Id:      "2",
@@ -62,15 +157,53 @@ func TestConvertToEvent_AllDayEvent(t *testing.T) {
 		End:     &calendar.EventDateTime{Date: "2024-01-16"},
 	}
 
-	result, err := repo.convertToEvent(event)
+	result, err := repo.convertToEvent("test", event)
 	require.NoError(t, err)
 	assert.True(t, result.IsAllDay)
 	assert.Equal(t, "終日イベント", result.Title)
 }
 
+func TestConvertToEvent_AllDayEventWestOfUTC(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	repo := NewGoogleCalendarRepositoryWithProvider(nil, []string{"test"}, ny)
+
+	// 夏時間の開始日（23時間の日）の終日予定
+	event := &calendar.Event{
+		Id:      "2",
+		Summary: "終日イベント",
+		Start:   &calendar.EventDateTime{Date: "2024-03-10"},
+		End:     &calendar.EventDateTime{Date: "2024-03-11"},
+	}
+
+	result, err := repo.convertToEvent("test", event)
+	require.NoError(t, err)
+	// UTCの0時として解析すると前日の19時になってしまう
+	assert.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, ny), result.StartTime)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, ny), result.EndTime)
+	assert.Equal(t, 23*time.Hour, result.EndTime.Sub(result.StartTime))
+}
+
+func TestConvertToEvent_OutOfOffice(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	repo := NewGoogleCalendarRepositoryWithProvider(nil, []string{"test"}, jst)
+
+	event := &calendar.Event{
+		Id:        "ooo",
+		Summary:   "不在",
+		EventType: "outOfOffice",
+		Start:     &calendar.EventDateTime{DateTime: "2024-01-15T00:00:00+09:00"},
+		End:       &calendar.EventDateTime{DateTime: "2024-01-16T00:00:00+09:00"},
+	}
+
+	result, err := repo.convertToEvent("test", event)
+	require.NoError(t, err)
+	assert.True(t, result.IsOutOfOffice)
+}
+
 func TestConvertToEvent_EmptyTitle(t *testing.T) {
 	jst, _ := time.LoadLocation("Asia/Tokyo")
-	repo := NewGoogleCalendarRepositoryWithProvider(nil, "test", jst)
+	repo := NewGoogleCalendarRepositoryWithProvider(nil, []string{"test"}, jst)
 
 	event := &calendar.Event{
 		Id:      "3",
@@ -79,14 +212,14 @@ func TestConvertToEvent_EmptyTitle(t *testing.T) {
 		End:     &calendar.EventDateTime{DateTime: "2024-01-15T11:00:00+09:00"},
 	}
 
-	result, err := repo.convertToEvent(event)
+	result, err := repo.convertToEvent("test", event)
 	require.NoError(t, err)
 	assert.Equal(t, "（無題）", result.Title)
 }
 
 func TestConvertToEvent_NoStartTime(t *testing.T) {
 	jst, _ := time.LoadLocation("Asia/Tokyo")
-	repo := NewGoogleCalendarRepositoryWithProvider(nil, "test", jst)
+	repo := NewGoogleCalendarRepositoryWithProvider(nil, []string{"test"}, jst)
 
 	event := &calendar.Event{
 		Id:    "4",
@@ -94,7 +227,7 @@ func TestConvertToEvent_NoStartTime(t *testing.T) {
 		End:   &calendar.EventDateTime{DateTime: "2024-01-15T11:00:00+09:00"},
 	}
 
-	_, err := repo.convertToEvent(event)
+	_, err := repo.convertToEvent("test", event)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "開始時刻が設定されていません")
 }
@@ -104,7 +237,7 @@ func TestConvertToEvent_NoStartTime(t *testing.T) {
 func TestGetEvents_Success(t *testing.T) {
 	jst, _ := time.LoadLocation("Asia/Tokyo")
 	mockProvider := new(MockEventsProvider)
-	repo := NewGoogleCalendarRepositoryWithProvider(mockProvider, "test-calendar", jst)
+	repo := NewGoogleCalendarRepositoryWithProvider(mockProvider, []string{"test-calendar"}, jst)
 
 	targetDate := time.Date(2024, 1, 15, 0, 0, 0, 0, jst)
 
@@ -128,10 +261,37 @@ func TestGetEvents_Success(t *testing.T) {
 	mockProvider.AssertExpectations(t)
 }
 
+func TestGetEvents_DSTDayBounds(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		date    time.Time
+		timeMin string
+		timeMax string
+	}{
+		{name: "夏時間開始日（23時間）", date: time.Date(2024, 3, 10, 12, 0, 0, 0, ny), timeMin: "2024-03-10T00:00:00-05:00", timeMax: "2024-03-11T00:00:00-04:00"},
+		{name: "夏時間終了日（25時間）", date: time.Date(2024, 11, 3, 12, 0, 0, 0, ny), timeMin: "2024-11-03T00:00:00-04:00", timeMax: "2024-11-04T00:00:00-05:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := new(MockEventsProvider)
+			repo := NewGoogleCalendarRepositoryWithProvider(mockProvider, []string{"test-calendar"}, ny)
+			mockProvider.On("ListEvents", "test-calendar", tt.timeMin, tt.timeMax).Return([]*calendar.Event{}, nil)
+
+			_, err := repo.GetEvents(context.Background(), tt.date)
+			require.NoError(t, err)
+			mockProvider.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGetEvents_APIError(t *testing.T) {
 	jst, _ := time.LoadLocation("Asia/Tokyo")
 	mockProvider := new(MockEventsProvider)
-	repo := NewGoogleCalendarRepositoryWithProvider(mockProvider, "test-calendar", jst)
+	repo := NewGoogleCalendarRepositoryWithProvider(mockProvider, []string{"test-calendar"}, jst)
 
 	targetDate := time.Date(2024, 1, 15, 0, 0, 0, 0, jst)
 
@@ -147,7 +307,7 @@ func TestGetEvents_APIError(t *testing.T) {
 func TestGetEvents_EmptyResult(t *testing.T) {
 	jst, _ := time.LoadLocation("Asia/Tokyo")
 	mockProvider := new(MockEventsProvider)
-	repo := NewGoogleCalendarRepositoryWithProvider(mockProvider, "test-calendar", jst)
+	repo := NewGoogleCalendarRepositoryWithProvider(mockProvider, []string{"test-calendar"}, jst)
 
 	targetDate := time.Date(2024, 1, 15, 0, 0, 0, 0, jst)
 
@@ -159,3 +319,213 @@ func TestGetEvents_EmptyResult(t *testing.T) {
 	assert.Empty(t, result)
 	mockProvider.AssertExpectations(t)
 }
+
+func TestGetEvents_MultipleCalendars(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	mockProvider := new(MockEventsProvider)
+	repo := NewGoogleCalendarRepositoryWithProvider(mockProvider, []string{"work", "family"}, jst)
+
+	mockProvider.On("ListEvents", "work", mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+		Return([]*calendar.Event{
+			{Id: "standup", Summary: "朝会", Start: &calendar.EventDateTime{DateTime: "2024-01-15T09:00:00+09:00"}, End: &calendar.EventDateTime{DateTime: "2024-01-15T09:30:00+09:00"}},
+			{Id: "dinner", Summary: "会食", Start: &calendar.EventDateTime{DateTime: "2024-01-15T19:00:00+09:00"}, End: &calendar.EventDateTime{DateTime: "2024-01-15T21:00:00+09:00"}},
+		}, nil)
+	mockProvider.On("ListEvents", "family", mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+		Return([]*calendar.Event{
+			{Id: "school", Summary: "保育園送り", Start: &calendar.EventDateTime{DateTime: "2024-01-15T08:00:00+09:00"}, End: &calendar.EventDateTime{DateTime: "2024-01-15T08:30:00+09:00"}},
+			// 両方のカレンダーに招待されている予定
+			{Id: "dinner", Summary: "会食", Start: &calendar.EventDateTime{DateTime: "2024-01-15T19:00:00+09:00"}, End: &calendar.EventDateTime{DateTime: "2024-01-15T21:00:00+09:00"}},
+		}, nil)
+
+	result, err := repo.GetEvents(context.Background(), time.Date(2024, 1, 15, 0, 0, 0, 0, jst))
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+	assert.Equal(t, []string{"school", "standup", "dinner"}, []string{result[0].ID, result[1].ID, result[2].ID})
+	assert.Equal(t, []string{"family", "work", "work"}, []string{result[0].CalendarID, result[1].CalendarID, result[2].CalendarID})
+	mockProvider.AssertExpectations(t)
+}
+
+func TestGetEvents_MultipleCalendarsError(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	mockProvider := new(MockEventsProvider)
+	repo := NewGoogleCalendarRepositoryWithProvider(mockProvider, []string{"work", "family"}, jst)
+
+	mockProvider.On("ListEvents", "work", mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+		Return([]*calendar.Event{}, nil)
+	mockProvider.On("ListEvents", "family", mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+		Return(nil, errors.New("API error"))
+
+	_, err := repo.GetEvents(context.Background(), time.Date(2024, 1, 15, 0, 0, 0, 0, jst))
+	assert.ErrorContains(t, err, "カレンダー family のイベントの取得に失敗しました")
+}
+
+// --- 記録済みフィクスチャを使った GetEvents テスト ---
+
+// newReplayProvider フィクスチャを再生するEventsProviderを作成するヘルパー
+// RECORD_FIXTURES が設定されている場合は GOOGLE_CREDENTIALS で実APIにアクセスして記録する
+func newReplayProvider(t *testing.T, fixture string) EventsProvider {
+	t.Helper()
+
+	rec, err := testutil.NewRecorder(fixture, "/calendar/v3/", http.DefaultTransport)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, rec.Save())
+	})
+
+	if rec.Recording() {
+		provider, err := newGoogleEventsProvider([]byte(os.Getenv("GOOGLE_CREDENTIALS")), "", rec, calendar.CalendarReadonlyScope)
+		require.NoError(t, err)
+		return provider
+	}
+
+	service, err := calendar.NewService(
+		context.Background(),
+		option.WithHTTPClient(&http.Client{Transport: rec}),
+		option.WithoutAuthentication(),
+	)
+	require.NoError(t, err)
+	return &googleEventsProvider{service: service, cache: newEventsCache()}
+}
+
+func TestGetEvents_RecordedFixture(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	provider := newReplayProvider(t, "testdata/calendar/events_2024-01-15.json")
+	repo := NewGoogleCalendarRepositoryWithProvider(provider, []string{"primary"}, jst)
+
+	result, err := repo.GetEvents(context.Background(), time.Date(2024, 1, 15, 0, 0, 0, 0, jst))
+	require.NoError(t, err)
+	if os.Getenv(testutil.RecordEnv) != "" {
+		t.Skip("記録モードのため検証をスキップします")
+	}
+
+	// キャンセル済みのイベントは除外される
+	require.Len(t, result, 4)
+
+	// 複数日にまたがる終日イベント
+	assert.Equal(t, "大阪出張", result[0].Title)
+	assert.True(t, result[0].IsAllDay)
+	assert.Equal(t, 14, result[0].StartTime.Day())
+	assert.Equal(t, 17, result[0].EndTime.Day())
+
+	// 繰り返しイベントのインスタンス
+	assert.Equal(t, "週次定例", result[1].Title)
+	assert.Equal(t, "weekly_20240115T000000Z", result[1].ID)
+	assert.Equal(t, 9, result[1].StartTime.Hour())
+
+	assert.Equal(t, "顧客打ち合わせ", result[2].Title)
+	assert.Equal(t, "オンライン", result[2].Location)
+
+	// 日付をまたぐ無題のイベント
+	assert.Equal(t, "（無題）", result[3].Title)
+	assert.Equal(t, 16, result[3].EndTime.Day())
+}
+
+// --- ETagキャッシュテスト（偽サーバー使用） ---
+
+func TestListEvents_UsesETagCache(t *testing.T) {
+	server := fakes.NewCalendarServer(t)
+	server.AddEvents("primary", &calendar.Event{
+		Id:      "1",
+		Summary: "朝会",
+		Start:   &calendar.EventDateTime{DateTime: "2024-01-15T09:00:00+09:00"},
+		End:     &calendar.EventDateTime{DateTime: "2024-01-15T09:30:00+09:00"},
+	})
+
+	provider, err := newGoogleEventsProvider(server.ServiceAccountJSON(t), server.Endpoint(), http.DefaultTransport, calendar.CalendarReadonlyScope)
+	require.NoError(t, err)
+	provider.cache = newEventsCache()
+
+	first, err := provider.ListEvents("primary", "2024-01-15T00:00:00+09:00", "2024-01-16T00:00:00+09:00")
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+
+	// 2回目は304となりキャッシュから返される
+	second, err := provider.ListEvents("primary", "2024-01-15T00:00:00+09:00", "2024-01-16T00:00:00+09:00")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 2, server.ListCalls())
+	assert.Equal(t, 1, server.NotModifiedCount())
+
+	// 内容が変わればETagも変わり最新のイベントを取得する
+	server.AddEvents("primary", &calendar.Event{
+		Id:      "2",
+		Summary: "追加された予定",
+		Start:   &calendar.EventDateTime{DateTime: "2024-01-15T13:00:00+09:00"},
+		End:     &calendar.EventDateTime{DateTime: "2024-01-15T14:00:00+09:00"},
+	})
+	third, err := provider.ListEvents("primary", "2024-01-15T00:00:00+09:00", "2024-01-16T00:00:00+09:00")
+	require.NoError(t, err)
+	assert.Len(t, third, 2)
+	assert.Equal(t, 1, server.NotModifiedCount())
+}
+
+func TestListEvents_FollowsNextPageToken(t *testing.T) {
+	server := fakes.NewCalendarServer(t)
+	server.SetPageSize(2)
+	for i := 0; i < 5; i++ {
+		server.AddEvents("primary", &calendar.Event{
+			Id:      fmt.Sprintf("event-%d", i),
+			Summary: fmt.Sprintf("予定%d", i),
+			Start:   &calendar.EventDateTime{DateTime: fmt.Sprintf("2024-01-15T%02d:00:00+09:00", 9+i)},
+			End:     &calendar.EventDateTime{DateTime: fmt.Sprintf("2024-01-15T%02d:30:00+09:00", 9+i)},
+		})
+	}
+
+	provider, err := newGoogleEventsProvider(server.ServiceAccountJSON(t), server.Endpoint(), http.DefaultTransport, calendar.CalendarReadonlyScope)
+	require.NoError(t, err)
+	provider.cache = newEventsCache()
+
+	events, err := provider.ListEvents("primary", "2024-01-15T00:00:00+09:00", "2024-01-16T00:00:00+09:00")
+	require.NoError(t, err)
+	require.Len(t, events, 5)
+	assert.Equal(t, "event-0", events[0].Id)
+	assert.Equal(t, "event-4", events[4].Id)
+	assert.Equal(t, 3, server.ListCalls())
+
+	// 変更がなければ1ページ目の304で全ページ分をキャッシュから返す
+	cached, err := provider.ListEvents("primary", "2024-01-15T00:00:00+09:00", "2024-01-16T00:00:00+09:00")
+	require.NoError(t, err)
+	assert.Equal(t, events, cached)
+	assert.Equal(t, 4, server.ListCalls())
+	assert.Equal(t, 1, server.NotModifiedCount())
+}
+
+// --- ベンチマーク ---
+
+// benchmarkEventCount 大量の予定がある日を想定したベンチマーク用の件数
+const benchmarkEventCount = 250
+
+func BenchmarkConvertToEvent(b *testing.B) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	repo := NewGoogleCalendarRepositoryWithProvider(nil, []string{"test"}, jst)
+
+	events := make([]*calendar.Event, benchmarkEventCount)
+	for i := range events {
+		if i%10 == 0 {
+			events[i] = &calendar.Event{
+				Id:      fmt.Sprintf("allday-%d", i),
+				Summary: fmt.Sprintf("終日イベント%d", i),
+				Start:   &calendar.EventDateTime{Date: "2024-01-15"},
+				End:     &calendar.EventDateTime{Date: "2024-01-16"},
+			}
+			continue
+		}
+		events[i] = &calendar.Event{
+			Id:       fmt.Sprintf("event-%d", i),
+			Summary:  fmt.Sprintf("ミーティング%d", i),
+			Location: "会議室A",
+			Start:    &calendar.EventDateTime{DateTime: "2024-01-15T10:00:00+09:00"},
+			End:      &calendar.EventDateTime{DateTime: "2024-01-15T11:00:00+09:00"},
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, event := range events {
+			if _, err := repo.convertToEvent("test", event); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
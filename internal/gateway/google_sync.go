@@ -0,0 +1,309 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
+)
+
+// EventsSyncer syncTokenを使ったカレンダーイベントの差分取得を抽象化する
+type EventsSyncer interface {
+	// SyncEvents syncTokenが空の場合は期間[timeMin, timeMax)の全イベント、指定した場合はトークンの発行以降に
+	// 変更されたイベントをキャンセル済みのものも含めて取得し、次回の差分取得に使うsyncTokenとともに返す
+	SyncEvents(calendarID, syncToken, timeMin, timeMax string) ([]*calendar.Event, string, error)
+}
+
+// SyncEvents Events.ListをsyncToken付きで呼び出し、全ページのイベントを取得する
+func (p *googleEventsProvider) SyncEvents(calendarID, syncToken, timeMin, timeMax string) ([]*calendar.Event, string, error) {
+	// syncTokenは並び順・期間の指定と併用できないため、OrderByは指定しない
+	call := p.service.Events.List(calendarID).
+		SingleEvents(true).
+		ShowDeleted(true).
+		MaxResults(eventsPageSize)
+	if syncToken != "" {
+		call = call.SyncToken(syncToken)
+	} else {
+		call = call.TimeMin(timeMin).TimeMax(timeMax)
+	}
+
+	var items []*calendar.Event
+	for pageToken := ""; ; {
+		page, err := call.PageToken(pageToken).Do()
+		if err != nil {
+			return nil, "", err
+		}
+		items = append(items, page.Items...)
+		if page.NextPageToken == "" {
+			return items, page.NextSyncToken, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// syncStateSchema 差分取得の状態の保存形式
+var syncStateSchema = store.Schema{Name: "差分取得の状態", Version: 1}
+
+const (
+	// syncStateTTL 差分取得の状態を保持する期間（期限切れ後は全件を取得し直す）
+	syncStateTTL = 30 * 24 * time.Hour
+	// syncWindow 全件を取得する期間の長さ
+	// 繰り返しの予定を展開しても保存する状態がストアの上限（DynamoDBは1件400KB）に収まるよう、取得する期間を限る
+	syncWindow = 14 * 24 * time.Hour
+	// syncLookback 取得する期間より前に遡って保持する期間
+	// 今日と明日を並行して取得する場合に、明日を先に取得しても今日の取得で全件の取得にならないようにする
+	syncLookback = 24 * time.Hour
+)
+
+// syncState カレンダーごとに保存する差分取得の状態
+type syncState struct {
+	SyncToken string `json:"syncToken"`
+	// From, Until 期間[From, Until)と重なる予定を保持している
+	From  time.Time `json:"from"`
+	Until time.Time `json:"until"`
+	// Events 保持している予定（キーはイベントID、通知に使う項目のみ）
+	Events map[string]*calendar.Event `json:"events"`
+}
+
+// SyncingEventsProvider syncTokenをストアに保存し、前回からの変更だけを取得するEventsProvider
+// 取得した予定をストアに保持しておき、期間内の予定はそこから返す
+// 保持している期間の外を取得する場合や、syncTokenが期限切れ(410 Gone)の場合は全件を取得し直す
+type SyncingEventsProvider struct {
+	syncer EventsSyncer
+	store  store.Store
+
+	// 今日と明日の予定を並行して取得しても状態の読み込みと保存が競合しないよう、カレンダーごとに排他する
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// NewSyncingEventsProvider 差分取得を行うEventsProviderを作成
+func NewSyncingEventsProvider(syncer EventsSyncer, s store.Store) *SyncingEventsProvider {
+	return &SyncingEventsProvider{syncer: syncer, store: s, locks: make(map[string]*sync.Mutex)}
+}
+
+// lock カレンダーの差分取得の状態を排他する
+func (p *SyncingEventsProvider) lock(calendarID string) func() {
+	p.mu.Lock()
+	l, ok := p.locks[calendarID]
+	if !ok {
+		l = &sync.Mutex{}
+		p.locks[calendarID] = l
+	}
+	p.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+// ListEvents 前回からの変更を取得して保持している予定に反映し、期間と重なる予定を開始時刻順に返す
+func (p *SyncingEventsProvider) ListEvents(calendarID, timeMin, timeMax string) ([]*calendar.Event, error) {
+	from, err := time.Parse(time.RFC3339, timeMin)
+	if err != nil {
+		return nil, fmt.Errorf("timeMinの形式が不正です: %v", err)
+	}
+	to, err := time.Parse(time.RFC3339, timeMax)
+	if err != nil {
+		return nil, fmt.Errorf("timeMaxの形式が不正です: %v", err)
+	}
+
+	defer p.lock(calendarID)()
+
+	ctx := context.Background()
+	key := store.Key(store.NamespaceSyncToken, calendarID)
+	state, err := p.load(ctx, key)
+	if err != nil {
+		fmt.Printf("Warning: 差分取得の状態を読み込めないため全件を取得します: %v\n", err)
+	}
+
+	if state != nil && !from.Before(state.From) && !to.After(state.Until) {
+		changes, nextToken, err := p.syncer.SyncEvents(calendarID, state.SyncToken, "", "")
+		switch {
+		case err == nil:
+			state.apply(changes)
+			state.SyncToken = nextToken
+		case isSyncTokenExpired(err):
+			fmt.Printf("Warning: カレンダー %s のsyncTokenが無効になったため全件を取得します\n", calendarID)
+			state = nil
+		default:
+			return nil, err
+		}
+	} else {
+		state = nil
+	}
+
+	if state == nil {
+		since, until := from.Add(-syncLookback), from.Add(syncWindow)
+		if to.After(until) {
+			until = to
+		}
+		events, nextToken, err := p.syncer.SyncEvents(calendarID, "", since.Format(time.RFC3339), until.Format(time.RFC3339))
+		if err != nil {
+			return nil, err
+		}
+		state = &syncState{SyncToken: nextToken, From: since, Until: until, Events: make(map[string]*calendar.Event)}
+		state.apply(events)
+	}
+
+	state.prune(from.Add(-syncLookback))
+	if err := p.save(ctx, key, state); err != nil {
+		fmt.Printf("Warning: 差分取得の状態を保存できませんでした（次回は全件を取得します）: %v\n", err)
+	}
+	return state.between(from, to), nil
+}
+
+// load 保存されている差分取得の状態を読み込む（存在しない場合はnil）
+func (p *SyncingEventsProvider) load(ctx context.Context, key string) (*syncState, error) {
+	raw, err := p.store.Get(ctx, key)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state syncState
+	if _, err := syncStateSchema.Decode(raw, &state); err != nil {
+		return nil, err
+	}
+	if state.SyncToken == "" {
+		return nil, nil
+	}
+	if state.Events == nil {
+		state.Events = make(map[string]*calendar.Event)
+	}
+	return &state, nil
+}
+
+// save 差分取得の状態を保存する
+// syncTokenが返されなかった場合は次回に差分を取得できないため、保存済みの状態を削除する
+func (p *SyncingEventsProvider) save(ctx context.Context, key string, state *syncState) error {
+	if state.SyncToken == "" {
+		return p.store.Delete(ctx, key)
+	}
+	data, err := syncStateSchema.Encode(state)
+	if err != nil {
+		return err
+	}
+	return p.store.Put(ctx, key, data, syncStateTTL)
+}
+
+// apply 取得した変更を反映する
+// キャンセル済みの予定と、変更で保持している期間の外に移った予定は削除する
+func (s *syncState) apply(changes []*calendar.Event) {
+	for _, event := range changes {
+		if event.Id == "" {
+			continue
+		}
+		start, end := eventRange(event)
+		if event.Status == "cancelled" || !start.Before(s.Until) || !end.After(s.From) {
+			delete(s.Events, event.Id)
+			continue
+		}
+		s.Events[event.Id] = trimEvent(event)
+	}
+}
+
+// trimEvent 予定のうち通知に使う項目（convertToEventが参照するもの）だけを残す
+func trimEvent(event *calendar.Event) *calendar.Event {
+	trimmed := &calendar.Event{
+		Id:                        event.Id,
+		Status:                    event.Status,
+		Summary:                   event.Summary,
+		Location:                  event.Location,
+		Description:               event.Description,
+		EventType:                 event.EventType,
+		HangoutLink:               event.HangoutLink,
+		Start:                     event.Start,
+		End:                       event.End,
+		Reminders:                 event.Reminders,
+		WorkingLocationProperties: event.WorkingLocationProperties,
+	}
+	if event.Organizer != nil {
+		trimmed.Organizer = &calendar.EventOrganizer{Email: event.Organizer.Email}
+	}
+	for _, attendee := range event.Attendees {
+		trimmed.Attendees = append(trimmed.Attendees, &calendar.EventAttendee{Email: attendee.Email, Resource: attendee.Resource})
+	}
+	if event.ConferenceData != nil {
+		trimmed.ConferenceData = &calendar.ConferenceData{EntryPoints: event.ConferenceData.EntryPoints}
+	}
+	if event.ExtendedProperties != nil {
+		for _, key := range []string{notifiedPropertyKey, notifiedByPropertyKey} {
+			value, ok := event.ExtendedProperties.Private[key]
+			if !ok {
+				continue
+			}
+			if trimmed.ExtendedProperties == nil {
+				trimmed.ExtendedProperties = &calendar.EventExtendedProperties{Private: make(map[string]string)}
+			}
+			trimmed.ExtendedProperties.Private[key] = value
+		}
+	}
+	return trimmed
+}
+
+// prune cutoffより前に終了した予定を削除し、保持している期間の開始をcutoffまで進める
+func (s *syncState) prune(cutoff time.Time) {
+	if !cutoff.After(s.From) {
+		return
+	}
+	for id, event := range s.Events {
+		if _, end := eventRange(event); !end.After(cutoff) {
+			delete(s.Events, id)
+		}
+	}
+	s.From = cutoff
+}
+
+// between 期間[from, to)と重なる予定を開始時刻順に返す
+func (s *syncState) between(from, to time.Time) []*calendar.Event {
+	events := make([]*calendar.Event, 0)
+	for _, event := range s.Events {
+		start, end := eventRange(event)
+		if start.Before(to) && end.After(from) {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		si, _ := eventRange(events[i])
+		sj, _ := eventRange(events[j])
+		if si.Equal(sj) {
+			return events[i].Id < events[j].Id
+		}
+		return si.Before(sj)
+	})
+	return events
+}
+
+// eventRange 予定の開始・終了時刻を返す（終日の予定は通知のタイムゾーンの0時として扱う）
+func eventRange(event *calendar.Event) (start, end time.Time) {
+	return parseEventTime(event.Start), parseEventTime(event.End)
+}
+
+// parseEventTime EventDateTimeを時刻に変換する（解析できない場合はゼロ値）
+func parseEventTime(dt *calendar.EventDateTime) time.Time {
+	if dt == nil {
+		return time.Time{}
+	}
+	if dt.DateTime != "" {
+		t, _ := time.Parse(time.RFC3339, dt.DateTime)
+		return t
+	}
+	t, _ := time.ParseInLocation("2006-01-02", dt.Date, dates.Location())
+	return t
+}
+
+// isSyncTokenExpired syncTokenが無効になり、全件の取得が必要なエラーか判定
+func isSyncTokenExpired(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusGone
+}
@@ -0,0 +1,226 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/fakes"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
+)
+
+// newSyncingProvider 偽サーバーに接続する差分取得のEventsProviderを作成
+func newSyncingProvider(t *testing.T, server *fakes.CalendarServer) (*SyncingEventsProvider, store.Store) {
+	t.Helper()
+	provider, err := newGoogleEventsProvider(server.ServiceAccountJSON(t), server.Endpoint(), http.DefaultTransport, calendar.CalendarReadonlyScope)
+	require.NoError(t, err)
+	s := store.NewMemoryStore(clock.Fixed(time.Date(2024, 1, 15, 7, 0, 0, 0, time.UTC)))
+	return NewSyncingEventsProvider(provider, s), s
+}
+
+func timedEvent(id, summary, start, end string) *calendar.Event {
+	return &calendar.Event{
+		Id:      id,
+		Summary: summary,
+		Start:   &calendar.EventDateTime{DateTime: start},
+		End:     &calendar.EventDateTime{DateTime: end},
+	}
+}
+
+func eventIDs(events []*calendar.Event) []string {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.Id)
+	}
+	return ids
+}
+
+func TestSyncingEventsProvider_AppliesChanges(t *testing.T) {
+	server := fakes.NewCalendarServer(t)
+	server.SetPageSize(2)
+	server.AddEvents("primary",
+		timedEvent("standup", "朝会", "2024-01-15T09:00:00+09:00", "2024-01-15T09:30:00+09:00"),
+		timedEvent("lunch", "ランチ", "2024-01-15T12:00:00+09:00", "2024-01-15T13:00:00+09:00"),
+		timedEvent("review", "レビュー", "2024-01-16T15:00:00+09:00", "2024-01-16T16:00:00+09:00"),
+	)
+	provider, s := newSyncingProvider(t, server)
+
+	// 初回は全件を取得し、期間内の予定を開始時刻順に返す
+	events, err := provider.ListEvents("primary", "2024-01-15T00:00:00+09:00", "2024-01-16T00:00:00+09:00")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"standup", "lunch"}, eventIDs(events))
+	_, err = s.Get(context.Background(), store.Key(store.NamespaceSyncToken, "primary"))
+	require.NoError(t, err)
+
+	// 2回目以降は変更のみを取得して反映する
+	server.UpdateEvent("primary", timedEvent("standup", "朝会（時間変更）", "2024-01-15T10:00:00+09:00", "2024-01-15T10:30:00+09:00"))
+	server.CancelEvent("primary", "lunch")
+	server.AddEvents("primary", timedEvent("1on1", "1on1", "2024-01-15T08:00:00+09:00", "2024-01-15T08:30:00+09:00"))
+	calls := server.ListCalls()
+
+	events, err = provider.ListEvents("primary", "2024-01-15T00:00:00+09:00", "2024-01-16T00:00:00+09:00")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1on1", "standup"}, eventIDs(events))
+	assert.Equal(t, "朝会（時間変更）", events[1].Summary)
+	assert.Equal(t, calls+2, server.ListCalls())
+
+	// 翌日も保持している予定から返す
+	events, err = provider.ListEvents("primary", "2024-01-16T00:00:00+09:00", "2024-01-17T00:00:00+09:00")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"review"}, eventIDs(events))
+}
+
+func TestSyncingEventsProvider_ExpiredSyncToken(t *testing.T) {
+	server := fakes.NewCalendarServer(t)
+	server.AddEvents("primary", timedEvent("standup", "朝会", "2024-01-15T09:00:00+09:00", "2024-01-15T09:30:00+09:00"))
+	provider, _ := newSyncingProvider(t, server)
+
+	_, err := provider.ListEvents("primary", "2024-01-15T00:00:00+09:00", "2024-01-16T00:00:00+09:00")
+	require.NoError(t, err)
+
+	// syncTokenが期限切れ(410 Gone)の場合は全件を取得し直す
+	server.AddEvents("primary", timedEvent("lunch", "ランチ", "2024-01-15T12:00:00+09:00", "2024-01-15T13:00:00+09:00"))
+	server.ExpireSyncTokens()
+	calls := server.ListCalls()
+
+	events, err := provider.ListEvents("primary", "2024-01-15T00:00:00+09:00", "2024-01-16T00:00:00+09:00")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"standup", "lunch"}, eventIDs(events))
+	assert.Equal(t, calls+2, server.ListCalls())
+}
+
+func TestSyncingEventsProvider_EarlierRangeRequiresFullSync(t *testing.T) {
+	server := fakes.NewCalendarServer(t)
+	server.AddEvents("primary",
+		timedEvent("earlier", "2日前の予定", "2024-01-13T09:00:00+09:00", "2024-01-13T10:00:00+09:00"),
+		timedEvent("today", "当日の予定", "2024-01-15T09:00:00+09:00", "2024-01-15T10:00:00+09:00"),
+	)
+	provider, _ := newSyncingProvider(t, server)
+
+	_, err := provider.ListEvents("primary", "2024-01-15T00:00:00+09:00", "2024-01-16T00:00:00+09:00")
+	require.NoError(t, err)
+
+	// 保持している期間（前日以降）より前の予定は全件の取得で取り直す
+	events, err := provider.ListEvents("primary", "2024-01-13T00:00:00+09:00", "2024-01-14T00:00:00+09:00")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"earlier"}, eventIDs(events))
+	assert.Equal(t, 0, server.SyncCalls())
+}
+
+func TestSyncingEventsProvider_ConcurrentDays(t *testing.T) {
+	server := fakes.NewCalendarServer(t)
+	server.AddEvents("primary",
+		timedEvent("today", "当日の予定", "2024-01-15T09:00:00+09:00", "2024-01-15T10:00:00+09:00"),
+		timedEvent("tomorrow", "翌日の予定", "2024-01-16T09:00:00+09:00", "2024-01-16T10:00:00+09:00"),
+	)
+	provider, _ := newSyncingProvider(t, server)
+
+	// 今日と明日を並行して取得しても、全件の取得は1回だけで、もう一方は差分取得になる
+	ranges := [][2]string{
+		{"2024-01-15T00:00:00+09:00", "2024-01-16T00:00:00+09:00"},
+		{"2024-01-16T00:00:00+09:00", "2024-01-17T00:00:00+09:00"},
+	}
+	// 明日の取得が先に状態を保存した場合も、今日は保持している期間に含まれる
+	results := make([][]*calendar.Event, len(ranges))
+	errs := make([]error, len(ranges))
+	var wg sync.WaitGroup
+	for i, r := range ranges {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = provider.ListEvents("primary", r[0], r[1])
+		}()
+	}
+	wg.Wait()
+
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	assert.Equal(t, []string{"today"}, eventIDs(results[0]))
+	assert.Equal(t, []string{"tomorrow"}, eventIDs(results[1]))
+	assert.Equal(t, 2, server.ListCalls())
+	assert.Equal(t, 1, server.SyncCalls())
+
+	// 保存したsyncTokenで次回も差分を取得できる
+	server.CancelEvent("primary", "today")
+	events, err := provider.ListEvents("primary", ranges[0][0], ranges[0][1])
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	assert.Equal(t, 2, server.SyncCalls())
+}
+
+func TestSyncingEventsProvider_BoundedWindow(t *testing.T) {
+	server := fakes.NewCalendarServer(t)
+	server.AddEvents("primary",
+		timedEvent("today", "当日の予定", "2024-01-15T09:00:00+09:00", "2024-01-15T10:00:00+09:00"),
+		timedEvent("later", "1か月後の予定", "2024-02-15T09:00:00+09:00", "2024-02-15T10:00:00+09:00"),
+	)
+	provider, _ := newSyncingProvider(t, server)
+
+	// 全件の取得は一定期間に限り、期間外の予定は保存しない
+	_, err := provider.ListEvents("primary", "2024-01-15T00:00:00+09:00", "2024-01-16T00:00:00+09:00")
+	require.NoError(t, err)
+	state, err := provider.load(context.Background(), store.Key(store.NamespaceSyncToken, "primary"))
+	require.NoError(t, err)
+	assert.Contains(t, state.Events, "today")
+	assert.NotContains(t, state.Events, "later")
+
+	// 保持している期間より後を取得する場合は全件を取得し直す
+	events, err := provider.ListEvents("primary", "2024-02-15T00:00:00+09:00", "2024-02-16T00:00:00+09:00")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"later"}, eventIDs(events))
+	assert.Equal(t, 0, server.SyncCalls())
+}
+
+func TestTrimEvent(t *testing.T) {
+	event := timedEvent("standup", "朝会", "2024-01-15T09:00:00+09:00", "2024-01-15T09:30:00+09:00")
+	event.Organizer = &calendar.EventOrganizer{Email: "owner@example.com", DisplayName: "オーナー"}
+	event.Attendees = []*calendar.EventAttendee{{Email: "a@example.com", DisplayName: "A", ResponseStatus: "accepted"}}
+	event.HtmlLink = "https://calendar.google.com/event?eid=standup"
+	event.ExtendedProperties = &calendar.EventExtendedProperties{Private: map[string]string{notifiedPropertyKey: "2024-01-15", notifiedByPropertyKey: "instance-a", "other": "x"}}
+
+	trimmed := trimEvent(event)
+	assert.Empty(t, trimmed.HtmlLink)
+	assert.Equal(t, &calendar.EventOrganizer{Email: "owner@example.com"}, trimmed.Organizer)
+	assert.Equal(t, []*calendar.EventAttendee{{Email: "a@example.com"}}, trimmed.Attendees)
+	assert.Equal(t, map[string]string{notifiedPropertyKey: "2024-01-15", notifiedByPropertyKey: "instance-a"}, trimmed.ExtendedProperties.Private)
+
+	// 変換結果は元の予定と変わらない
+	repo := NewGoogleCalendarRepositoryWithProvider(nil, []string{"primary"}, time.FixedZone("JST", 9*60*60))
+	want, err := repo.convertToEvent("primary", event)
+	require.NoError(t, err)
+	got, err := repo.convertToEvent("primary", trimmed)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestSyncingEventsProvider_APIError(t *testing.T) {
+	server := fakes.NewCalendarServer(t)
+	provider, _ := newSyncingProvider(t, server)
+	server.FailWith(http.StatusForbidden)
+
+	_, err := provider.ListEvents("primary", "2024-01-15T00:00:00+09:00", "2024-01-16T00:00:00+09:00")
+	require.Error(t, err)
+}
+
+func TestSyncState_Prune(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	state := &syncState{From: from, Events: map[string]*calendar.Event{
+		"old": timedEvent("old", "古い予定", "2024-01-01T09:00:00Z", "2024-01-01T10:00:00Z"),
+		"new": timedEvent("new", "新しい予定", "2024-01-10T09:00:00Z", "2024-01-10T10:00:00Z"),
+	}}
+
+	state.prune(from.AddDate(0, 0, -1))
+	assert.Len(t, state.Events, 2)
+
+	state.prune(from.AddDate(0, 0, 5))
+	assert.Equal(t, from.AddDate(0, 0, 5), state.From)
+	assert.Contains(t, state.Events, "new")
+	assert.NotContains(t, state.Events, "old")
+}
@@ -0,0 +1,197 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
+)
+
+// lineDeliveryPushPath Push APIで送信したメッセージ数を取得するAPIのパス
+const lineDeliveryPushPath = "/v2/bot/message/delivery/push"
+
+// LINEの集計状況
+const (
+	// DeliveryStatusReady 集計済み
+	DeliveryStatusReady = "ready"
+	// DeliveryStatusUnready 集計中（翌日以降に確定する）
+	DeliveryStatusUnready = "unready"
+	// DeliveryStatusOutOfService 集計の対象外（集計開始前の日付など）
+	DeliveryStatusOutOfService = "out_of_service"
+)
+
+// deliverySchema 配信確認の記録の保存形式のバージョン
+var deliverySchema = store.Schema{Name: "配信確認", Version: 1}
+
+// DeliveryConfirmation LINEが集計した送信数と送信ログの突き合わせ結果
+type DeliveryConfirmation struct {
+	// Date 対象日（JST、YYYY-MM-DD）
+	Date      string    `json:"date"`
+	CheckedAt time.Time `json:"checkedAt"`
+	// Status LINEの集計状況（DeliveryStatusReady など）
+	Status string `json:"status"`
+	// Delivered LINEが集計したPush APIでの送信数（チャネル全体）
+	Delivered int `json:"delivered"`
+	// Recorded 送信ログに記録した送信先へのメッセージ数
+	Recorded int `json:"recorded"`
+}
+
+// Confirmed LINEの集計が確定し、送信ログのメッセージがすべて送信済みとして数えられているかどうか
+func (c DeliveryConfirmation) Confirmed() bool {
+	return c.Status == DeliveryStatusReady && c.Delivered >= c.Recorded
+}
+
+// LINEDeliveryVerifier 送信ログとLINEが集計した送信数を突き合わせ、配信確認として保存する
+// LINEの集計は翌日に確定するため、朝の通知の後に前日分を確認する
+// LINEは日本時間の日付で集計するため、設定されたタイムゾーンに関わらず日付は日本時間で扱う
+type LINEDeliveryVerifier struct {
+	channelAccessToken string
+	baseURL            string
+	httpClient         *http.Client
+	recipient          string
+	audit              *AuditLog
+	store              store.Store
+	retention          time.Duration
+	clock              clock.Clock
+}
+
+// NewLINEDeliveryVerifier 送信先の送信ログと確認結果の保存先を指定してLINEDeliveryVerifierを作成
+// baseURLが空の場合はDefaultLINEAPIBaseURL、transportがnilの場合はhttp.DefaultTransportを使用する
+func NewLINEDeliveryVerifier(channelAccessToken, baseURL string, transport http.RoundTripper, recipient string, audit *AuditLog, s store.Store, retention time.Duration, clk clock.Clock) *LINEDeliveryVerifier {
+	if baseURL == "" {
+		baseURL = DefaultLINEAPIBaseURL
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &LINEDeliveryVerifier{
+		channelAccessToken: channelAccessToken,
+		baseURL:            strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   30 * time.Second,
+		},
+		recipient: recipient,
+		audit:     audit,
+		store:     s,
+		retention: retention,
+		clock:     clk,
+	}
+}
+
+// SendScheduleNotification 前日分の配信を確認する（確認済みの場合は何もしない）
+// 送信ログより少ない送信数しか集計されていない場合はエラーを返す
+func (v *LINEDeliveryVerifier) SendScheduleNotification(ctx context.Context, _ domain.ScheduleReport) error {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	yesterday := dates.AddDays(dates.Today(v.clock.Now(), jst), -1)
+
+	confirmation, err := v.Verify(ctx, yesterday)
+	if err != nil {
+		return err
+	}
+	if confirmation.Status == DeliveryStatusReady && !confirmation.Confirmed() {
+		return fmt.Errorf("%sの配信を確認できませんでした（送信ログ%d件、LINEの集計%d件）", confirmation.Date, confirmation.Recorded, confirmation.Delivered)
+	}
+	return nil
+}
+
+// Verify 指定日（JST）の配信を確認して記録する
+// 集計が確定した確認結果がすでにある場合は、APIを呼ばずにそれを返す
+func (v *LINEDeliveryVerifier) Verify(ctx context.Context, day time.Time) (DeliveryConfirmation, error) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	date := day.In(jst).Format("2006-01-02")
+
+	if saved, err := v.Confirmation(ctx, day); err != nil {
+		return DeliveryConfirmation{}, err
+	} else if saved != nil && saved.Status == DeliveryStatusReady {
+		return *saved, nil
+	}
+
+	records, err := v.audit.Records(ctx, v.recipient, day)
+	if err != nil {
+		return DeliveryConfirmation{}, fmt.Errorf("%sの送信ログの取得に失敗しました: %v", date, err)
+	}
+	recorded := 0
+	for _, record := range records {
+		recorded += len(record.Messages)
+	}
+
+	status, delivered, err := v.pushCount(ctx, day.In(jst).Format("20060102"))
+	if err != nil {
+		return DeliveryConfirmation{}, err
+	}
+
+	confirmation := DeliveryConfirmation{
+		Date:      date,
+		CheckedAt: v.clock.Now(),
+		Status:    status,
+		Delivered: delivered,
+		Recorded:  recorded,
+	}
+	data, err := deliverySchema.Encode(confirmation)
+	if err != nil {
+		return DeliveryConfirmation{}, err
+	}
+	if err := v.store.Put(ctx, v.key(date), data, v.retention); err != nil {
+		return DeliveryConfirmation{}, fmt.Errorf("%sの配信確認の保存に失敗しました: %v", date, err)
+	}
+	return confirmation, nil
+}
+
+// Confirmation 保存された指定日（JST）の配信確認を取得（未確認の場合はnil）
+func (v *LINEDeliveryVerifier) Confirmation(ctx context.Context, day time.Time) (*DeliveryConfirmation, error) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	data, err := v.store.Get(ctx, v.key(day.In(jst).Format("2006-01-02")))
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var confirmation DeliveryConfirmation
+	if _, err := deliverySchema.Decode(data, &confirmation); err != nil {
+		return nil, err
+	}
+	return &confirmation, nil
+}
+
+// pushCount 指定日（yyyyMMdd）にPush APIで送信したメッセージ数を取得
+func (v *LINEDeliveryVerifier) pushCount(ctx context.Context, date string) (status string, success int, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.baseURL+lineDeliveryPushPath+"?date="+date, nil)
+	if err != nil {
+		return "", 0, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+v.channelAccessToken)
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return "", 0, &APIError{Service: "LINE", Kind: ErrorKindTransient, Message: err.Error(), Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, newLINEAPIError(resp)
+	}
+
+	var result struct {
+		Status  string `json:"status"`
+		Success int    `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", 0, fmt.Errorf("送信数のレスポンスを解析できませんでした: %v", err)
+	}
+	return result.Status, result.Success, nil
+}
+
+// key 送信先と日付ごとの配信確認のキー
+func (v *LINEDeliveryVerifier) key(date string) string {
+	return store.Key(store.NamespaceHistory, "delivery:line:"+v.recipient+":"+date)
+}
@@ -0,0 +1,74 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
+)
+
+// newDeliveryTestVerifier 前日（3/4）に2件のメッセージを送信した送信ログと、指定した集計を返すLINE APIのスタブでVerifierを作成
+func newDeliveryTestVerifier(t *testing.T, response string, calls *[]string) *LINEDeliveryVerifier {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls = append(*calls, r.URL.Path+"?"+r.URL.RawQuery)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	jst := time.FixedZone("JST", 9*60*60)
+	yesterday := clock.Fixed(time.Date(2024, 3, 4, 7, 0, 0, 0, jst))
+	s := store.NewMemoryStore(yesterday)
+	audit := NewAuditLog(s, 90*24*time.Hour, false, yesterday)
+	require.NoError(t, audit.Record(context.Background(), "LINE", "U123", "", []lineMessage{{Type: "text", Text: "本日"}, {Type: "text", Text: "翌日"}}))
+
+	now := clock.Fixed(time.Date(2024, 3, 5, 7, 0, 0, 0, jst))
+	return NewLINEDeliveryVerifier("test-token", server.URL, server.Client().Transport, "U123", audit, s, 90*24*time.Hour, now)
+}
+
+func TestLINEDeliveryVerifier_Confirmed(t *testing.T) {
+	var calls []string
+	v := newDeliveryTestVerifier(t, `{"status":"ready","success":2}`, &calls)
+
+	require.NoError(t, v.SendScheduleNotification(context.Background(), domain.ScheduleReport{}))
+	assert.Equal(t, []string{"/v2/bot/message/delivery/push?date=20240304"}, calls)
+
+	jst := time.FixedZone("JST", 9*60*60)
+	confirmation, err := v.Confirmation(context.Background(), time.Date(2024, 3, 4, 0, 0, 0, 0, jst))
+	require.NoError(t, err)
+	require.NotNil(t, confirmation)
+	assert.Equal(t, "2024-03-04", confirmation.Date)
+	assert.Equal(t, 2, confirmation.Recorded)
+	assert.Equal(t, 2, confirmation.Delivered)
+	assert.True(t, confirmation.Confirmed())
+
+	// 集計が確定した日は再度確認しない
+	require.NoError(t, v.SendScheduleNotification(context.Background(), domain.ScheduleReport{}))
+	assert.Len(t, calls, 1)
+}
+
+func TestLINEDeliveryVerifier_Missing(t *testing.T) {
+	var calls []string
+	v := newDeliveryTestVerifier(t, `{"status":"ready","success":1}`, &calls)
+
+	err := v.SendScheduleNotification(context.Background(), domain.ScheduleReport{})
+	assert.ErrorContains(t, err, "送信ログ2件、LINEの集計1件")
+}
+
+func TestLINEDeliveryVerifier_Unready(t *testing.T) {
+	var calls []string
+	v := newDeliveryTestVerifier(t, `{"status":"unready"}`, &calls)
+
+	require.NoError(t, v.SendScheduleNotification(context.Background(), domain.ScheduleReport{}))
+	require.NoError(t, v.SendScheduleNotification(context.Background(), domain.ScheduleReport{}))
+	assert.Len(t, calls, 2, "集計中の場合は次回も確認する")
+}
@@ -0,0 +1,109 @@
+package gateway
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+// lineSubstitution textV2メッセージの置換オブジェクト
+type lineSubstitution struct {
+	Type      string            `json:"type"`
+	Mentionee lineMentionTarget `json:"mentionee"`
+}
+
+// lineMentionTarget メンション対象のユーザー
+type lineMentionTarget struct {
+	Type   string `json:"type"`
+	UserID string `json:"userId"`
+}
+
+// SetMentions 参加者のメールアドレスとLINEユーザーIDの対応を設定
+// 設定されている場合、グループ宛ての通知で該当する参加者をメンションする
+func (n *LINENotifier) SetMentions(emailToUserID map[string]string) {
+	n.mentions = make(map[string]string, len(emailToUserID))
+	for email, userID := range emailToUserID {
+		n.mentions[strings.ToLower(email)] = userID
+	}
+}
+
+// buildMentionSection 参加者をメンションする予定の一覧と置換オブジェクトを作成
+// メンション対象の参加者がいない場合や、送信先がグループ・トークルームでない場合は空文字とnilを返す
+func (n *LINENotifier) buildMentionSection(todayEvents, tomorrowEvents []domain.Event) (string, map[string]lineSubstitution) {
+	if len(n.mentions) == 0 || !isGroupTarget(n.userID) {
+		return "", nil
+	}
+
+	var builder strings.Builder
+	substitutions := make(map[string]lineSubstitution)
+	appendDay := func(label string, events []domain.Event) {
+		schedules, _ := splitDeadlines(events)
+		for _, event := range schedules {
+			keys := n.mentionKeys(event, substitutions)
+			if len(keys) == 0 {
+				continue
+			}
+			when := "終日"
+			if !event.IsAllDay {
+				when = event.StartTime.Format("15:04")
+			}
+			builder.WriteString(fmt.Sprintf("🔸 %s %s %s %s\n", label, when, escapeTextV2(event.Title), strings.Join(keys, " ")))
+		}
+	}
+	appendDay("本日", todayEvents)
+	appendDay("翌日", tomorrowEvents)
+
+	if builder.Len() == 0 {
+		return "", nil
+	}
+	return "👥 参加予定:\n" + strings.TrimSuffix(builder.String(), "\n"), substitutions
+}
+
+// isGroupTarget 送信先がグループ（Cで始まるID）またはトークルーム（Rで始まるID）かどうか
+// 1対1のトーク（Uで始まるユーザーID）ではメンションしても意味がないため使わない
+func isGroupTarget(to string) bool {
+	return strings.HasPrefix(to, "C") || strings.HasPrefix(to, "R")
+}
+
+// mentionKeys 予定の参加者のうちメンション対象のプレースホルダーを返し、置換オブジェクトに登録する
+// 同じユーザーはメッセージ全体で同じキーを使う
+func (n *LINENotifier) mentionKeys(event domain.Event, substitutions map[string]lineSubstitution) []string {
+	var userIDs []string
+	seen := make(map[string]bool)
+	for _, email := range event.Attendees {
+		userID, ok := n.mentions[strings.ToLower(email)]
+		if !ok || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	keys := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		key := ""
+		for k, sub := range substitutions {
+			if sub.Mentionee.UserID == userID {
+				key = k
+				break
+			}
+		}
+		if key == "" {
+			key = fmt.Sprintf("m%d", len(substitutions))
+			substitutions[key] = lineSubstitution{
+				Type:      "mention",
+				Mentionee: lineMentionTarget{Type: "user", UserID: userID},
+			}
+		}
+		keys = append(keys, "{"+key+"}")
+	}
+	return keys
+}
+
+// escapeTextV2 textV2メッセージで { を文字として表示するためにエスケープする
+func escapeTextV2(text string) string {
+	return strings.ReplaceAll(text, "{", "{{")
+}
@@ -0,0 +1,121 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+func TestBuildMentionSection(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	n := newTestLINENotifier("token", "C-group", http.DefaultClient, "", time.Now)
+	n.SetMentions(map[string]string{
+		"Alice@example.com": "U-alice",
+		"bob@example.com":   "U-bob",
+	})
+
+	section, substitutions := n.buildMentionSection(
+		[]domain.Event{
+			{Title: "定例 {週次}", StartTime: time.Date(2024, 1, 15, 10, 0, 0, 0, jst), Attendees: []string{"bob@example.com", "alice@example.com", "carol@example.com"}},
+			{Title: "個人作業", StartTime: time.Date(2024, 1, 15, 13, 0, 0, 0, jst)},
+		},
+		[]domain.Event{
+			{Title: "合宿", IsAllDay: true, Attendees: []string{"bob@example.com"}},
+			{Title: "提出", IsAllDay: true, IsDeadline: true, Attendees: []string{"alice@example.com"}},
+		},
+	)
+
+	assert.Equal(t, "👥 参加予定:\n🔸 本日 10:00 定例 {{週次} {m0} {m1}\n🔸 翌日 終日 合宿 {m1}", section)
+	assert.Equal(t, map[string]lineSubstitution{
+		"m0": {Type: "mention", Mentionee: lineMentionTarget{Type: "user", UserID: "U-alice"}},
+		"m1": {Type: "mention", Mentionee: lineMentionTarget{Type: "user", UserID: "U-bob"}},
+	}, substitutions)
+}
+
+func TestBuildMentionSection_NoMentions(t *testing.T) {
+	n := newTestLINENotifier("token", "C-group", http.DefaultClient, "", time.Now)
+	events := []domain.Event{{Title: "定例", Attendees: []string{"alice@example.com"}}}
+
+	section, substitutions := n.buildMentionSection(events, nil)
+	assert.Empty(t, section)
+	assert.Nil(t, substitutions)
+
+	n.SetMentions(map[string]string{"bob@example.com": "U-bob"})
+	section, substitutions = n.buildMentionSection(events, nil)
+	assert.Empty(t, section)
+	assert.Nil(t, substitutions)
+}
+
+func TestSendScheduleNotification_WithMentions(t *testing.T) {
+	var pushReq linePushRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&pushReq))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	jst := time.FixedZone("JST", 9*60*60)
+	n := newTestLINENotifier("test-token", "C-group", server.Client(), server.URL, func() time.Time {
+		return time.Date(2024, 1, 15, 9, 0, 0, 0, jst)
+	})
+	n.SetMentions(map[string]string{"alice@example.com": "U-alice"})
+
+	err := n.SendScheduleNotification(context.Background(), newTestReport([]domain.Event{
+		{Title: "定例", StartTime: time.Date(2024, 1, 15, 10, 0, 0, 0, jst), EndTime: time.Date(2024, 1, 15, 11, 0, 0, 0, jst), Attendees: []string{"alice@example.com"}},
+	}, nil))
+	require.NoError(t, err)
+
+	require.Len(t, pushReq.Messages, 1)
+	message := pushReq.Messages[0]
+	assert.Equal(t, "textV2", message.Type)
+	assert.Contains(t, message.Text, "Google Calendar LINE Notifier")
+	assert.Contains(t, message.Text, "🔸 本日 10:00 定例 {m0}")
+	assert.Equal(t, "U-alice", message.Substitution["m0"].Mentionee.UserID)
+}
+
+func TestSendScheduleNotification_MentionsOnlyInGroups(t *testing.T) {
+	var pushReq linePushRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&pushReq))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	jst := time.FixedZone("JST", 9*60*60)
+	report := newTestReport([]domain.Event{
+		{Title: "定例", StartTime: time.Date(2024, 1, 15, 10, 0, 0, 0, jst), EndTime: time.Date(2024, 1, 15, 11, 0, 0, 0, jst), Attendees: []string{"alice@example.com"}},
+	}, nil)
+
+	tests := []struct {
+		to       string
+		wantType string
+	}{
+		{to: "U-user", wantType: "text"},
+		{to: "C-group", wantType: "textV2"},
+		{to: "R-room", wantType: "textV2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.to, func(t *testing.T) {
+			n := newTestLINENotifier("test-token", tt.to, server.Client(), server.URL, func() time.Time {
+				return time.Date(2024, 1, 15, 9, 0, 0, 0, jst)
+			})
+			n.SetMentions(map[string]string{"alice@example.com": "U-alice"})
+
+			require.NoError(t, n.SendScheduleNotification(context.Background(), report))
+			require.Len(t, pushReq.Messages, 1)
+			assert.Equal(t, tt.wantType, pushReq.Messages[0].Type)
+			if tt.wantType == "text" {
+				assert.NotContains(t, pushReq.Messages[0].Text, "参加予定")
+				assert.Empty(t, pushReq.Messages[0].Substitution)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
+)
+
+// lineMirror 送信したメッセージを確認用の宛先にも送る設定
+type lineMirror struct {
+	to       string
+	interval time.Duration
+	store    store.Store
+	lastSent time.Time
+}
+
+// SetDebugMirror 送信したメッセージを確認用のLINEユーザー（またはグループ）にも送信する
+// 受信者のトークを見ずに本番の表示を確認するためのもので、interval以内の再送は間引く（0以下の場合は間引かない）
+// sが設定されている場合は送信した記録をストアに保存し、実行をまたいで間引く
+func (n *LINENotifier) SetDebugMirror(to string, interval time.Duration, s store.Store) {
+	n.mirror = &lineMirror{to: to, interval: interval, store: s}
+}
+
+// mirrorMessages 送信したメッセージを確認用の宛先に送信する
+// 確認用の送信に失敗しても本来の通知には影響させない
+func (n *LINENotifier) mirrorMessages(ctx context.Context, messages []lineMessage) {
+	if n.mirror == nil || n.mirror.to == "" || n.mirror.to == n.userID {
+		return
+	}
+	if n.mirrorThrottled(ctx) {
+		fmt.Println("確認用の宛先への送信は間隔内のためスキップしました")
+		return
+	}
+
+	// メンションは本来の宛先のユーザーに向けたものなので、確認用にはプレースホルダーのまま送る
+	mirrored := make([]lineMessage, len(messages))
+	for i, message := range messages {
+		mirrored[i] = lineMessage{Type: "text", Text: message.Text}
+	}
+	if err := n.post(ctx, n.mirror.to, "", mirrored); err != nil {
+		fmt.Printf("Warning: 確認用の宛先への送信に失敗しました: %v\n", err)
+		return
+	}
+	n.markMirrored(ctx)
+}
+
+// mirrorThrottled 前回の確認用の送信から間隔が経っていないかどうか
+func (n *LINENotifier) mirrorThrottled(ctx context.Context) bool {
+	m := n.mirror
+	if m.interval <= 0 {
+		return false
+	}
+	if m.store == nil {
+		return !m.lastSent.IsZero() && n.clock.Now().Sub(m.lastSent) < m.interval
+	}
+	_, err := m.store.Get(ctx, store.Key(store.NamespaceMirror, m.to))
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		fmt.Printf("Warning: 確認用の送信記録の取得に失敗しました: %v\n", err)
+	}
+	return err == nil
+}
+
+// markMirrored 確認用の送信を記録する（間隔が経つとストアから消える）
+func (n *LINENotifier) markMirrored(ctx context.Context) {
+	m := n.mirror
+	m.lastSent = n.clock.Now()
+	if m.interval <= 0 || m.store == nil {
+		return
+	}
+	value := []byte(m.lastSent.UTC().Format(time.RFC3339))
+	if err := m.store.Put(ctx, store.Key(store.NamespaceMirror, m.to), value, m.interval); err != nil {
+		fmt.Printf("Warning: 確認用の送信記録の保存に失敗しました: %v\n", err)
+	}
+}
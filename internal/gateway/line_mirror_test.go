@@ -0,0 +1,91 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
+)
+
+// mirrorTestServer 受け取ったPushリクエストを記録するLINE APIのスタブ
+type mirrorTestServer struct {
+	mu       sync.Mutex
+	requests []linePushRequest
+}
+
+func (s *mirrorTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pushReq linePushRequest
+	if err := json.NewDecoder(r.Body).Decode(&pushReq); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.requests = append(s.requests, pushReq)
+	w.WriteHeader(http.StatusOK)
+}
+
+// recipients 受け取ったリクエストの宛先
+func (s *mirrorTestServer) recipients() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var to []string
+	for _, req := range s.requests {
+		to = append(to, req.To)
+	}
+	return to
+}
+
+func TestLINENotifier_DebugMirror(t *testing.T) {
+	handler := &mirrorTestServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, time.UTC)
+	clk := clock.Func(func() time.Time { return now })
+	n := newTestLINENotifier("test-token", "test-user", server.Client(), server.URL, func() time.Time { return now })
+	n.SetMentions(map[string]string{"alice@example.com": "Ualice"})
+	n.SetDebugMirror("debug-user", time.Hour, store.NewMemoryStore(clk))
+
+	report := newTestReport([]domain.Event{{Title: "朝会", StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour), Attendees: []string{"alice@example.com"}}}, nil)
+	require.NoError(t, n.SendScheduleNotification(context.Background(), report))
+	assert.Equal(t, []string{"test-user", "debug-user"}, handler.recipients())
+
+	mirrored := handler.requests[1].Messages[0]
+	assert.Equal(t, "text", mirrored.Type, "確認用にはメンションせずに送る")
+	assert.Equal(t, handler.requests[0].Messages[0].Text, mirrored.Text)
+
+	// 間隔内の送信は確認用の宛先には送らない
+	now = now.Add(30 * time.Minute)
+	require.NoError(t, n.SendScheduleNotification(context.Background(), report))
+	assert.Equal(t, []string{"test-user", "debug-user", "test-user"}, handler.recipients())
+
+	now = now.Add(time.Hour)
+	require.NoError(t, n.SendScheduleNotification(context.Background(), report))
+	assert.Equal(t, []string{"test-user", "debug-user", "test-user", "test-user", "debug-user"}, handler.recipients())
+}
+
+func TestLINENotifier_DebugMirror_InMemoryThrottle(t *testing.T) {
+	handler := &mirrorTestServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, time.UTC)
+	n := newTestLINENotifier("test-token", "test-user", server.Client(), server.URL, func() time.Time { return now })
+	n.SetDebugMirror("debug-user", time.Hour, nil)
+
+	require.NoError(t, n.sendPushMessage(context.Background(), "1通目"))
+	require.NoError(t, n.sendPushMessage(context.Background(), "2通目"))
+	assert.Equal(t, []string{"test-user", "debug-user", "test-user"}, handler.recipients())
+}
@@ -8,8 +8,23 @@ import (
 	"net/http"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
 	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/runid"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/timing"
+)
+
+const (
+	// DefaultLINEAPIBaseURL LINE Messaging APIのデフォルトのベースURL
+	DefaultLINEAPIBaseURL = "https://api.line.me"
+	// linePushPath LINE Push APIのパス
+	linePushPath = "/v2/bot/message/push"
+	// scheduleMessageHeader 予定通知メッセージの見出し
+	scheduleMessageHeader = "Google Calendar LINE Notifier\n\n"
 )
 
 // LINENotifier LINE Messaging APIを使用したNotifierの実装
@@ -18,13 +33,22 @@ type LINENotifier struct {
 	userID             string
 	httpClient         *http.Client
 	endpoint           string
-	clock              func() time.Time
+	clock              clock.Clock
+	exporter           ScheduleExporter
+	format             MessageFormat
+	mentions           map[string]string
+	outbox             store.Store
+	audit              *AuditLog
+	mirror             *lineMirror
+	debugFooter        bool
+	dryRun             bool
 }
 
 // lineMessage LINE APIに送信するメッセージ構造体
 type lineMessage struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+	Type         string                      `json:"type"`
+	Text         string                      `json:"text"`
+	Substitution map[string]lineSubstitution `json:"substitution,omitempty"`
 }
 
 // linePushRequest LINE Push APIのリクエスト構造体
@@ -43,62 +67,187 @@ type lineErrorResponse struct {
 }
 
 // NewLINENotifier LINE通知クライアントを作成
-func NewLINENotifier(channelAccessToken, userID string) *LINENotifier {
+// baseURLが空の場合はDefaultLINEAPIBaseURL、transportがnilの場合はhttp.DefaultTransportを使用する
+func NewLINENotifier(channelAccessToken, userID, baseURL string, transport http.RoundTripper, clk clock.Clock) *LINENotifier {
+	if baseURL == "" {
+		baseURL = DefaultLINEAPIBaseURL
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
 	return &LINENotifier{
 		channelAccessToken: channelAccessToken,
 		userID:             userID,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Transport: transport,
+			Timeout:   30 * time.Second,
 		},
-		endpoint: "https://api.line.me/v2/bot/message/push",
-		clock:    time.Now,
+		endpoint: strings.TrimSuffix(baseURL, "/") + linePushPath,
+		clock:    clk,
 	}
 }
 
-// SendScheduleNotification カレンダー予定をLINEで通知
-func (n *LINENotifier) SendScheduleNotification(ctx context.Context, todayEvents, tomorrowEvents []domain.Event) error {
-	// 通知メッセージを作成
-	message := n.buildScheduleMessage(todayEvents, tomorrowEvents)
+// SetScheduleExporter 本日の予定をエクスポートするScheduleExporterを設定
+// 設定されている場合、通知メッセージの末尾にカレンダー取り込み用のリンクを追加する
+func (n *LINENotifier) SetScheduleExporter(exporter ScheduleExporter) {
+	n.exporter = exporter
+}
 
-	// LINE Push APIでメッセージを送信
-	return n.sendPushMessage(ctx, message)
+// SetDebugFooter デバッグ用に通知メッセージの末尾へ実行IDを表示するかを設定
+func (n *LINENotifier) SetDebugFooter(enabled bool) {
+	n.debugFooter = enabled
 }
 
-// buildScheduleMessage 予定通知用のメッセージを構築
-func (n *LINENotifier) buildScheduleMessage(todayEvents, tomorrowEvents []domain.Event) string {
-	var messageBuilder strings.Builder
-	jst, _ := time.LoadLocation("Asia/Tokyo")
-	today := n.clock().In(jst)
+// SetDryRun trueの場合はLINEに送信せず、送信するはずだったメッセージをログに出力する
+func (n *LINENotifier) SetDryRun(enabled bool) {
+	n.dryRun = enabled
+}
 
-	// Google Calendar LINE Notifier
-	messageBuilder.WriteString("Google Calendar LINE Notifier\n\n")
+// SetMessageFormat 通知メッセージの表示オプションを設定
+func (n *LINENotifier) SetMessageFormat(format MessageFormat) {
+	n.format = format
+}
+
+// SendScheduleNotification カレンダー予定をLINEで通知
+func (n *LINENotifier) SendScheduleNotification(ctx context.Context, report domain.ScheduleReport) error {
+	todayEvents, tomorrowEvents := report.Today.Events, report.Tomorrow.Events
+
+	// 通知メッセージを作成（日ごとに分ける設定の場合は本日・翌日の2通）
+	stopBuild := timing.Start(ctx, "build")
+	texts := TextRenderer{Format: n.format}.Render(report)
+	stopBuild()
+
+	// 本日の予定のエクスポートリンクを追加（失敗しても通知は継続する）
+	if link := n.exportTodayEvents(ctx, todayEvents); link != "" {
+		texts[0] += n.format.render("\n\n📅 本日の予定をカレンダーに追加:\n" + link)
+	}
+
+	// デバッグ時は配信を追跡できるよう実行IDを末尾に表示
+	if id := runid.FromContext(ctx); n.debugFooter && id != "" {
+		texts[len(texts)-1] += "\n\nrun: " + id
+	}
+
+	// LINEの文字数・通数の上限に収まるよう分割（メンションを付ける分の余白を残す）
+	policy := LINEChunkPolicy
+	section, substitutions := n.buildMentionSection(todayEvents, tomorrowEvents)
+	if section != "" {
+		section = n.format.render(section)
+		policy.MaxLength -= utf8.RuneCountInString(section) + 2
+	}
+	texts = policy.Apply(texts)
+
+	messages := make([]lineMessage, len(texts))
+	for i, text := range texts {
+		messages[i] = lineMessage{Type: "text", Text: text}
+	}
 
-	// 本日の予定
-	dowToday := getWeekdayJapanese(today.Weekday())
-	if len(todayEvents) > 0 {
-		messageBuilder.WriteString(fmt.Sprintf("本日 %s(%s) (%d件):\n", today.Format("1/2"), dowToday, len(todayEvents)))
-		for _, event := range todayEvents {
-			appendEventToMessage(&messageBuilder, event)
+	// メンション対象の参加者がいれば最後のメッセージをtextV2として送信
+	if section != "" {
+		last := len(messages) - 1
+		messages[last] = lineMessage{
+			Type:         "textV2",
+			Text:         escapeTextV2(texts[last]) + "\n\n" + section,
+			Substitution: substitutions,
 		}
-	} else {
-		messageBuilder.WriteString(fmt.Sprintf("本日 %s(%s): 予定なし\n", today.Format("1/2"), dowToday))
 	}
 
-	messageBuilder.WriteString("\n\n")
+	// LINE Push APIでメッセージを送信（アウトボックスが設定されていれば経由する）
+	defer timing.Start(ctx, "send")()
+	if n.outbox != nil {
+		return n.deliverViaOutbox(ctx, messages)
+	}
+	return n.pushMessages(ctx, messages...)
+}
+
+// exportTodayEvents 本日の予定をエクスポートし、取得用URLを返す
+// エクスポーターが未設定、予定がない、またはエクスポートに失敗した場合は空文字を返す
+func (n *LINENotifier) exportTodayEvents(ctx context.Context, todayEvents []domain.Event) string {
+	if n.exporter == nil {
+		return ""
+	}
+	own, _ := splitFamily(todayEvents)
+	schedules, _ := splitDeadlines(own)
+	if len(schedules) == 0 {
+		return ""
+	}
+
+	defer timing.Start(ctx, "export")()
+	url, err := n.exporter.Export(ctx, n.clock.Now().In(dates.Location()), schedules)
+	if err != nil {
+		fmt.Printf("Warning: 予定のエクスポートに失敗しました: %v\n", err)
+		return ""
+	}
+	return url
+}
+
+// appendDaySection 1日分の予定と締め切りをメッセージに追加
+// 日付の見出しの後のブロックはMessageFormat.Sectionsの順に表示する
+func (f MessageFormat) appendDaySection(builder *strings.Builder, label string, day domain.DaySchedule) {
+	layout := f.layoutDay(day)
+	template := f.template(layout)
 
-	// 翌日の予定
-	tomorrow := today.Add(24 * time.Hour)
-	dowTomorrow := getWeekdayJapanese(tomorrow.Weekday())
-	if len(tomorrowEvents) > 0 {
-		messageBuilder.WriteString(fmt.Sprintf("翌日 %s(%s) (%d件):\n", tomorrow.Format("1/2"), dowTomorrow, len(tomorrowEvents)))
-		for _, event := range tomorrowEvents {
-			appendEventToMessage(&messageBuilder, event)
+	if layout.Count > 0 {
+		builder.WriteString(fmt.Sprintf("%s %s (%d件):\n", label, dayHeading(layout.Date), layout.Count))
+		if template == dayTemplateMeetingFree {
+			builder.WriteString(meetingFreeMessage + "\n")
 		}
+	} else if template == dayTemplateMeetingFree {
+		builder.WriteString(fmt.Sprintf("%s %s: %s\n", label, dayHeading(layout.Date), meetingFreeMessage))
 	} else {
-		messageBuilder.WriteString(fmt.Sprintf("翌日 %s(%s): 予定なし\n", tomorrow.Format("1/2"), dowTomorrow))
+		builder.WriteString(fmt.Sprintf("%s %s: 予定なし\n", label, dayHeading(layout.Date)))
 	}
 
-	return messageBuilder.String()
+	for _, section := range f.Sections.daySections() {
+		switch section {
+		case SectionEvents:
+			for _, event := range layout.Listed {
+				appendEventToMessage(builder, event)
+			}
+			// 終日予定を別ブロックに分ける設定の場合
+			if len(layout.AllDay) > 0 {
+				builder.WriteString("📆 終日:\n")
+				for _, event := range layout.AllDay {
+					builder.WriteString(fmt.Sprintf("🔸 %s\n", event.Title))
+					appendEventDetails(builder, event)
+				}
+			}
+			if layout.Hidden > 0 {
+				builder.WriteString(fmt.Sprintf("…他%d件 (タップで全件)\n%s\n", layout.Hidden, googleCalendarDayURL(layout.Date)))
+			}
+		case SectionSummary:
+			if layout.Summary != "" {
+				builder.WriteString(layout.Summary + "\n")
+			}
+		case SectionDeadlines:
+			// 締め切りは通常の予定とは別のブロックに表示
+			if len(layout.Deadlines) > 0 {
+				builder.WriteString("📋 締め切り:\n")
+				for _, deadline := range layout.Deadlines {
+					builder.WriteString(fmt.Sprintf("🔸 %s\n", deadline.Title))
+				}
+			}
+		case SectionFamily:
+			// 家族のカレンダーの予定も別のブロックに表示
+			if len(layout.Family) > 0 {
+				builder.WriteString("👨‍👩‍👧 家族:\n")
+				for _, event := range layout.Family {
+					appendEventToMessage(builder, event)
+				}
+			}
+		}
+	}
+}
+
+// splitDeadlines イベントを通常の予定と締め切りに分ける
+func splitDeadlines(events []domain.Event) (schedules, deadlines []domain.Event) {
+	for _, event := range events {
+		if event.IsDeadline {
+			deadlines = append(deadlines, event)
+		} else {
+			schedules = append(schedules, event)
+		}
+	}
+	return schedules, deadlines
 }
 
 // appendEventToMessage イベントをメッセージに追加
@@ -112,23 +261,78 @@ func appendEventToMessage(builder *strings.Builder, event domain.Event) {
 		builder.WriteString(fmt.Sprintf("🔸 %s %s\n", timeRange, event.Title))
 	}
 
-	// 場所情報があれば追加
-	if event.Location != "" {
+	appendEventDetails(builder, event)
+}
+
+// appendEventDetails 予定の場所・参加者・リンクをメッセージに追加
+func appendEventDetails(builder *strings.Builder, event domain.Event) {
+	// 場所情報があれば追加（URLのみの場所はリンクとして表示する）
+	if event.Location != "" && !isURLOnly(event.Location) {
 		builder.WriteString(fmt.Sprintf("   📍 %s\n", event.Location))
 	}
+
+	// 表示名が設定されている参加者
+	if len(event.AttendeeNames) > 0 {
+		builder.WriteString(fmt.Sprintf("   👤 %s\n", strings.Join(event.AttendeeNames, "、")))
+	}
+
+	// 場所・説明文に含まれる参加用URLなどのリンク
+	if link := eventLink(event); link != "" {
+		builder.WriteString(fmt.Sprintf("   🔗 %s\n", link))
+	}
 }
 
 // sendPushMessage LINE Push APIでメッセージを送信
 func (n *LINENotifier) sendPushMessage(ctx context.Context, message string) error {
+	return n.pushMessages(ctx, lineMessage{
+		Type: "text",
+		Text: message,
+	})
+}
+
+// SendTestMessage 任意のテキストをそのまま送信し、送信設定（トークン・宛先・通信経路）を確認する
+// toが空の場合は通知先のユーザーに送信する。確認用の送信のため、送信ログや確認用の宛先には残さない
+func (n *LINENotifier) SendTestMessage(ctx context.Context, to, text string) error {
+	if to == "" {
+		to = n.userID
+	}
+	if n.dryRun {
+		fmt.Printf("[DRY RUN] %s へのテスト送信をスキップしました:\n%s\n", to, text)
+		return nil
+	}
+	return n.post(ctx, to, "", []lineMessage{{Type: "text", Text: text}})
+}
+
+// pushMessages LINE Push APIで1回のリクエストで複数のメッセージを送信
+func (n *LINENotifier) pushMessages(ctx context.Context, messages ...lineMessage) error {
+	return n.pushMessagesWithRetryKey(ctx, "", messages)
+}
+
+// pushMessagesWithRetryKey リトライキーを付けてメッセージを送信
+// 同じリトライキーで再送した場合、LINE側で重複送信が防がれる（空の場合は付与しない）
+func (n *LINENotifier) pushMessagesWithRetryKey(ctx context.Context, retryKey string, messages []lineMessage) error {
+	if n.dryRun {
+		for i, message := range messages {
+			fmt.Printf("[DRY RUN] LINE送信をスキップしました (%d/%d):\n%s\n", i+1, len(messages), message.Text)
+		}
+		return nil
+	}
+
+	if err := n.post(ctx, n.userID, retryKey, messages); err != nil {
+		return err
+	}
+
+	n.recordAudit(ctx, retryKey, messages)
+	n.mirrorMessages(ctx, messages)
+	return nil
+}
+
+// post LINE Push APIで宛先にメッセージを送信
+func (n *LINENotifier) post(ctx context.Context, to, retryKey string, messages []lineMessage) error {
 	// リクエストボディを作成
 	pushRequest := linePushRequest{
-		To: n.userID,
-		Messages: []lineMessage{
-			{
-				Type: "text",
-				Text: message,
-			},
-		},
+		To:       to,
+		Messages: messages,
 	}
 
 	requestBody, err := json.Marshal(pushRequest)
@@ -150,31 +354,59 @@ func (n *LINENotifier) sendPushMessage(ctx context.Context, message string) erro
 	// ヘッダーを設定
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", n.channelAccessToken))
+	if retryKey != "" {
+		req.Header.Set("X-Line-Retry-Key", retryKey)
+	}
 
 	// APIリクエストを送信
 	resp, err := n.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("LINE APIリクエストの送信に失敗しました: %v", err)
+		return &APIError{
+			Service: "LINE",
+			Kind:    ErrorKindTransient,
+			Message: err.Error(),
+			Err:     err,
+		}
 	}
 	defer resp.Body.Close()
 
 	// レスポンスを確認
 	if resp.StatusCode != http.StatusOK {
-		// エラーレスポンスの詳細を取得
-		var errorResponse lineErrorResponse
-		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
-			return fmt.Errorf("LINE API呼び出しが失敗しました (Status: %d, レスポンス解析不可: %v)", resp.StatusCode, err)
-		}
+		return newLINEAPIError(resp)
+	}
+	return nil
+}
 
-		errorDetails := errorResponse.Message
-		if len(errorResponse.Details) > 0 {
-			errorDetails += fmt.Sprintf(" (詳細: %s)", errorResponse.Details[0].Message)
-		}
+// newLINEAPIError LINE APIのエラーレスポンスからAPIErrorを作成
+func newLINEAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		Service:    "LINE",
+		StatusCode: resp.StatusCode,
+		Kind:       classifyStatus(resp.StatusCode),
+	}
 
-		return fmt.Errorf("LINE API呼び出しが失敗しました (Status: %d): %s", resp.StatusCode, errorDetails)
+	// エラーレスポンスの詳細を取得（巨大なボディに備えて上限付きで読み込む）
+	body, err := readErrorBody(resp.Body)
+	if err != nil {
+		apiErr.Message = fmt.Sprintf("レスポンス読み込み不可: %v", err)
+		return apiErr
 	}
 
-	return nil
+	var errorResponse lineErrorResponse
+	if err := json.Unmarshal(body, &errorResponse); err != nil || errorResponse.Message == "" {
+		// JSONとして解析できない場合は生のボディをログに残す
+		fmt.Printf("Warning: LINE APIのエラーレスポンスを解析できませんでした (Status: %d): %s\n", resp.StatusCode, truncateBody(body))
+		apiErr.Message = fmt.Sprintf("レスポンス解析不可: %s", truncateBody(body))
+		return apiErr
+	}
+
+	errorDetails := errorResponse.Message
+	if len(errorResponse.Details) > 0 {
+		errorDetails += fmt.Sprintf(" (詳細: %s)", errorResponse.Details[0].Message)
+	}
+	apiErr.Message = errorDetails
+
+	return apiErr
 }
 
 // getWeekdayJapanese 曜日を日本語に変換
@@ -3,9 +3,11 @@ package gateway
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -13,20 +15,44 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/k-negishi/google-calendar-line-notifier/internal/category"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
 	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/runid"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/testutil"
 )
 
 // newTestLINENotifier テスト用の LINENotifier を構築するヘルパー
-func newTestLINENotifier(token, userID string, httpClient *http.Client, endpoint string, clock func() time.Time) *LINENotifier {
+func newTestLINENotifier(token, userID string, httpClient *http.Client, endpoint string, now func() time.Time) *LINENotifier {
 	return &LINENotifier{
 		channelAccessToken: token,
 		userID:             userID,
 		httpClient:         httpClient,
 		endpoint:           endpoint,
-		clock:              clock,
+		clock:              clock.Func(now),
 	}
 }
 
+// newTestReport 本日・翌日の予定からテスト用のScheduleReportを作成（本日は2024-01-15）
+func newTestReport(todayEvents, tomorrowEvents []domain.Event) domain.ScheduleReport {
+	return reportAt(time.Date(2024, 1, 15, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60)), todayEvents, tomorrowEvents)
+}
+
+// reportAt 通知時点nowの本日・翌日の予定からScheduleReportを作成
+// ユースケースと同じく、日付は通知のタイムゾーンで算出する
+func reportAt(now time.Time, todayEvents, tomorrowEvents []domain.Event) domain.ScheduleReport {
+	today := dates.Today(now, dates.Location())
+	return domain.NewScheduleReport(now, today, dates.AddDays(today, 1), todayEvents, tomorrowEvents)
+}
+
+// renderMessage 通知の表示オプションでレポートを1通のメッセージとして描画
+func renderMessage(n *LINENotifier, report domain.ScheduleReport) string {
+	format := n.format
+	format.SplitDays = false
+	return TextRenderer{Format: format}.Render(report)[0]
+}
+
 // --- getWeekdayJapanese テスト ---
 
 func TestGetWeekdayJapanese(t *testing.T) {
@@ -50,7 +76,7 @@ func TestGetWeekdayJapanese(t *testing.T) {
 	}
 }
 
-// --- buildScheduleMessage テスト ---
+// --- TextRenderer テスト ---
 
 func TestBuildScheduleMessage_WithEvents(t *testing.T) {
 	jst := time.FixedZone("JST", 9*60*60)
@@ -67,7 +93,7 @@ func TestBuildScheduleMessage_WithEvents(t *testing.T) {
 		{Title: "終日イベント", IsAllDay: true},
 	}
 
-	message := n.buildScheduleMessage(todayEvents, tomorrowEvents)
+	message := renderMessage(n, reportAt(fixedTime, todayEvents, tomorrowEvents))
 
 	assert.Contains(t, message, "本日 1/15(月)")
 	assert.Contains(t, message, "(1件)")
@@ -84,12 +110,199 @@ func TestBuildScheduleMessage_NoEvents(t *testing.T) {
 		return fixedTime
 	})
 
-	message := n.buildScheduleMessage(nil, nil)
+	message := renderMessage(n, reportAt(fixedTime, nil, nil))
 
 	assert.Contains(t, message, "本日 1/15(月): 予定なし")
 	assert.Contains(t, message, "翌日 1/16(火): 予定なし")
 }
 
+func TestBuildScheduleMessage_DateBoundaries(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		today    string
+		tomorrow string
+	}{
+		{"年末", time.Date(2024, 12, 31, 9, 0, 0, 0, jst), "本日 12/31(火)", "翌日 1/1(水)"},
+		{"UTCでは前年", time.Date(2024, 12, 31, 15, 30, 0, 0, time.UTC), "本日 1/1(水)", "翌日 1/2(木)"},
+		{"月末", time.Date(2024, 4, 30, 9, 0, 0, 0, jst), "本日 4/30(火)", "翌日 5/1(水)"},
+		{"うるう年の2/28", time.Date(2024, 2, 28, 9, 0, 0, 0, jst), "本日 2/28(水)", "翌日 2/29(木)"},
+		{"平年の2/28", time.Date(2023, 2, 28, 9, 0, 0, 0, jst), "本日 2/28(火)", "翌日 3/1(水)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newTestLINENotifier("token", "user", http.DefaultClient, "", func() time.Time {
+				return tt.now
+			})
+
+			message := renderMessage(n, reportAt(tt.now, nil, nil))
+
+			assert.Contains(t, message, tt.today+": 予定なし")
+			assert.Contains(t, message, tt.tomorrow+": 予定なし")
+		})
+	}
+}
+
+// --- TextRenderer ゴールデンファイルテスト ---
+
+func TestBuildScheduleMessage_Golden(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	fixedTime := time.Date(2024, 1, 15, 9, 0, 0, 0, jst)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, jst)
+	}
+
+	tests := []struct {
+		name           string
+		format         MessageFormat
+		todayEvents    []domain.Event
+		tomorrowEvents []domain.Event
+		warnings       []domain.Warning
+	}{
+		{
+			name: "with_events",
+			todayEvents: []domain.Event{
+				{Title: "チームミーティング", StartTime: at(15, 9, 0), EndTime: at(15, 10, 30), Location: "会議室A"},
+				{Title: "プロジェクト準備", IsAllDay: true},
+			},
+			tomorrowEvents: []domain.Event{
+				{Title: "顧客打ち合わせ", StartTime: at(16, 14, 0), EndTime: at(16, 15, 0), Location: "オンライン"},
+			},
+		},
+		{
+			name:        "today_only",
+			todayEvents: []domain.Event{{Title: "歯医者", StartTime: at(15, 18, 0), EndTime: at(15, 19, 0)}},
+		},
+		{
+			name: "no_events",
+		},
+		{
+			name:        "with_deadlines",
+			todayEvents: []domain.Event{{Title: "example/app#12 リリースノート作成 [v1.0]", IsAllDay: true, IsDeadline: true}},
+			tomorrowEvents: []domain.Event{
+				{Title: "顧客打ち合わせ", StartTime: at(16, 14, 0), EndTime: at(16, 15, 0)},
+				{Title: "example/app#34 API移行 [v1.1]", IsAllDay: true, IsDeadline: true},
+			},
+		},
+		{
+			name:   "all_day_first",
+			format: MessageFormat{AllDayPlacement: AllDayPlacementFirst},
+			todayEvents: []domain.Event{
+				{Title: "朝会", StartTime: at(15, 9, 0), EndTime: at(15, 9, 15)},
+				{Title: "出張", IsAllDay: true, Location: "大阪"},
+				{Title: "レビュー", StartTime: at(15, 13, 0), EndTime: at(15, 14, 0)},
+				{Title: "在宅勤務", IsAllDay: true},
+			},
+		},
+		{
+			name:   "all_day_last",
+			format: MessageFormat{AllDayPlacement: AllDayPlacementLast},
+			todayEvents: []domain.Event{
+				{Title: "朝会", StartTime: at(15, 9, 0), EndTime: at(15, 9, 15)},
+				{Title: "出張", IsAllDay: true, Location: "大阪"},
+				{Title: "レビュー", StartTime: at(15, 13, 0), EndTime: at(15, 14, 0)},
+				{Title: "在宅勤務", IsAllDay: true},
+			},
+		},
+		{
+			name:   "collapsed",
+			format: MessageFormat{MaxEventsPerDay: 2},
+			todayEvents: []domain.Event{
+				{Title: "朝会", StartTime: at(15, 9, 0), EndTime: at(15, 9, 15)},
+				{Title: "設計レビュー", StartTime: at(15, 10, 0), EndTime: at(15, 11, 0)},
+				{Title: "ランチ", StartTime: at(15, 12, 0), EndTime: at(15, 13, 0)},
+				{Title: "1on1", StartTime: at(15, 15, 0), EndTime: at(15, 15, 30)},
+			},
+			tomorrowEvents: []domain.Event{
+				{Title: "顧客打ち合わせ", StartTime: at(16, 14, 0), EndTime: at(16, 15, 0)},
+			},
+		},
+		{
+			name: "category_totals",
+			format: MessageFormat{Classifier: category.NewClassifier([]category.Rule{
+				{Name: "会議", Keywords: []string{"朝会", "レビュー"}},
+				{Name: "移動", Keywords: []string{"移動"}},
+			})},
+			todayEvents: []domain.Event{
+				{Title: "朝会", StartTime: at(15, 9, 0), EndTime: at(15, 9, 15)},
+				{Title: "設計レビュー", StartTime: at(15, 10, 0), EndTime: at(15, 11, 30)},
+				{Title: "ランチ", StartTime: at(15, 12, 0), EndTime: at(15, 13, 0)},
+				{Title: "出張", IsAllDay: true},
+			},
+		},
+		{
+			name: "early_warning",
+			todayEvents: []domain.Event{
+				{Title: "設計レビュー", StartTime: at(15, 8, 30), EndTime: at(15, 9, 30), Location: "本社 5F"},
+				{Title: "1on1", StartTime: at(15, 15, 0), EndTime: at(15, 15, 30)},
+			},
+			warnings: []domain.Warning{{
+				Kind:  domain.WarningEarlyOffice,
+				Event: domain.Event{Title: "設計レビュー", StartTime: at(15, 8, 30), EndTime: at(15, 9, 30), Location: "本社 5F"},
+			}},
+		},
+		{
+			name:   "plain_text",
+			format: MessageFormat{PlainText: true},
+			todayEvents: []domain.Event{
+				{Title: "🎂 誕生日会", StartTime: at(15, 18, 0), EndTime: at(15, 20, 0), Location: "渋谷", Description: "https://zoom.us/j/123"},
+				{Title: "example/app#12 リリース [v1.0]", IsAllDay: true, IsDeadline: true},
+			},
+		},
+		{
+			name: "with_family",
+			todayEvents: []domain.Event{
+				{Title: "朝会", StartTime: at(15, 9, 0), EndTime: at(15, 9, 15)},
+				{Title: "保育園お迎え", StartTime: at(15, 17, 30), EndTime: at(15, 18, 0), IsFamily: true},
+			},
+			tomorrowEvents: []domain.Event{
+				{Title: "授業参観", IsAllDay: true, Location: "小学校", IsFamily: true},
+			},
+		},
+		{
+			name:   "meeting_free",
+			format: MessageFormat{CelebrateMeetingFree: true},
+			todayEvents: []domain.Event{
+				{Title: "在宅勤務", IsAllDay: true},
+			},
+			tomorrowEvents: []domain.Event{
+				{Title: "顧客打ち合わせ", StartTime: at(16, 14, 0), EndTime: at(16, 15, 0)},
+			},
+		},
+		{
+			name:   "meeting_free_empty",
+			format: MessageFormat{CelebrateMeetingFree: true},
+		},
+		{
+			name:   "all_day_separate",
+			format: MessageFormat{AllDayPlacement: AllDayPlacementSeparate},
+			todayEvents: []domain.Event{
+				{Title: "朝会", StartTime: at(15, 9, 0), EndTime: at(15, 9, 15)},
+				{Title: "出張", IsAllDay: true, Location: "大阪"},
+				{Title: "レビュー", StartTime: at(15, 13, 0), EndTime: at(15, 14, 0)},
+				{Title: "在宅勤務", IsAllDay: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newTestLINENotifier("token", "user", http.DefaultClient, "", func() time.Time {
+				return fixedTime
+			})
+			n.SetMessageFormat(tt.format)
+
+			report := reportAt(fixedTime, tt.todayEvents, tt.tomorrowEvents)
+			report.Warnings = tt.warnings
+			message := renderMessage(n, report)
+			testutil.AssertGolden(t, filepath.Join("testdata", "golden", tt.name+".txt"), []byte(message))
+		})
+	}
+}
+
 // --- appendEventToMessage テスト ---
 
 func TestAppendEventToMessage_TimedEvent(t *testing.T) {
@@ -144,6 +357,41 @@ func TestAppendEventToMessage_WithLocation(t *testing.T) {
 	assert.Contains(t, result, "📍 渋谷オフィス")
 }
 
+func TestAppendEventToMessage_WithLink(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	start := time.Date(2024, 1, 15, 14, 0, 0, 0, jst)
+
+	tests := []struct {
+		name     string
+		event    domain.Event
+		expected string
+	}{
+		{
+			name:     "説明文のURL",
+			event:    domain.Event{Title: "定例", StartTime: start, EndTime: start.Add(time.Hour), Location: "会議室A", Description: `議題は<a href="https://docs.example.com/agenda">こちら</a>`},
+			expected: "🔸 14:00〜15:00 定例\n   📍 会議室A\n   🔗 https://docs.example.com/agenda\n",
+		},
+		{
+			name:     "URLのみの場所はリンクとして表示",
+			event:    domain.Event{Title: "面談", StartTime: start, EndTime: start.Add(time.Hour), Location: "https://zoom.us/j/123", Description: "https://example.com/other"},
+			expected: "🔸 14:00〜15:00 面談\n   🔗 Zoom: https://zoom.us/j/123\n",
+		},
+		{
+			name:     "URLなし",
+			event:    domain.Event{Title: "ランチ", StartTime: start, EndTime: start.Add(time.Hour), Description: "いつもの店"},
+			expected: "🔸 14:00〜15:00 ランチ\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var builder strings.Builder
+			appendEventToMessage(&builder, tt.event)
+			assert.Equal(t, tt.expected, builder.String())
+		})
+	}
+}
+
 // --- sendPushMessage テスト（httptest 使用） ---
 
 func TestSendPushMessage_Success(t *testing.T) {
@@ -190,6 +438,40 @@ func TestSendPushMessage_APIError(t *testing.T) {
 	assert.Contains(t, err.Error(), "LINE API呼び出しが失敗しました")
 }
 
+func TestSendPushMessage_AuthError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, err := w.Write([]byte(`{"message":"Authentication failed"}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	n := newTestLINENotifier("invalid-token", "test-user", server.Client(), server.URL, time.Now)
+
+	err := n.sendPushMessage(context.Background(), "テストメッセージ")
+	require.Error(t, err)
+	assert.Equal(t, ErrorKindAuth, ErrorKindOf(err))
+	assert.False(t, IsRetryable(err))
+	assert.Contains(t, err.Error(), "Authentication failed")
+}
+
+func TestSendPushMessage_NonJSONErrorBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, err := w.Write([]byte("<html>Bad Gateway</html>"))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	n := newTestLINENotifier("test-token", "test-user", server.Client(), server.URL, time.Now)
+
+	err := n.sendPushMessage(context.Background(), "テストメッセージ")
+	require.Error(t, err)
+	assert.True(t, IsRetryable(err))
+	assert.Contains(t, err.Error(), "レスポンス解析不可")
+	assert.Contains(t, err.Error(), "<html>Bad Gateway</html>")
+}
+
 func TestSendScheduleNotification(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		body, err := io.ReadAll(r.Body)
@@ -222,6 +504,194 @@ func TestSendScheduleNotification(t *testing.T) {
 		},
 	}
 
-	err := n.SendScheduleNotification(context.Background(), todayEvents, nil)
+	err := n.SendScheduleNotification(context.Background(), newTestReport(todayEvents, nil))
 	assert.NoError(t, err)
 }
+
+func TestSendScheduleNotification_SplitDays(t *testing.T) {
+	var pushReq linePushRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&pushReq))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	jst := time.FixedZone("JST", 9*60*60)
+	n := newTestLINENotifier("test-token", "test-user", server.Client(), server.URL, func() time.Time {
+		return time.Date(2024, 1, 15, 9, 0, 0, 0, jst)
+	})
+	n.SetMessageFormat(MessageFormat{SplitDays: true})
+
+	err := n.SendScheduleNotification(context.Background(), newTestReport([]domain.Event{
+		{Title: "会議", StartTime: time.Date(2024, 1, 15, 10, 0, 0, 0, jst), EndTime: time.Date(2024, 1, 15, 11, 0, 0, 0, jst)},
+	}, nil))
+	require.NoError(t, err)
+
+	require.Len(t, pushReq.Messages, 2)
+	assert.Equal(t, "Google Calendar LINE Notifier\n\n本日 1/15(月) (1件):\n🔸 10:00〜11:00 会議\n", pushReq.Messages[0].Text)
+	assert.Equal(t, "翌日 1/16(火): 予定なし\n", pushReq.Messages[1].Text)
+}
+
+// stubScheduleExporter テスト用のScheduleExporter
+type stubScheduleExporter struct {
+	url    string
+	err    error
+	events []domain.Event
+}
+
+func (s *stubScheduleExporter) Export(_ context.Context, _ time.Time, events []domain.Event) (string, error) {
+	s.events = events
+	return s.url, s.err
+}
+
+func TestSendScheduleNotification_DebugFooter(t *testing.T) {
+	var pushReq linePushRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&pushReq))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := newTestLINENotifier("test-token", "test-user", server.Client(), server.URL, func() time.Time {
+		return time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	})
+	ctx := runid.WithRunID(context.Background(), "run-123")
+
+	require.NoError(t, n.SendScheduleNotification(ctx, newTestReport(nil, nil)))
+	assert.NotContains(t, pushReq.Messages[0].Text, "run-123")
+
+	n.SetDebugFooter(true)
+	require.NoError(t, n.SendScheduleNotification(ctx, newTestReport(nil, nil)))
+	assert.True(t, strings.HasSuffix(pushReq.Messages[0].Text, "\n\nrun: run-123"))
+}
+
+func TestSendScheduleNotification_WithExporter(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	fixedTime := time.Date(2024, 1, 15, 9, 0, 0, 0, jst)
+	todayEvents := []domain.Event{
+		{Title: "会議", StartTime: time.Date(2024, 1, 15, 10, 0, 0, 0, jst), EndTime: time.Date(2024, 1, 15, 11, 0, 0, 0, jst)},
+		{Title: "提出", StartTime: time.Date(2024, 1, 15, 0, 0, 0, 0, jst), IsAllDay: true, IsDeadline: true},
+	}
+
+	tests := []struct {
+		name     string
+		exporter *stubScheduleExporter
+		events   []domain.Event
+		wantLink bool
+	}{
+		{name: "リンクを追加", exporter: &stubScheduleExporter{url: "https://example.com/today.ics"}, events: todayEvents, wantLink: true},
+		{name: "エクスポート失敗時はリンクなしで送信", exporter: &stubScheduleExporter{err: fmt.Errorf("boom")}, events: todayEvents},
+		{name: "予定がない場合はエクスポートしない", exporter: &stubScheduleExporter{url: "https://example.com/today.ics"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var text string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var pushReq linePushRequest
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&pushReq))
+				text = pushReq.Messages[0].Text
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			n := newTestLINENotifier("test-token", "test-user", server.Client(), server.URL, func() time.Time {
+				return fixedTime
+			})
+			n.SetScheduleExporter(tt.exporter)
+
+			err := n.SendScheduleNotification(context.Background(), newTestReport(tt.events, nil))
+			require.NoError(t, err)
+
+			if tt.wantLink {
+				assert.True(t, strings.HasSuffix(text, "📅 本日の予定をカレンダーに追加:\nhttps://example.com/today.ics"))
+				// 締め切りはエクスポート対象外
+				require.Len(t, tt.exporter.events, 1)
+				assert.Equal(t, "会議", tt.exporter.events[0].Title)
+			} else {
+				assert.NotContains(t, text, "カレンダーに追加")
+			}
+		})
+	}
+}
+
+// --- ベンチマーク ---
+
+// newBenchmarkEvents ベンチマーク用に大量の予定を生成するヘルパー
+func newBenchmarkEvents(day int) []domain.Event {
+	jst := time.FixedZone("JST", 9*60*60)
+	events := make([]domain.Event, benchmarkEventCount)
+	for i := range events {
+		start := time.Date(2024, 1, day, 8, 0, 0, 0, jst).Add(time.Duration(i) * 3 * time.Minute)
+		events[i] = domain.Event{
+			Title:     fmt.Sprintf("ミーティング%d", i),
+			StartTime: start,
+			EndTime:   start.Add(30 * time.Minute),
+			IsAllDay:  i%10 == 0,
+			Location:  "渋谷オフィス",
+		}
+	}
+	return events
+}
+
+func BenchmarkBuildScheduleMessage(b *testing.B) {
+	jst := time.FixedZone("JST", 9*60*60)
+	fixedTime := time.Date(2024, 1, 15, 9, 0, 0, 0, jst)
+	n := newTestLINENotifier("token", "user", http.DefaultClient, "", func() time.Time {
+		return fixedTime
+	})
+	todayEvents, tomorrowEvents := newBenchmarkEvents(15), newBenchmarkEvents(16)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = renderMessage(n, reportAt(fixedTime, todayEvents, tomorrowEvents))
+	}
+}
+
+func BenchmarkMarshalPushRequest(b *testing.B) {
+	jst := time.FixedZone("JST", 9*60*60)
+	fixedTime := time.Date(2024, 1, 15, 9, 0, 0, 0, jst)
+	n := newTestLINENotifier("token", "user", http.DefaultClient, "", func() time.Time {
+		return fixedTime
+	})
+	message := renderMessage(n, reportAt(fixedTime, newBenchmarkEvents(15), newBenchmarkEvents(16)))
+	pushRequest := linePushRequest{
+		To:       "user",
+		Messages: []lineMessage{{Type: "text", Text: message}},
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(pushRequest); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestSendTestMessage(t *testing.T) {
+	var received []linePushRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var pushReq linePushRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&pushReq))
+		received = append(received, pushReq)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := newTestLINENotifier("test-token", "test-user", server.Client(), server.URL, time.Now)
+	n.format = MessageFormat{PlainText: true}
+
+	require.NoError(t, n.SendTestMessage(context.Background(), "", "🔸 テスト"))
+	require.NoError(t, n.SendTestMessage(context.Background(), "C-group", "グループへのテスト"))
+	require.Len(t, received, 2)
+	assert.Equal(t, "test-user", received[0].To)
+	// 表示設定に関係なく、指定したテキストをそのまま送る
+	assert.Equal(t, "🔸 テスト", received[0].Messages[0].Text)
+	assert.Equal(t, "C-group", received[1].To)
+
+	n.SetDryRun(true)
+	require.NoError(t, n.SendTestMessage(context.Background(), "", "送信しない"))
+	assert.Len(t, received, 2)
+}
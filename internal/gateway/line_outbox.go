@@ -0,0 +1,183 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
+)
+
+// outboxMaxAge 未送信のメッセージを再送する期限
+// LINEのリトライキーの有効期間（24時間）に合わせ、これを過ぎたものは破棄する
+const outboxMaxAge = 24 * time.Hour
+
+// outboxSchema アウトボックスの保存形式のバージョン
+// outboxEntryのフィールドの意味を変える場合はバージョンを上げ、Migrationsに移行手順を追加する
+var outboxSchema = store.Schema{Name: "アウトボックス", Version: 1}
+
+// retryKeyNamespace メッセージの内容からリトライキー（UUID）を生成する際の名前空間
+var retryKeyNamespace = uuid.MustParse("6f1b2c5e-3d4a-4e8b-9c7f-2a1d0e5b8c34")
+
+// outboxEntry アウトボックスに保存する未送信のメッセージ
+type outboxEntry struct {
+	RetryKey  string        `json:"retryKey"`
+	CreatedAt time.Time     `json:"createdAt"`
+	Messages  []lineMessage `json:"messages"`
+}
+
+// SetOutbox 送信前のメッセージを保存するストアを設定
+// 設定されている場合、送信前にメッセージを保存し、LINE APIが受け付けた後に削除する
+// 一時的なエラーで送信できなかったメッセージは次回の実行時に再送する
+func (n *LINENotifier) SetOutbox(s store.Store) {
+	n.outbox = s
+}
+
+// deliverViaOutbox 未送信のメッセージを再送した後、新しいメッセージをアウトボックス経由で送信
+func (n *LINENotifier) deliverViaOutbox(ctx context.Context, messages []lineMessage) error {
+	pending, err := n.loadOutbox(ctx)
+	if err != nil {
+		// アウトボックスが使えなくても通知自体は直接送信する
+		fmt.Printf("Warning: アウトボックスの読み込みに失敗しました: %v\n", err)
+		return n.pushMessages(ctx, messages...)
+	}
+
+	entry := outboxEntry{
+		RetryKey:  n.retryKey(messages),
+		CreatedAt: n.clock.Now(),
+		Messages:  messages,
+	}
+
+	// 同じ実行の再試行（Lambdaの非同期呼び出しのリトライなど）で同じ内容が未送信のまま残っている場合は、
+	// 残っているものを新しいメッセージとして1回だけ送信する
+	remaining := pending[:0]
+	for _, p := range pending {
+		if p.RetryKey == entry.RetryKey {
+			entry.CreatedAt = p.CreatedAt
+			continue
+		}
+		remaining = append(remaining, p)
+	}
+	pending = n.sweepOutbox(ctx, remaining)
+	if err := n.saveOutbox(ctx, append(pending, entry)); err != nil {
+		fmt.Printf("Warning: アウトボックスへの保存に失敗しました: %v\n", err)
+		return n.pushMessages(ctx, messages...)
+	}
+
+	sendErr := n.pushOutboxEntry(ctx, entry)
+	if sendErr == nil || !IsRetryable(sendErr) {
+		// 送信済み、または再送しても回復しないエラーの場合はアウトボックスから削除
+		if err := n.saveOutbox(ctx, pending); err != nil {
+			fmt.Printf("Warning: アウトボックスの更新に失敗しました: %v\n", err)
+		}
+	}
+	return sendErr
+}
+
+// sweepOutbox 未送信のメッセージを再送し、残ったものを返す
+func (n *LINENotifier) sweepOutbox(ctx context.Context, pending []outboxEntry) []outboxEntry {
+	if len(pending) == 0 {
+		return nil
+	}
+
+	now := n.clock.Now()
+	var remaining []outboxEntry
+	for _, entry := range pending {
+		if now.Sub(entry.CreatedAt) > outboxMaxAge {
+			fmt.Printf("Warning: 再送期限を過ぎた未送信メッセージを破棄しました (作成: %s)\n", entry.CreatedAt.Format(time.RFC3339))
+			continue
+		}
+
+		err := n.pushOutboxEntry(ctx, entry)
+		if err == nil {
+			fmt.Printf("未送信メッセージを再送しました (作成: %s)\n", entry.CreatedAt.Format(time.RFC3339))
+			continue
+		}
+		if !IsRetryable(err) {
+			fmt.Printf("Warning: 未送信メッセージの再送に失敗したため破棄しました: %v\n", err)
+			continue
+		}
+		fmt.Printf("Warning: 未送信メッセージの再送に失敗しました（次回再試行）: %v\n", err)
+		remaining = append(remaining, entry)
+	}
+
+	if err := n.saveOutbox(ctx, remaining); err != nil {
+		fmt.Printf("Warning: アウトボックスの更新に失敗しました: %v\n", err)
+	}
+	return remaining
+}
+
+// retryKey 送信先・通知日・メッセージの内容から決まるリトライキーを生成
+// 同じ内容を再送する場合は同じキーになるため、LINE側で二重送信が防がれる
+func (n *LINENotifier) retryKey(messages []lineMessage) string {
+	data, _ := json.Marshal(messages)
+	name := n.userID + "\n" + n.clock.Now().In(dates.Location()).Format("2006-01-02") + "\n" + string(data)
+	return uuid.NewSHA1(retryKeyNamespace, []byte(name)).String()
+}
+
+// pushOutboxEntry アウトボックスのメッセージをリトライキー付きで送信
+// 同じリトライキーのリクエストが既に受け付けられている場合（409）は送信済みとして扱う
+func (n *LINENotifier) pushOutboxEntry(ctx context.Context, entry outboxEntry) error {
+	err := n.pushMessagesWithRetryKey(ctx, entry.RetryKey, entry.Messages)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		return nil
+	}
+	return err
+}
+
+// outboxKey 送信先ごとのアウトボックスのキー
+func (n *LINENotifier) outboxKey() string {
+	return store.Key(store.NamespaceOutbox, "line:"+n.userID)
+}
+
+// loadOutbox アウトボックスから未送信のメッセージを読み込む
+func (n *LINENotifier) loadOutbox(ctx context.Context) ([]outboxEntry, error) {
+	data, err := n.outbox.Get(ctx, n.outboxKey())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []outboxEntry
+	if _, err := outboxSchema.Decode(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// saveOutbox 未送信のメッセージをアウトボックスに保存（空の場合は削除）
+func (n *LINENotifier) saveOutbox(ctx context.Context, entries []outboxEntry) error {
+	if len(entries) == 0 {
+		return n.outbox.Delete(ctx, n.outboxKey())
+	}
+
+	data, err := outboxSchema.Encode(entries)
+	if err != nil {
+		return err
+	}
+	return n.outbox.Put(ctx, n.outboxKey(), data, outboxMaxAge)
+}
+
+// MigrateOutbox アウトボックスの保存形式を現在のバージョンに移行する（起動時に実行する）
+func (n *LINENotifier) MigrateOutbox(ctx context.Context) error {
+	if n.outbox == nil {
+		return nil
+	}
+	migrated, err := store.MigrateKey(ctx, n.outbox, n.outboxKey(), outboxSchema, outboxMaxAge)
+	if err != nil {
+		return fmt.Errorf("アウトボックスの移行に失敗しました: %v", err)
+	}
+	if migrated {
+		fmt.Printf("アウトボックスをバージョン%dに移行しました\n", outboxSchema.Version)
+	}
+	return nil
+}
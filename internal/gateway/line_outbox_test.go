@@ -0,0 +1,151 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/store"
+)
+
+// outboxTestServer 指定したステータスを返し、受け取ったリトライキーを記録するLINE APIのスタブ
+type outboxTestServer struct {
+	mu        sync.Mutex
+	status    int
+	retryKeys []string
+	texts     []string
+	// delivered リトライキーごとに受け付けた（200を返した）回数
+	delivered map[string]int
+}
+
+func (s *outboxTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pushReq linePushRequest
+	if err := json.NewDecoder(r.Body).Decode(&pushReq); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.retryKeys = append(s.retryKeys, r.Header.Get("X-Line-Retry-Key"))
+	s.texts = append(s.texts, pushReq.Messages[0].Text)
+
+	w.WriteHeader(s.status)
+	if s.status == http.StatusOK {
+		if s.delivered == nil {
+			s.delivered = make(map[string]int)
+		}
+		s.delivered[r.Header.Get("X-Line-Retry-Key")]++
+	} else {
+		_, _ = w.Write([]byte(`{"message":"error"}`))
+	}
+}
+
+func newOutboxTestNotifier(t *testing.T, handler *outboxTestServer, now *time.Time) (*LINENotifier, store.Store) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	clk := clock.Func(func() time.Time { return *now })
+	outbox := store.NewMemoryStore(clk)
+	n := newTestLINENotifier("test-token", "test-user", server.Client(), server.URL, func() time.Time { return *now })
+	n.SetOutbox(outbox)
+	return n, outbox
+}
+
+func TestLINENotifier_Outbox_RetriesOnNextRun(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, jst)
+	handler := &outboxTestServer{status: http.StatusInternalServerError}
+	n, outbox := newOutboxTestNotifier(t, handler, &now)
+
+	todayEvents := []domain.Event{{Title: "定例会議", StartTime: now.Add(3 * time.Hour), EndTime: now.Add(4 * time.Hour)}}
+
+	// 一時的なエラーで送信できなかったメッセージはアウトボックスに残る
+	err := n.SendScheduleNotification(context.Background(), newTestReport(todayEvents, nil))
+	require.Error(t, err)
+	pending, err := n.loadOutbox(context.Background())
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	// 次回の実行で未送信のメッセージを同じリトライキーで再送してから新しいメッセージを送る
+	handler.status = http.StatusOK
+	now = now.Add(time.Hour)
+	err = n.SendScheduleNotification(context.Background(), newTestReport(nil, nil))
+	require.NoError(t, err)
+
+	require.Len(t, handler.retryKeys, 3)
+	assert.NotEmpty(t, handler.retryKeys[0])
+	assert.Equal(t, handler.retryKeys[0], handler.retryKeys[1])
+	assert.NotEqual(t, handler.retryKeys[1], handler.retryKeys[2])
+	assert.Contains(t, handler.texts[1], "定例会議")
+	assert.NotContains(t, handler.texts[2], "定例会議")
+
+	_, err = outbox.Get(context.Background(), n.outboxKey())
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func TestLINENotifier_Outbox_RetriedRunSendsOnce(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, jst)
+	handler := &outboxTestServer{status: http.StatusServiceUnavailable}
+	n, _ := newOutboxTestNotifier(t, handler, &now)
+
+	todayEvents := []domain.Event{{Title: "定例会議", StartTime: now.Add(3 * time.Hour), EndTime: now.Add(4 * time.Hour)}}
+	report := newTestReport(todayEvents, nil)
+
+	err := n.SendScheduleNotification(context.Background(), report)
+	require.Error(t, err)
+
+	// 同じ実行の再試行では、未送信のメッセージと新しいメッセージを二重に送らない
+	handler.status = http.StatusOK
+	now = now.Add(time.Minute)
+	err = n.SendScheduleNotification(context.Background(), report)
+	require.NoError(t, err)
+
+	require.Len(t, handler.retryKeys, 2)
+	assert.Equal(t, handler.retryKeys[0], handler.retryKeys[1])
+	assert.Equal(t, map[string]int{handler.retryKeys[0]: 1}, handler.delivered)
+
+	pending, err := n.loadOutbox(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestLINENotifier_Outbox_DropsNonRetryableFailure(t *testing.T) {
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, time.UTC)
+	handler := &outboxTestServer{status: http.StatusBadRequest}
+	n, _ := newOutboxTestNotifier(t, handler, &now)
+
+	err := n.SendScheduleNotification(context.Background(), newTestReport(nil, nil))
+	require.Error(t, err)
+
+	pending, err := n.loadOutbox(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestLINENotifier_Outbox_Sweep(t *testing.T) {
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, time.UTC)
+	handler := &outboxTestServer{status: http.StatusConflict}
+	n, _ := newOutboxTestNotifier(t, handler, &now)
+
+	pending := []outboxEntry{
+		{RetryKey: "expired", CreatedAt: now.Add(-25 * time.Hour), Messages: []lineMessage{{Type: "text", Text: "古い通知"}}},
+		{RetryKey: "accepted", CreatedAt: now.Add(-time.Hour), Messages: []lineMessage{{Type: "text", Text: "受付済みの通知"}}},
+	}
+
+	// 期限切れは送信せずに破棄し、409（同じリトライキーで受付済み）は送信済みとして扱う
+	remaining := n.sweepOutbox(context.Background(), pending)
+	assert.Empty(t, remaining)
+	assert.Equal(t, []string{"accepted"}, handler.retryKeys)
+}
@@ -0,0 +1,42 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+// SendReminder 予定の開始前にリマインダーを送信
+// ReminderSchedulerが登録したスケジュールから呼び出される。Lambdaの再試行で二重に送らないよう、
+// 予定・開始時刻・送信時刻（remindAt）から決まるリトライキーを付け、受付済み（409）の場合は送信済みとして扱う
+// 1つの予定に複数のリマインダーがある場合も、送信時刻が異なれば別のリマインダーとして送る
+func (n *LINENotifier) SendReminder(ctx context.Context, event domain.Event, remindAt time.Time) error {
+	message := lineMessage{Type: "text", Text: n.format.render(buildReminderMessage(event))}
+	retryKey := uuid.NewSHA1(retryKeyNamespace, []byte(strings.Join([]string{
+		"reminder", n.userID, event.ID, event.StartTime.UTC().Format(time.RFC3339), remindAt.UTC().Format(time.RFC3339),
+	}, "\n"))).String()
+
+	err := n.pushMessagesWithRetryKey(ctx, retryKey, []lineMessage{message})
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		return nil
+	}
+	return err
+}
+
+// buildReminderMessage リマインダーのメッセージを構築
+func buildReminderMessage(event domain.Event) string {
+	var builder strings.Builder
+	builder.WriteString("⏰ まもなく予定の時刻です\n")
+	builder.WriteString(fmt.Sprintf("🔸 %s〜 %s\n", event.StartTime.In(dates.Location()).Format("15:04"), event.Title))
+	appendEventDetails(&builder, event)
+	return strings.TrimSuffix(builder.String(), "\n")
+}
@@ -0,0 +1,65 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+func TestSendReminder(t *testing.T) {
+	handler := &outboxTestServer{status: http.StatusOK}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	now := time.Date(2024, 1, 15, 4, 30, 0, 0, time.UTC)
+	n := newTestLINENotifier("test-token", "test-user", server.Client(), server.URL, func() time.Time { return now })
+	event := domain.Event{ID: "review", Title: "設計レビュー", StartTime: time.Date(2024, 1, 15, 5, 0, 0, 0, time.UTC), Location: "会議室A"}
+	remindAt := event.StartTime.Add(-30 * time.Minute)
+
+	require.NoError(t, n.SendReminder(context.Background(), event, remindAt))
+	require.Len(t, handler.texts, 1)
+	assert.Equal(t, "⏰ まもなく予定の時刻です\n🔸 14:00〜 設計レビュー\n   📍 会議室A", handler.texts[0])
+
+	// 再試行で同じリマインダーを送る場合は同じリトライキーを使い、受付済み（409）は送信済みとして扱う
+	handler.status = http.StatusConflict
+	require.NoError(t, n.SendReminder(context.Background(), event, remindAt))
+	require.Len(t, handler.retryKeys, 2)
+	assert.NotEmpty(t, handler.retryKeys[0])
+	assert.Equal(t, handler.retryKeys[0], handler.retryKeys[1])
+}
+
+func TestSendReminder_PlainText(t *testing.T) {
+	handler := &outboxTestServer{status: http.StatusOK}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	n := newTestLINENotifier("test-token", "test-user", server.Client(), server.URL, time.Now)
+	n.SetMessageFormat(MessageFormat{PlainText: true})
+	event := domain.Event{ID: "review", Title: "設計レビュー", StartTime: time.Date(2024, 1, 15, 5, 0, 0, 0, time.UTC)}
+
+	require.NoError(t, n.SendReminder(context.Background(), event, event.StartTime.Add(-10*time.Minute)))
+	assert.Equal(t, "まもなく予定の時刻です\n- 14:00〜 設計レビュー", handler.texts[0])
+}
+
+func TestSendReminder_MultipleLeads(t *testing.T) {
+	handler := &outboxTestServer{status: http.StatusOK}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	n := newTestLINENotifier("test-token", "test-user", server.Client(), server.URL, time.Now)
+	event := domain.Event{ID: "review", Title: "設計レビュー", StartTime: time.Date(2024, 1, 15, 5, 0, 0, 0, time.UTC)}
+
+	// 同じ予定の30分前と10分前のリマインダーは、別々のリトライキーで両方とも送る
+	require.NoError(t, n.SendReminder(context.Background(), event, event.StartTime.Add(-30*time.Minute)))
+	require.NoError(t, n.SendReminder(context.Background(), event, event.StartTime.Add(-10*time.Minute)))
+	require.Len(t, handler.retryKeys, 2)
+	assert.NotEqual(t, handler.retryKeys[0], handler.retryKeys[1])
+	assert.Len(t, handler.delivered, 2)
+}
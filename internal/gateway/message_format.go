@@ -0,0 +1,205 @@
+package gateway
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/category"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/sanitize"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/textutil"
+)
+
+// AllDayPlacement 終日予定を1日の予定一覧のどこに表示するか
+type AllDayPlacement string
+
+const (
+	// AllDayPlacementInline 取得した順序のまま時間指定の予定と混在させて表示する（デフォルト）
+	AllDayPlacementInline AllDayPlacement = "inline"
+	// AllDayPlacementFirst 終日予定を時間指定の予定より先に表示する
+	AllDayPlacementFirst AllDayPlacement = "first"
+	// AllDayPlacementLast 終日予定を時間指定の予定の後に表示する
+	AllDayPlacementLast AllDayPlacement = "last"
+	// AllDayPlacementSeparate 終日予定を「終日」ブロックとして分けて表示する
+	AllDayPlacementSeparate AllDayPlacement = "separate"
+)
+
+// ParseAllDayPlacement 文字列からAllDayPlacementを解析する
+// 空文字の場合はAllDayPlacementInlineを返す
+func ParseAllDayPlacement(s string) (AllDayPlacement, error) {
+	switch p := AllDayPlacement(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return AllDayPlacementInline, nil
+	case AllDayPlacementInline, AllDayPlacementFirst, AllDayPlacementLast, AllDayPlacementSeparate:
+		return p, nil
+	default:
+		return "", fmt.Errorf("終日予定の表示位置が不正です: %q (inline, first, last, separate のいずれか)", s)
+	}
+}
+
+// MessageFormat 通知メッセージの表示オプション
+// ゼロ値は従来どおりの表示になる
+type MessageFormat struct {
+	AllDayPlacement AllDayPlacement
+	// MaxEventsPerDay 1日に表示する予定の上限（0以下の場合は無制限）
+	// 超過分は「…他N件」としてまとめ、Googleカレンダーの日表示へのリンクを添える
+	MaxEventsPerDay int
+	// MaxTitleLength 予定のタイトルを表示する最大の表示幅（全角文字は2、半角文字は1として数える。0以下の場合は無制限）
+	MaxTitleLength int
+	// MaxLocationLength 予定の場所を表示する最大の表示幅（数え方はMaxTitleLengthと同じ。0以下の場合は無制限）
+	MaxLocationLength int
+	// Anniversaries 設定されている場合、近づいた記念日までの日数を本日の予定の先頭に表示する
+	Anniversaries *AnniversaryCountdown
+	// SplitDays 本日と翌日の予定を別々のメッセージとして送信する
+	SplitDays bool
+	// Sections セクションの表示順と表示するセクション（ゼロ値は既定の順序ですべて表示する）
+	Sections SectionLayout
+	// PlainText 絵文字や装飾記号を使わないプレーンテキストで表示する
+	PlainText bool
+	// Classifier 設定されている場合、カテゴリごとの合計時間を表示する
+	Classifier *category.Classifier
+	// CelebrateMeetingFree 時間指定の予定（会議）がない日を「会議のない一日」として表示する
+	CelebrateMeetingFree bool
+}
+
+// plainTextReplacer 装飾用の絵文字を読み上げやすい表記に置き換える
+var plainTextReplacer = strings.NewReplacer(
+	"🔸 ", "- ",
+	"📍 ", "場所: ",
+	"⏰ ", "",
+	"🔗 ", "リンク: ",
+	"📋 ", "",
+	"📆 ", "",
+	"⏱ ", "",
+	"📅 ", "",
+	"👥 ", "",
+	"👤 ", "参加者: ",
+	"👨‍👩‍👧 ", "",
+	"🎉 ", "",
+	"🎂 ", "",
+)
+
+// render 表示オプションに従ってメッセージ本文を仕上げる
+func (f MessageFormat) render(text string) string {
+	if !f.PlainText {
+		return text
+	}
+	lines := strings.Split(sanitize.StripEmoji(plainTextReplacer.Replace(text)), "\n")
+	for i, line := range lines {
+		// 絵文字を除去した跡に残る連続した空白を詰める（行頭のインデントは維持する）
+		body := strings.TrimLeft(line, " ")
+		indent := line[:len(line)-len(body)]
+		lines[i] = indent + strings.Join(strings.Fields(body), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// shorten 表示用にタイトルと場所を上限の表示幅に切り詰めた予定を返す
+// URLを含む場所はリンクの抽出に使うため切り詰めない
+func (f MessageFormat) shorten(event domain.Event) domain.Event {
+	event.Title = textutil.TruncateWidth(event.Title, f.MaxTitleLength)
+	if len(sanitize.ExtractURLs(event.Location)) == 0 {
+		event.Location = textutil.TruncateWidth(event.Location, f.MaxLocationLength)
+	}
+	return event
+}
+
+// arrangeAllDay 表示位置の設定に従って予定を並べ替える
+// AllDayPlacementSeparateの場合は終日予定を allDay として分けて返す
+func (f MessageFormat) arrangeAllDay(events []domain.Event) (listed, allDay []domain.Event) {
+	if f.AllDayPlacement == "" || f.AllDayPlacement == AllDayPlacementInline {
+		return events, nil
+	}
+
+	var timed []domain.Event
+	for _, event := range events {
+		if event.IsAllDay {
+			allDay = append(allDay, event)
+		} else {
+			timed = append(timed, event)
+		}
+	}
+
+	switch f.AllDayPlacement {
+	case AllDayPlacementFirst:
+		return append(allDay, timed...), nil
+	case AllDayPlacementLast:
+		return append(timed, allDay...), nil
+	default:
+		return timed, allDay
+	}
+}
+
+// collapse 表示上限に従って予定を切り詰め、省略した件数を返す
+// Importanceの高い予定を優先して残し（同点の場合は表示順が先の予定）、表示順は維持する
+func (f MessageFormat) collapse(listed, allDay []domain.Event) ([]domain.Event, []domain.Event, int) {
+	total := len(listed) + len(allDay)
+	if f.MaxEventsPerDay <= 0 || total <= f.MaxEventsPerDay {
+		return listed, allDay, 0
+	}
+
+	all := make([]domain.Event, 0, total)
+	all = append(append(all, listed...), allDay...)
+	order := make([]int, total)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return all[order[a]].Importance > all[order[b]].Importance
+	})
+	keep := make([]bool, total)
+	for _, i := range order[:f.MaxEventsPerDay] {
+		keep[i] = true
+	}
+
+	var keptListed, keptAllDay []domain.Event
+	for i, event := range all {
+		switch {
+		case !keep[i]:
+		case i < len(listed):
+			keptListed = append(keptListed, event)
+		default:
+			keptAllDay = append(keptAllDay, event)
+		}
+	}
+	return keptListed, keptAllDay, total - f.MaxEventsPerDay
+}
+
+// googleCalendarDayURL Googleカレンダーで指定日の予定一覧を開くURL
+func googleCalendarDayURL(day time.Time) string {
+	return fmt.Sprintf("https://calendar.google.com/calendar/r/day/%d/%d/%d", day.Year(), int(day.Month()), day.Day())
+}
+
+// categorySummary カテゴリごとの合計時間を「⏱ 内訳: 会議 1時間30分 / その他 30分」形式で返す
+// Classifierが未設定、または集計対象の予定がない場合は空文字を返す
+func (f MessageFormat) categorySummary(events []domain.Event) string {
+	if f.Classifier == nil {
+		return ""
+	}
+	totals := f.Classifier.Totals(events)
+	if len(totals) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(totals))
+	for _, total := range totals {
+		parts = append(parts, fmt.Sprintf("%s %s", total.Name, formatDuration(total.Duration)))
+	}
+	return "⏱ 内訳: " + strings.Join(parts, " / ")
+}
+
+// formatDuration 所要時間を「1時間30分」形式に整形する
+func formatDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%d分", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%d時間", hours)
+	default:
+		return fmt.Sprintf("%d時間%d分", hours, minutes)
+	}
+}
@@ -0,0 +1,120 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+func TestParseAllDayPlacement(t *testing.T) {
+	tests := []struct {
+		input string
+		want  AllDayPlacement
+	}{
+		{input: "", want: AllDayPlacementInline},
+		{input: "inline", want: AllDayPlacementInline},
+		{input: "First", want: AllDayPlacementFirst},
+		{input: " last ", want: AllDayPlacementLast},
+		{input: "separate", want: AllDayPlacementSeparate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseAllDayPlacement(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseAllDayPlacement_Invalid(t *testing.T) {
+	_, err := ParseAllDayPlacement("top")
+	assert.Error(t, err)
+}
+
+func TestMessageFormat_Collapse(t *testing.T) {
+	events := func(titles ...string) []domain.Event {
+		var result []domain.Event
+		for _, title := range titles {
+			result = append(result, domain.Event{Title: title})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name       string
+		max        int
+		listed     []domain.Event
+		allDay     []domain.Event
+		wantListed int
+		wantAllDay int
+		wantHidden int
+	}{
+		{name: "上限なし", max: 0, listed: events("a", "b", "c"), wantListed: 3},
+		{name: "上限以内", max: 3, listed: events("a", "b"), allDay: events("c"), wantListed: 2, wantAllDay: 1},
+		{name: "時間指定で上限到達", max: 2, listed: events("a", "b", "c"), allDay: events("d"), wantListed: 2, wantHidden: 2},
+		{name: "終日ブロックに残り枠", max: 3, listed: events("a", "b"), allDay: events("c", "d"), wantListed: 2, wantAllDay: 1, wantHidden: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listed, allDay, hidden := MessageFormat{MaxEventsPerDay: tt.max}.collapse(tt.listed, tt.allDay)
+			assert.Len(t, listed, tt.wantListed)
+			assert.Len(t, allDay, tt.wantAllDay)
+			assert.Equal(t, tt.wantHidden, hidden)
+		})
+	}
+}
+
+func TestGoogleCalendarDayURL(t *testing.T) {
+	day := time.Date(2024, 1, 5, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+	assert.Equal(t, "https://calendar.google.com/calendar/r/day/2024/1/5", googleCalendarDayURL(day))
+}
+
+func TestMessageFormat_CollapseKeepsImportantEvents(t *testing.T) {
+	listed := []domain.Event{
+		{Title: "a"},
+		{Title: "b", Importance: 2},
+		{Title: "c"},
+		{Title: "d", Importance: 5},
+	}
+
+	kept, _, hidden := MessageFormat{MaxEventsPerDay: 2}.collapse(listed, nil)
+
+	// 重要度の高い予定が残り、表示順は元の順序を維持する
+	require.Len(t, kept, 2)
+	assert.Equal(t, "b", kept[0].Title)
+	assert.Equal(t, "d", kept[1].Title)
+	assert.Equal(t, 2, hidden)
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "45分", formatDuration(45*time.Minute))
+	assert.Equal(t, "2時間", formatDuration(2*time.Hour))
+	assert.Equal(t, "1時間30分", formatDuration(90*time.Minute))
+}
+
+func TestMessageFormat_Shorten(t *testing.T) {
+	// 上限は表示幅で数える（全角文字は2、半角文字と省略記号は1）
+	f := MessageFormat{MaxTitleLength: 11, MaxLocationLength: 7}
+	event := f.shorten(domain.Event{Title: "プロジェクト定例会議", Location: "渋谷オフィス 会議室A"})
+	assert.Equal(t, "プロジェク…", event.Title)
+	assert.Equal(t, "渋谷オ…", event.Location)
+
+	// 文字数（11文字）では上限内でも、表示幅（14）が上限を超えれば切り詰める
+	event = f.shorten(domain.Event{Title: "Weekly定例MTG"})
+	assert.Equal(t, "Weekly定例…", event.Title)
+
+	// URLを含む場所は切り詰めない
+	event = f.shorten(domain.Event{Title: "面談", Location: "https://zoom.us/j/123"})
+	assert.Equal(t, "面談", event.Title)
+	assert.Equal(t, "https://zoom.us/j/123", event.Location)
+
+	// 上限未設定の場合はそのまま
+	event = MessageFormat{}.shorten(domain.Event{Title: "プロジェクト定例会議"})
+	assert.Equal(t, "プロジェクト定例会議", event.Title)
+}
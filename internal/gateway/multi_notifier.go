@@ -0,0 +1,38 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+// ScheduleNotifier 予定を通知する出力先
+type ScheduleNotifier interface {
+	SendScheduleNotification(ctx context.Context, report domain.ScheduleReport) error
+}
+
+// MultiNotifier 主となる通知先に加えて、補助的な出力先にも予定を送る
+// 主の通知先のエラーのみを返し、補助的な出力先の失敗はログに残して続行する
+type MultiNotifier struct {
+	primary     ScheduleNotifier
+	secondaries []ScheduleNotifier
+}
+
+// NewMultiNotifier 主の通知先と補助的な出力先を指定してMultiNotifierを作成
+func NewMultiNotifier(primary ScheduleNotifier, secondaries ...ScheduleNotifier) *MultiNotifier {
+	return &MultiNotifier{primary: primary, secondaries: secondaries}
+}
+
+// SendScheduleNotification 主の通知先に送信した後、補助的な出力先に送信する
+func (m *MultiNotifier) SendScheduleNotification(ctx context.Context, report domain.ScheduleReport) error {
+	if err := m.primary.SendScheduleNotification(ctx, report); err != nil {
+		return err
+	}
+	for _, secondary := range m.secondaries {
+		if err := secondary.SendScheduleNotification(ctx, report); err != nil {
+			fmt.Printf("Warning: 補助的な出力先への送信に失敗しました: %v\n", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/usecase"
+)
+
+const (
+	// notifiedPropertyKey 通知済みの日付を記録するイベントの非公開拡張プロパティのキー
+	notifiedPropertyKey = "lineNotifierNotifiedOn"
+	// notifiedByPropertyKey 通知した通知インスタンスを記録するイベントの非公開拡張プロパティのキー
+	notifiedByPropertyKey = "lineNotifierNotifiedBy"
+)
+
+// NotificationInstanceID 通知先から通知インスタンスの識別子を作成
+// カレンダーには通知先のIDをそのまま書き込まないよう、ハッシュの先頭を使う
+func NotificationInstanceID(lineUserID string) string {
+	sum := sha256.Sum256([]byte(lineUserID))
+	return hex.EncodeToString(sum[:6])
+}
+
+// EventsPatcher はカレンダーのイベントへの書き込みを抽象化する
+type EventsPatcher interface {
+	SetPrivateProperties(calendarID, eventID string, properties map[string]string) error
+}
+
+// SetPrivateProperties イベントの非公開拡張プロパティを設定する（指定していないプロパティは変更しない）
+func (p *googleEventsProvider) SetPrivateProperties(calendarID, eventID string, properties map[string]string) error {
+	patch := &calendar.Event{
+		ExtendedProperties: &calendar.EventExtendedProperties{Private: properties},
+	}
+	_, err := p.service.Events.Patch(calendarID, eventID, patch).Do()
+	return err
+}
+
+// NewGoogleEventsPatcher イベントへの書き込み権限でCalendar APIクライアントを作成
+// カレンダーをサービスアカウントに「予定の変更」権限で共有しておく必要がある
+func NewGoogleEventsPatcher(credentialsJSON []byte, endpoint string, transport http.RoundTripper) (EventsPatcher, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return newGoogleEventsProvider(credentialsJSON, endpoint, transport, calendar.CalendarEventsScope)
+}
+
+// notifiedStatus イベントに記録された通知済みの日付と通知インスタンスを返す
+// 記録がない・日付が不正な場合は日付をゼロ値とする
+func notifiedStatus(event *calendar.Event, loc *time.Location) (time.Time, string) {
+	if event.ExtendedProperties == nil {
+		return time.Time{}, ""
+	}
+	value, ok := event.ExtendedProperties.Private[notifiedPropertyKey]
+	if !ok {
+		return time.Time{}, ""
+	}
+	date, err := dates.ParseDate(value, loc)
+	if err != nil {
+		return time.Time{}, ""
+	}
+	return date, event.ExtendedProperties.Private[notifiedByPropertyKey]
+}
+
+// SkipNotifiedCalendarRepository 他の通知インスタンスが本日すでに通知済みとして記録した予定を除外するデコレーター
+// 同じカレンダーを複数の通知インスタンスで共有する場合に、同じ予定を重複して通知しないようにする
+// 自身が記録した予定は除外しないため、同じ日に再実行しても同じ内容を通知する
+type SkipNotifiedCalendarRepository struct {
+	next     usecase.CalendarRepository
+	instance string
+	clock    clock.Clock
+}
+
+// NewSkipNotifiedCalendarRepository nextから取得した予定のうち、instance以外が本日通知済みのものを除外するリポジトリを作成
+func NewSkipNotifiedCalendarRepository(next usecase.CalendarRepository, instance string, clk clock.Clock) *SkipNotifiedCalendarRepository {
+	return &SkipNotifiedCalendarRepository{next: next, instance: instance, clock: clk}
+}
+
+// GetEvents 指定日の予定のうち、他の通知インスタンスが本日まだ通知していないものを返す
+// 通知インスタンスの記録がない予定は、他の通知インスタンスが通知したものとして扱う
+func (r *SkipNotifiedCalendarRepository) GetEvents(ctx context.Context, targetDate time.Time) ([]domain.Event, error) {
+	events, err := r.next.GetEvents(ctx, targetDate)
+	if err != nil {
+		return nil, err
+	}
+
+	today := dates.Today(r.clock.Now(), dates.Location())
+	pending := make([]domain.Event, 0, len(events))
+	for _, event := range events {
+		if event.NotifiedOn.Equal(today) && event.NotifiedBy != r.instance {
+			continue
+		}
+		pending = append(pending, event)
+	}
+	return pending, nil
+}
+
+// NotificationStatusWriter 通知した予定に通知済みの日付と通知インスタンスを書き込む
+// 通知の後に補助的な出力先として実行し、他の通知インスタンスが同じ予定を重複して通知しないようにする
+type NotificationStatusWriter struct {
+	patcher     EventsPatcher
+	calendarIDs map[string]bool
+	instance    string
+	clock       clock.Clock
+}
+
+// NewNotificationStatusWriter 書き込み対象のカレンダーを指定してNotificationStatusWriterを作成
+// 各予定は登録されているカレンダーに書き込み、他のカレンダー（家族のカレンダーなど）や他の取得元の予定には書き込まない
+func NewNotificationStatusWriter(patcher EventsPatcher, calendarIDs []string, instance string, clk clock.Clock) *NotificationStatusWriter {
+	targets := make(map[string]bool, len(calendarIDs))
+	for _, calendarID := range calendarIDs {
+		targets[calendarID] = true
+	}
+	return &NotificationStatusWriter{patcher: patcher, calendarIDs: targets, instance: instance, clock: clk}
+}
+
+// SendScheduleNotification 本日と翌日の予定に本日の日付と通知インスタンスを通知済みとして書き込む
+// 本日すでに自身が通知済みとして記録した予定は書き込み直さない
+func (w *NotificationStatusWriter) SendScheduleNotification(_ context.Context, report domain.ScheduleReport) error {
+	today := dates.Today(w.clock.Now(), dates.Location())
+	properties := map[string]string{
+		notifiedPropertyKey:   today.Format("2006-01-02"),
+		notifiedByPropertyKey: w.instance,
+	}
+
+	var failed int
+	var firstErr error
+	for _, day := range report.Days() {
+		for _, event := range day.Events {
+			if event.ID == "" || !w.calendarIDs[event.CalendarID] {
+				continue
+			}
+			if event.NotifiedOn.Equal(today) && event.NotifiedBy == w.instance {
+				continue
+			}
+			if err := w.patcher.SetPrivateProperties(event.CalendarID, event.ID, properties); err != nil {
+				fmt.Printf("Warning: 予定 %s への通知状況の書き込みに失敗しました: %v\n", event.ID, err)
+				if firstErr == nil {
+					firstErr = err
+				}
+				failed++
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("通知状況%d件の書き込みに失敗しました: %v", failed, firstErr)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/fakes"
+)
+
+type fakeEventsPatcher struct {
+	patched []string
+	err     error
+}
+
+func (f *fakeEventsPatcher) SetPrivateProperties(calendarID, eventID string, properties map[string]string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.patched = append(f.patched, calendarID+"/"+eventID+" "+properties[notifiedPropertyKey]+" by "+properties[notifiedByPropertyKey])
+	return nil
+}
+
+func TestConvertToEvent_NotifiedStatus(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	repo := NewGoogleCalendarRepositoryWithProvider(nil, []string{"primary"}, jst)
+
+	event := &calendar.Event{
+		Id:                 "1",
+		Start:              &calendar.EventDateTime{DateTime: "2024-01-15T10:00:00+09:00"},
+		End:                &calendar.EventDateTime{DateTime: "2024-01-15T11:00:00+09:00"},
+		ExtendedProperties: &calendar.EventExtendedProperties{Private: map[string]string{notifiedPropertyKey: "2024-01-15", notifiedByPropertyKey: "instance-a"}},
+	}
+
+	result, err := repo.convertToEvent("primary", event)
+	require.NoError(t, err)
+	assert.Equal(t, "primary", result.CalendarID)
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, jst), result.NotifiedOn)
+	assert.Equal(t, "instance-a", result.NotifiedBy)
+
+	event.ExtendedProperties.Private[notifiedPropertyKey] = "invalid"
+	result, err = repo.convertToEvent("primary", event)
+	require.NoError(t, err)
+	assert.True(t, result.NotifiedOn.IsZero())
+	assert.Empty(t, result.NotifiedBy)
+}
+
+func TestSkipNotifiedCalendarRepository_GetEvents(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, jst)
+	next := fakes.CalendarRepository{Events: []domain.Event{
+		{ID: "new", Title: "未通知"},
+		{ID: "other", Title: "他のインスタンスが本日通知済み", NotifiedOn: time.Date(2024, 1, 15, 0, 0, 0, 0, jst), NotifiedBy: "instance-b"},
+		{ID: "legacy", Title: "インスタンスの記録なしで本日通知済み", NotifiedOn: time.Date(2024, 1, 15, 0, 0, 0, 0, jst)},
+		{ID: "own", Title: "自身が本日通知済み", NotifiedOn: time.Date(2024, 1, 15, 0, 0, 0, 0, jst), NotifiedBy: "instance-a"},
+		{ID: "yesterday", Title: "前日に通知", NotifiedOn: time.Date(2024, 1, 14, 0, 0, 0, 0, jst), NotifiedBy: "instance-b"},
+	}}
+	repo := NewSkipNotifiedCalendarRepository(next, "instance-a", clock.Fixed(now))
+
+	// 同じ日に再実行しても、自身が通知した予定は除外しない
+	events, err := repo.GetEvents(context.Background(), now)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	assert.Equal(t, []string{"new", "own", "yesterday"}, ids)
+}
+
+func TestNotificationStatusWriter_SendScheduleNotification(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, jst)
+	patcher := &fakeEventsPatcher{}
+	writer := NewNotificationStatusWriter(patcher, []string{"primary", "work@example.com"}, "instance-a", clock.Fixed(now))
+
+	report := domain.NewScheduleReport(now, now, now.AddDate(0, 0, 1),
+		[]domain.Event{
+			{ID: "a", CalendarID: "primary"},
+			{ID: "own", CalendarID: "primary", NotifiedOn: time.Date(2024, 1, 15, 0, 0, 0, 0, jst), NotifiedBy: "instance-a"},
+			{ID: "stale", CalendarID: "primary", NotifiedOn: time.Date(2024, 1, 14, 0, 0, 0, 0, jst), NotifiedBy: "instance-a"},
+			{ID: "c", CalendarID: "work@example.com"},
+			{ID: "family", CalendarID: "family@example.com", IsFamily: true},
+			{ID: "https://github.com/example/app/issues/1", IsDeadline: true},
+		},
+		[]domain.Event{{ID: "b", CalendarID: "primary"}},
+	)
+
+	require.NoError(t, writer.SendScheduleNotification(context.Background(), report))
+	// 本日すでに自身が記録した予定には書き込み直さない
+	assert.Equal(t, []string{
+		"primary/a 2024-01-15 by instance-a",
+		"primary/stale 2024-01-15 by instance-a",
+		"work@example.com/c 2024-01-15 by instance-a",
+		"primary/b 2024-01-15 by instance-a",
+	}, patcher.patched)
+}
+
+func TestNotificationStatusWriter_PatchError(t *testing.T) {
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, time.UTC)
+	writer := NewNotificationStatusWriter(&fakeEventsPatcher{err: errors.New("forbidden")}, []string{"primary"}, "instance-a", clock.Fixed(now))
+
+	report := domain.NewScheduleReport(now, now, now.AddDate(0, 0, 1), []domain.Event{{ID: "a", CalendarID: "primary"}}, nil)
+	err := writer.SendScheduleNotification(context.Background(), report)
+	assert.ErrorContains(t, err, "通知状況1件の書き込みに失敗しました")
+}
+
+func TestNotificationInstanceID(t *testing.T) {
+	id := NotificationInstanceID("U1234567890")
+	assert.Len(t, id, 12)
+	assert.NotContains(t, id, "U1234567890")
+	assert.Equal(t, id, NotificationInstanceID("U1234567890"))
+	assert.NotEqual(t, id, NotificationInstanceID("C1234567890"))
+}
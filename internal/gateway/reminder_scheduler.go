@@ -0,0 +1,215 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/payload"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/textutil"
+)
+
+// reminderSchedulePrefix 登録するリマインダーのスケジュール名の接頭辞
+// この接頭辞のスケジュールのみを本ツールが管理する（古いものの削除対象にする）
+const reminderSchedulePrefix = "reminder-"
+
+// scheduleDescriptionMaxLength スケジュールの説明の最大文字数
+const scheduleDescriptionMaxLength = 512
+
+// SchedulerAPI は EventBridge Scheduler のスケジュール操作を抽象化する
+type SchedulerAPI interface {
+	CreateSchedule(ctx context.Context, params *scheduler.CreateScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.CreateScheduleOutput, error)
+	DeleteSchedule(ctx context.Context, params *scheduler.DeleteScheduleInput, optFns ...func(*scheduler.Options)) (*scheduler.DeleteScheduleOutput, error)
+	ListSchedules(ctx context.Context, params *scheduler.ListSchedulesInput, optFns ...func(*scheduler.Options)) (*scheduler.ListSchedulesOutput, error)
+}
+
+// ReminderScheduler 予定ごとのリマインダー時刻にEventBridge Schedulerの1回限りのスケジュールを登録する
+// スケジュールはreminderを指定したペイロードでターゲット（この関数）を呼び出し、リマインダーをLINEで送信させる
+// 朝の通知の後に補助的な出力先として実行し、不要になったスケジュール（削除・変更された予定の分）は削除する
+// 実行済みのスケジュールはSchedulerが自動で削除する
+type ReminderScheduler struct {
+	client      SchedulerAPI
+	group       string
+	targetARN   string
+	roleARN     string
+	defaultLead time.Duration
+	clock       clock.Clock
+	dedup       *EventDedup
+}
+
+// NewReminderScheduler スケジュールグループと呼び出すターゲットを指定してReminderSchedulerを作成
+// 予定にリマインダーが設定されていない場合はdefaultLead前に通知する（0以下の場合は登録しない）
+func NewReminderScheduler(client SchedulerAPI, group, targetARN, roleARN string, defaultLead time.Duration, clk clock.Clock) *ReminderScheduler {
+	return &ReminderScheduler{
+		client:      client,
+		group:       group,
+		targetARN:   targetARN,
+		roleARN:     roleARN,
+		defaultLead: defaultLead,
+		clock:       clk,
+	}
+}
+
+// SetDedup 設定されている場合、他の通知モードで通知済みの予定は重複抑止の時間内のリマインダーを登録しない
+func (s *ReminderScheduler) SetDedup(dedup *EventDedup) {
+	s.dedup = dedup
+}
+
+// SendScheduleNotification 本日と翌日の予定のリマインダーを登録し、不要になったものを削除する
+func (s *ReminderScheduler) SendScheduleNotification(ctx context.Context, report domain.ScheduleReport) error {
+	desired := s.reminders(ctx, report)
+	existing, err := s.listSchedules(ctx)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	var firstErr error
+	fail := func(err error) {
+		fmt.Printf("Warning: %v\n", err)
+		if firstErr == nil {
+			firstErr = err
+		}
+		failed++
+	}
+
+	for _, name := range sortedKeys(existing) {
+		if _, ok := desired[name]; ok {
+			continue
+		}
+		_, err := s.client.DeleteSchedule(ctx, &scheduler.DeleteScheduleInput{
+			Name:      aws.String(name),
+			GroupName: aws.String(s.group),
+		})
+		if err != nil {
+			fail(fmt.Errorf("リマインダー %s の削除に失敗しました: %v", name, err))
+		}
+	}
+
+	for _, name := range sortedKeys(desired) {
+		if existing[name] {
+			continue
+		}
+		if err := s.createSchedule(ctx, name, desired[name]); err != nil {
+			fail(err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("リマインダー%d件の更新に失敗しました: %v", failed, firstErr)
+	}
+	return nil
+}
+
+// reminders 登録すべきリマインダーをスケジュール名ごとに返す
+// 終日の予定・締め切り・家族の予定・通知時刻を過ぎたリマインダー・通知済みで重複抑止の時間内のリマインダーは対象外
+func (s *ReminderScheduler) reminders(ctx context.Context, report domain.ScheduleReport) map[string]payload.Reminder {
+	now := s.clock.Now()
+	result := map[string]payload.Reminder{}
+	for _, day := range report.Days() {
+		for _, event := range day.Events {
+			if event.IsAllDay || event.IsDeadline || event.IsFamily {
+				continue
+			}
+			leads := event.Reminders
+			if len(leads) == 0 && s.defaultLead > 0 {
+				leads = []time.Duration{s.defaultLead}
+			}
+			for _, lead := range leads {
+				remindAt := event.StartTime.Add(-lead)
+				if !remindAt.After(now) {
+					continue
+				}
+				if s.dedup != nil && s.dedup.Covered(ctx, event, remindAt) {
+					continue
+				}
+				result[reminderScheduleName(event, remindAt)] = payload.Reminder{
+					EventID:  event.ID,
+					Title:    event.Title,
+					Start:    event.StartTime,
+					Location: event.Location,
+					RemindAt: remindAt,
+				}
+			}
+		}
+	}
+	return result
+}
+
+// listSchedules 登録済みのリマインダーのスケジュール名を返す
+func (s *ReminderScheduler) listSchedules(ctx context.Context) (map[string]bool, error) {
+	names := map[string]bool{}
+	input := &scheduler.ListSchedulesInput{
+		GroupName:  aws.String(s.group),
+		NamePrefix: aws.String(reminderSchedulePrefix),
+	}
+	for {
+		output, err := s.client.ListSchedules(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("登録済みのリマインダーの取得に失敗しました: %v", err)
+		}
+		for _, schedule := range output.Schedules {
+			names[aws.ToString(schedule.Name)] = true
+		}
+		if aws.ToString(output.NextToken) == "" {
+			return names, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// createSchedule リマインダーの時刻に1回だけ実行されるスケジュールを登録
+func (s *ReminderScheduler) createSchedule(ctx context.Context, name string, reminder payload.Reminder) error {
+	body, err := json.Marshal(payload.Event{Version: payload.SchemaVersion, Reminder: &reminder})
+	if err != nil {
+		return fmt.Errorf("リマインダーのJSON変換に失敗しました: %v", err)
+	}
+
+	loc := dates.Location()
+	_, err = s.client.CreateSchedule(ctx, &scheduler.CreateScheduleInput{
+		Name:                       aws.String(name),
+		GroupName:                  aws.String(s.group),
+		ScheduleExpression:         aws.String(fmt.Sprintf("at(%s)", reminder.RemindAt.In(loc).Format("2006-01-02T15:04:05"))),
+		ScheduleExpressionTimezone: aws.String(loc.String()),
+		FlexibleTimeWindow:         &types.FlexibleTimeWindow{Mode: types.FlexibleTimeWindowModeOff},
+		ActionAfterCompletion:      types.ActionAfterCompletionDelete,
+		Description:                aws.String(textutil.Truncate(reminder.Title, scheduleDescriptionMaxLength)),
+		Target: &types.Target{
+			Arn:     aws.String(s.targetARN),
+			RoleArn: aws.String(s.roleARN),
+			Input:   aws.String(string(body)),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("リマインダー %s の登録に失敗しました: %v", name, err)
+	}
+	return nil
+}
+
+// reminderScheduleName 予定とリマインダー時刻から決まるスケジュール名
+// 予定の時刻やリマインダーが変わると名前も変わるため、古いスケジュールは削除対象になる
+func reminderScheduleName(event domain.Event, remindAt time.Time) string {
+	sum := sha1.Sum([]byte(event.ID + "|" + event.StartTime.UTC().Format(time.RFC3339) + "|" + remindAt.UTC().Format(time.RFC3339)))
+	return reminderSchedulePrefix + hex.EncodeToString(sum[:])[:20]
+}
+
+// sortedKeys マップのキーを昇順で返す
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/payload"
+)
+
+type fakeSchedulerClient struct {
+	existing  []string
+	created   []*scheduler.CreateScheduleInput
+	deleted   []string
+	createErr error
+}
+
+func (f *fakeSchedulerClient) CreateSchedule(_ context.Context, params *scheduler.CreateScheduleInput, _ ...func(*scheduler.Options)) (*scheduler.CreateScheduleOutput, error) {
+	if f.createErr != nil {
+		return nil, f.createErr
+	}
+	f.created = append(f.created, params)
+	return &scheduler.CreateScheduleOutput{}, nil
+}
+
+func (f *fakeSchedulerClient) DeleteSchedule(_ context.Context, params *scheduler.DeleteScheduleInput, _ ...func(*scheduler.Options)) (*scheduler.DeleteScheduleOutput, error) {
+	f.deleted = append(f.deleted, aws.ToString(params.Name))
+	return &scheduler.DeleteScheduleOutput{}, nil
+}
+
+// ListSchedules 1件ずつページングして返す
+func (f *fakeSchedulerClient) ListSchedules(_ context.Context, params *scheduler.ListSchedulesInput, _ ...func(*scheduler.Options)) (*scheduler.ListSchedulesOutput, error) {
+	index := 0
+	if params.NextToken != nil {
+		index = len(aws.ToString(params.NextToken))
+	}
+	output := &scheduler.ListSchedulesOutput{}
+	if index < len(f.existing) {
+		output.Schedules = []types.ScheduleSummary{{Name: aws.String(f.existing[index])}}
+	}
+	if index+1 < len(f.existing) {
+		output.NextToken = aws.String(string(make([]byte, index+1)))
+	}
+	return output, nil
+}
+
+func TestReminderScheduler_SendScheduleNotification(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, jst)
+
+	standup := domain.Event{ID: "standup", Title: "朝会", StartTime: time.Date(2024, 1, 15, 9, 5, 0, 0, jst)}
+	review := domain.Event{
+		ID:        "review",
+		Title:     "設計レビュー",
+		StartTime: time.Date(2024, 1, 15, 14, 0, 0, 0, jst),
+		Location:  "会議室A",
+		Reminders: []time.Duration{30 * time.Minute},
+	}
+	lunch := domain.Event{ID: "lunch", Title: "ランチ", StartTime: time.Date(2024, 1, 16, 12, 0, 0, 0, jst)}
+	today := []domain.Event{
+		{ID: "holiday", Title: "休暇", IsAllDay: true},
+		{ID: "deadline", Title: "提出", StartTime: time.Date(2024, 1, 15, 18, 0, 0, 0, jst), IsDeadline: true},
+		standup,
+		review,
+	}
+	report := domain.NewScheduleReport(now, now, now.AddDate(0, 0, 1), today, []domain.Event{lunch})
+
+	keep := reminderScheduleName(lunch, lunch.StartTime.Add(-10*time.Minute))
+	client := &fakeSchedulerClient{existing: []string{"reminder-stale", keep}}
+	s := NewReminderScheduler(client, "reminders", "arn:aws:lambda:target", "arn:aws:iam::role/scheduler", 10*time.Minute, clock.Fixed(now))
+
+	require.NoError(t, s.SendScheduleNotification(context.Background(), report))
+
+	// 変更・削除された予定のスケジュールは削除し、登録済みのものは作り直さない
+	assert.Equal(t, []string{"reminder-stale"}, client.deleted)
+
+	// 朝会は通知時刻（8:55）を過ぎているため登録しない
+	require.Len(t, client.created, 1)
+	created := client.created[0]
+	assert.Equal(t, reminderScheduleName(review, review.StartTime.Add(-30*time.Minute)), aws.ToString(created.Name))
+	assert.Equal(t, "reminders", aws.ToString(created.GroupName))
+	assert.Equal(t, "at(2024-01-15T13:30:00)", aws.ToString(created.ScheduleExpression))
+	assert.Equal(t, "Asia/Tokyo", aws.ToString(created.ScheduleExpressionTimezone))
+	assert.Equal(t, types.ActionAfterCompletionDelete, created.ActionAfterCompletion)
+	assert.Equal(t, "arn:aws:lambda:target", aws.ToString(created.Target.Arn))
+	assert.Equal(t, "arn:aws:iam::role/scheduler", aws.ToString(created.Target.RoleArn))
+
+	// 入力はこの関数がリマインダーとして受け付けるペイロード
+	event, err := payload.Parse([]byte(aws.ToString(created.Target.Input)))
+	require.NoError(t, err)
+	require.NotNil(t, event.Reminder)
+	input := *event.Reminder
+	assert.Equal(t, "review", input.EventID)
+	assert.Equal(t, "設計レビュー", input.Title)
+	assert.Equal(t, "会議室A", input.Location)
+	assert.True(t, input.RemindAt.Equal(time.Date(2024, 1, 15, 13, 30, 0, 0, jst)))
+}
+
+func TestReminderScheduler_CreateError(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, jst)
+	event := domain.Event{ID: "a", Title: "定例", StartTime: time.Date(2024, 1, 15, 10, 0, 0, 0, jst)}
+	report := domain.NewScheduleReport(now, now, now.AddDate(0, 0, 1), []domain.Event{event}, nil)
+
+	client := &fakeSchedulerClient{createErr: errors.New("throttled")}
+	s := NewReminderScheduler(client, "reminders", "arn:target", "arn:role", 10*time.Minute, clock.Fixed(now))
+
+	err := s.SendScheduleNotification(context.Background(), report)
+	assert.ErrorContains(t, err, "リマインダー1件の更新に失敗しました")
+}
+
+func TestReminderScheduleName_ChangesWithStartTime(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	event := domain.Event{ID: "a", StartTime: start}
+	moved := domain.Event{ID: "a", StartTime: start.Add(time.Hour)}
+
+	name := reminderScheduleName(event, start.Add(-10*time.Minute))
+	assert.Regexp(t, `^reminder-[0-9a-f]{20}$`, name)
+	assert.Equal(t, name, reminderScheduleName(event, start.Add(-10*time.Minute)))
+	assert.NotEqual(t, name, reminderScheduleName(moved, start.Add(-10*time.Minute)))
+}
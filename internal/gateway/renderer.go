@@ -0,0 +1,159 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/textutil"
+)
+
+// Renderer ScheduleReportを通知先の形式のメッセージ本文に変換する
+// 予定の並べ替え・省略・締め切りの分離はMessageFormatの設定に従い、各実装で共通にする
+type Renderer interface {
+	Render(report domain.ScheduleReport) []string
+}
+
+// dayLayout 表示用に整理した1日分の予定
+type dayLayout struct {
+	Date time.Time
+	// Count 締め切りを除く予定の件数（省略した分も含む）
+	Count int
+	// Timed 時間指定の予定（会議など）の件数
+	Timed int
+	// Listed 一覧に表示する予定
+	Listed []domain.Event
+	// AllDay 別ブロックに表示する終日予定
+	AllDay []domain.Event
+	// Hidden 件数上限により省略した予定の数
+	Hidden int
+	// Deadlines 締め切り（タイトルは短縮済み）
+	Deadlines []domain.Event
+	// Family 家族の予定（タイトル・場所は短縮済み）
+	Family []domain.Event
+	// Summary カテゴリ別の所要時間の内訳（分類ルールがない場合は空）
+	Summary string
+}
+
+// layoutDay MessageFormatの設定に従って1日分の予定を表示用に整理
+func (f MessageFormat) layoutDay(day domain.DaySchedule) dayLayout {
+	own, family := splitFamily(day.Events)
+	schedules, deadlines := splitDeadlines(own)
+	layout := dayLayout{Date: day.Date, Count: len(schedules)}
+	for _, event := range schedules {
+		if !event.IsAllDay {
+			layout.Timed++
+		}
+	}
+	if len(schedules) > 0 {
+		listed, allDay, hidden := f.collapse(f.arrangeAllDay(schedules))
+		for _, event := range listed {
+			layout.Listed = append(layout.Listed, f.shorten(event))
+		}
+		for _, event := range allDay {
+			layout.AllDay = append(layout.AllDay, f.shorten(event))
+		}
+		layout.Hidden = hidden
+		layout.Summary = f.categorySummary(schedules)
+	}
+	for _, deadline := range deadlines {
+		deadline.Title = textutil.TruncateWidth(deadline.Title, f.MaxTitleLength)
+		layout.Deadlines = append(layout.Deadlines, deadline)
+	}
+	for _, event := range family {
+		layout.Family = append(layout.Family, f.shorten(event))
+	}
+	return layout
+}
+
+// dayTemplate 1日分の予定の表示の種類
+type dayTemplate int
+
+const (
+	// dayTemplateDefault 予定の一覧を表示する
+	dayTemplateDefault dayTemplate = iota
+	// dayTemplateEmpty 予定がないことを表示する
+	dayTemplateEmpty
+	// dayTemplateMeetingFree 会議のない日として祝う表示にする（予定の一覧は併せて表示する）
+	dayTemplateMeetingFree
+)
+
+// meetingFreeMessage 会議のない日に表示するメッセージ
+const meetingFreeMessage = "🎉 会議のない一日です"
+
+// template 1日分の予定をどの種類の表示にするかを選ぶ
+func (f MessageFormat) template(day dayLayout) dayTemplate {
+	switch {
+	case f.CelebrateMeetingFree && day.Timed == 0:
+		return dayTemplateMeetingFree
+	case day.Count == 0:
+		return dayTemplateEmpty
+	default:
+		return dayTemplateDefault
+	}
+}
+
+// dayHeading 「1/15(月)」形式の日付
+func dayHeading(day time.Time) string {
+	return fmt.Sprintf("%s(%s)", day.Format("1/2"), getWeekdayJapanese(day.Weekday()))
+}
+
+// TextRenderer LINEなどのプレーンテキスト向けのRenderer
+type TextRenderer struct {
+	Format MessageFormat
+}
+
+// Render 予定通知のメッセージ本文を作成
+// セクションはMessageFormat.Sectionsの順に並べ、ブロックの間は空行で区切る
+// MessageFormat.SplitDaysが有効な場合は本日と翌日を別々のメッセージにする
+func (r TextRenderer) Render(report domain.ScheduleReport) []string {
+	var messages []string
+	var builder strings.Builder
+	builder.WriteString(scheduleMessageHeader)
+	separate, hasDay := false, false
+	for _, section := range r.Format.Sections.messageSections() {
+		block, isDay := r.section(section, report)
+		if block == "" {
+			continue
+		}
+		if separate {
+			if isDay && hasDay && r.Format.SplitDays {
+				messages = append(messages, r.Format.render(builder.String()))
+				builder.Reset()
+				hasDay = false
+			} else {
+				builder.WriteString("\n\n")
+			}
+		}
+		builder.WriteString(block)
+		separate = true
+		hasDay = hasDay || isDay
+	}
+	return append(messages, r.Format.render(builder.String()))
+}
+
+// section メッセージ全体のセクションの本文（表示しない場合は空）と本日・翌日の予定かどうか
+func (r TextRenderer) section(section Section, report domain.ScheduleReport) (string, bool) {
+	switch section {
+	case SectionWarning:
+		// ユースケースが判定した出社日の早い予定などの警告
+		return strings.Join(warningLines(report.Warnings), "\n"), false
+	case SectionAnniversaries:
+		return strings.Join(r.Format.Anniversaries.lines(report.Today.Date), "\n"), false
+	case SectionToday, SectionTomorrow:
+		var builder strings.Builder
+		label, day := reportDay(section, report)
+		r.Format.appendDaySection(&builder, label, day)
+		return builder.String(), true
+	}
+	return "", false
+}
+
+// reportDay 本日・翌日のセクションの見出しと予定
+func reportDay(section Section, report domain.ScheduleReport) (string, domain.DaySchedule) {
+	if section == SectionTomorrow {
+		return "翌日", report.Tomorrow
+	}
+	return "本日", report.Today
+}
@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+func rendererTestReport() domain.ScheduleReport {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	today := time.Date(2024, 1, 15, 7, 0, 0, 0, jst)
+	tomorrow := today.AddDate(0, 0, 1)
+	todayEvents := []domain.Event{
+		{
+			Title:     "定例 <週次>",
+			StartTime: time.Date(2024, 1, 15, 10, 0, 0, 0, jst),
+			EndTime:   time.Date(2024, 1, 15, 11, 0, 0, 0, jst),
+			Location:  "会議室A",
+		},
+		{Title: "有給", IsAllDay: true},
+		{Title: "経費精算", IsDeadline: true},
+	}
+	return domain.NewScheduleReport(today, today, tomorrow, todayEvents, nil)
+}
+
+func TestTextRenderer_MatchesLINEMessage(t *testing.T) {
+	var pushReq linePushRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&pushReq))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// LINEには渡したレポートをそのまま描画したメッセージを送る（通知側の時計で日付を算出し直さない）
+	report := rendererTestReport()
+	notifier := newTestLINENotifier("token", "user", server.Client(), server.URL, func() time.Time { return report.GeneratedAt.AddDate(0, 0, 3) })
+	require.NoError(t, notifier.SendScheduleNotification(context.Background(), report))
+
+	got := TextRenderer{}.Render(report)
+
+	require.Len(t, got, 1)
+	require.Len(t, pushReq.Messages, 1)
+	assert.Equal(t, got[0], pushReq.Messages[0].Text)
+}
+
+func TestTextRenderer_SplitDays(t *testing.T) {
+	got := TextRenderer{Format: MessageFormat{SplitDays: true}}.Render(rendererTestReport())
+
+	require.Len(t, got, 2)
+	assert.Contains(t, got[0], "本日 1/15(月) (2件):")
+	assert.Contains(t, got[1], "翌日 1/16(火): 予定なし")
+}
@@ -0,0 +1,107 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/ics"
+)
+
+// ScheduleExporter 1日分の予定をエクスポートし、取得用のURLを返す
+type ScheduleExporter interface {
+	Export(ctx context.Context, day time.Time, events []domain.Event) (string, error)
+}
+
+// S3PutObjectAPI は S3 へのオブジェクト保存を抽象化する
+type S3PutObjectAPI interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// S3GetObjectPresigner は S3 オブジェクト取得用の署名付きURL発行を抽象化する
+type S3GetObjectPresigner interface {
+	PresignGetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
+}
+
+// S3ICSExporter 予定を.icsファイルとしてS3にアップロードし、署名付きURLを発行するScheduleExporterの実装
+// 実行ごとに別のキーに保存し、以前に共有したURLの内容が後の実行で変わらないようにする
+type S3ICSExporter struct {
+	client      S3PutObjectAPI
+	presigner   S3GetObjectPresigner
+	credentials aws.CredentialsProvider
+	bucket      string
+	prefix      string
+	expires     time.Duration
+	now         func() time.Time
+}
+
+// NewS3ICSExporter S3へのICSエクスポーターを作成
+// 署名付きURLは署名に使う認証情報の期限切れとともに無効になるため、有効期限は認証情報の残り時間までに短縮する
+func NewS3ICSExporter(client *s3.Client, credentials aws.CredentialsProvider, bucket, prefix string, expires time.Duration) *S3ICSExporter {
+	return NewS3ICSExporterWithClients(client, s3.NewPresignClient(client), credentials, bucket, prefix, expires)
+}
+
+// NewS3ICSExporterWithClients S3クライアントを指定してエクスポーターを作成（テスト用）
+// credentialsがnilの場合は有効期限を短縮しない
+func NewS3ICSExporterWithClients(client S3PutObjectAPI, presigner S3GetObjectPresigner, credentials aws.CredentialsProvider, bucket, prefix string, expires time.Duration) *S3ICSExporter {
+	return &S3ICSExporter{
+		client:      client,
+		presigner:   presigner,
+		credentials: credentials,
+		bucket:      bucket,
+		prefix:      prefix,
+		expires:     expires,
+		now:         time.Now,
+	}
+}
+
+// Export 予定を.icsに変換してS3に保存し、ダウンロード用の署名付きURLを返す
+// キーは「プレフィックス/日付/作成時刻.ics」とし、ダウンロード時のファイル名は「日付.ics」にする
+func (e *S3ICSExporter) Export(ctx context.Context, day time.Time, events []domain.Event) (string, error) {
+	now := e.now()
+	filename := day.Format("2006-01-02") + ".ics"
+	key := fmt.Sprintf("%s%s/%s.ics", e.prefix, day.Format("2006-01-02"), now.UTC().Format("20060102T150405Z"))
+
+	_, err := e.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:             aws.String(e.bucket),
+		Key:                aws.String(key),
+		Body:               bytes.NewReader(ics.Encode(events, now)),
+		ContentType:        aws.String("text/calendar; charset=utf-8"),
+		ContentDisposition: aws.String(fmt.Sprintf(`attachment; filename="%s"`, filename)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("ICSファイルのアップロードに失敗しました: %v", err)
+	}
+
+	presigned, err := e.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(e.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(e.urlExpiry(ctx, now)))
+	if err != nil {
+		return "", fmt.Errorf("ICSファイルの署名付きURL発行に失敗しました: %v", err)
+	}
+
+	return presigned.URL, nil
+}
+
+// urlExpiry 署名付きURLの有効期限
+// Lambdaの実行ロールなど期限のある認証情報で署名したURLは認証情報とともに失効するため、その残り時間を上限にする
+func (e *S3ICSExporter) urlExpiry(ctx context.Context, now time.Time) time.Duration {
+	if e.credentials == nil {
+		return e.expires
+	}
+	creds, err := e.credentials.Retrieve(ctx)
+	if err != nil || !creds.CanExpire {
+		return e.expires
+	}
+	if remaining := creds.Expires.Sub(now); remaining > 0 && remaining < e.expires {
+		return remaining
+	}
+	return e.expires
+}
@@ -0,0 +1,109 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+type fakeS3Client struct {
+	input *s3.PutObjectInput
+	body  string
+	err   error
+}
+
+func (f *fakeS3Client) PutObject(_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.input = params
+	body, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.body = string(body)
+	return &s3.PutObjectOutput{}, nil
+}
+
+type fakeS3Presigner struct {
+	input   *s3.GetObjectInput
+	expires time.Duration
+}
+
+func (f *fakeS3Presigner) PresignGetObject(_ context.Context, params *s3.GetObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error) {
+	f.input = params
+	opts := s3.PresignOptions{}
+	for _, fn := range optFns {
+		fn(&opts)
+	}
+	f.expires = opts.Expires
+	return &v4.PresignedHTTPRequest{URL: "https://bucket.s3.amazonaws.com/" + *params.Key + "?X-Amz-Signature=sig"}, nil
+}
+
+func TestS3ICSExporter_Export(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	day := time.Date(2024, 1, 15, 9, 0, 0, 0, jst)
+	client := &fakeS3Client{}
+	presigner := &fakeS3Presigner{}
+	exporter := NewS3ICSExporterWithClients(client, presigner, nil, "test-bucket", "ics/", 6*time.Hour)
+	exporter.now = func() time.Time { return time.Date(2024, 1, 14, 22, 0, 0, 0, time.UTC) }
+
+	url, err := exporter.Export(context.Background(), day, []domain.Event{
+		{ID: "ev1", Title: "会議", StartTime: day.Add(time.Hour), EndTime: day.Add(2 * time.Hour)},
+	})
+	require.NoError(t, err)
+
+	// 実行ごとに別のキーに保存し、ダウンロード時のファイル名は日付にする
+	assert.Equal(t, "https://bucket.s3.amazonaws.com/ics/2024-01-15/20240114T220000Z.ics?X-Amz-Signature=sig", url)
+	assert.Equal(t, "test-bucket", *client.input.Bucket)
+	assert.Equal(t, "ics/2024-01-15/20240114T220000Z.ics", *client.input.Key)
+	assert.Equal(t, "text/calendar; charset=utf-8", *client.input.ContentType)
+	assert.Equal(t, `attachment; filename="2024-01-15.ics"`, *client.input.ContentDisposition)
+	assert.Contains(t, client.body, "SUMMARY:会議")
+	assert.Equal(t, "ics/2024-01-15/20240114T220000Z.ics", *presigner.input.Key)
+	assert.Equal(t, 6*time.Hour, presigner.expires)
+}
+
+func TestS3ICSExporter_UploadError(t *testing.T) {
+	client := &fakeS3Client{err: errors.New("access denied")}
+	exporter := NewS3ICSExporterWithClients(client, &fakeS3Presigner{}, nil, "test-bucket", "", time.Hour)
+
+	_, err := exporter.Export(context.Background(), time.Now(), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "アップロードに失敗しました")
+}
+
+func TestS3ICSExporter_ExpiryLimitedByCredentials(t *testing.T) {
+	now := time.Date(2024, 1, 14, 22, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		creds aws.Credentials
+		want  time.Duration
+	}{
+		{name: "実行ロールの認証情報は残り時間まで", creds: aws.Credentials{CanExpire: true, Expires: now.Add(50 * time.Minute)}, want: 50 * time.Minute},
+		{name: "残り時間が長い場合は設定値", creds: aws.Credentials{CanExpire: true, Expires: now.Add(48 * time.Hour)}, want: 24 * time.Hour},
+		{name: "期限のない認証情報は設定値", creds: aws.Credentials{}, want: 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			presigner := &fakeS3Presigner{}
+			credentials := aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) { return tt.creds, nil })
+			exporter := NewS3ICSExporterWithClients(&fakeS3Client{}, presigner, credentials, "test-bucket", "ics/", 24*time.Hour)
+			exporter.now = func() time.Time { return now }
+
+			_, err := exporter.Export(context.Background(), now, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, presigner.expires)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Section 通知メッセージのセクション
+type Section string
+
+// メッセージ全体のセクション
+const (
+	// SectionWarning 早めの出社の警告
+	SectionWarning Section = "warning"
+	// SectionAnniversaries 記念日のカウントダウン
+	SectionAnniversaries Section = "anniversaries"
+	// SectionToday 本日の予定
+	SectionToday Section = "today"
+	// SectionTomorrow 翌日の予定
+	SectionTomorrow Section = "tomorrow"
+)
+
+// 各日のセクション（日付の見出しの後に表示する）
+const (
+	// SectionEvents 予定の一覧
+	SectionEvents Section = "events"
+	// SectionSummary カテゴリ別の所要時間の内訳
+	SectionSummary Section = "summary"
+	// SectionDeadlines 締め切り
+	SectionDeadlines Section = "deadlines"
+	// SectionFamily 家族の予定
+	SectionFamily Section = "family"
+)
+
+var (
+	// defaultMessageSections メッセージ全体のセクションの既定の順序
+	defaultMessageSections = []Section{SectionWarning, SectionAnniversaries, SectionToday, SectionTomorrow}
+	// defaultDaySections 各日のセクションの既定の順序
+	defaultDaySections = []Section{SectionEvents, SectionSummary, SectionDeadlines, SectionFamily}
+)
+
+// SectionLayout セクションの表示順（ゼロ値は既定の順序）
+type SectionLayout struct {
+	// Message メッセージ全体のセクションの順序
+	Message []Section
+	// Day 各日のセクションの順序
+	Day []Section
+}
+
+// ParseSectionLayout "today,tomorrow,deadlines" 形式のセクション名の一覧から表示順を作成
+// 並べた順に表示し、含めなかったセクションは表示しない
+// メッセージ全体・各日のどちらかのセクションを1つも含めない場合、そちらは既定の順序のままにする
+// 各日の予定の一覧（events）は省略できず、含めない場合は各日の先頭に表示する
+// 本日・翌日の予定（today, tomorrow）は少なくとも一方を含める必要がある
+func ParseSectionLayout(names []string) (SectionLayout, error) {
+	var layout SectionLayout
+	seen := make(map[Section]bool, len(names))
+	for _, name := range names {
+		section := Section(strings.ToLower(strings.TrimSpace(name)))
+		if seen[section] {
+			return SectionLayout{}, fmt.Errorf("セクション %s が重複しています", section)
+		}
+		seen[section] = true
+		switch section {
+		case SectionWarning, SectionAnniversaries, SectionToday, SectionTomorrow:
+			layout.Message = append(layout.Message, section)
+		case SectionEvents, SectionSummary, SectionDeadlines, SectionFamily:
+			layout.Day = append(layout.Day, section)
+		default:
+			return SectionLayout{}, fmt.Errorf("セクション名が不正です: %q (warning, anniversaries, today, tomorrow, events, summary, deadlines, family のいずれか)", name)
+		}
+	}
+	if len(layout.Message) > 0 && !seen[SectionToday] && !seen[SectionTomorrow] {
+		return SectionLayout{}, fmt.Errorf("セクションには today と tomorrow の少なくとも一方を含めてください")
+	}
+	if len(layout.Day) > 0 && !seen[SectionEvents] {
+		layout.Day = append([]Section{SectionEvents}, layout.Day...)
+	}
+	return layout, nil
+}
+
+// messageSections メッセージ全体のセクションの順序
+func (l SectionLayout) messageSections() []Section {
+	if len(l.Message) == 0 {
+		return defaultMessageSections
+	}
+	return l.Message
+}
+
+// daySections 各日のセクションの順序
+func (l SectionLayout) daySections() []Section {
+	if len(l.Day) == 0 {
+		return defaultDaySections
+	}
+	return l.Day
+}
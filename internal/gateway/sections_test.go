@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+func TestParseSectionLayout(t *testing.T) {
+	layout, err := ParseSectionLayout([]string{"Tomorrow", "today", "deadlines", "events"})
+	require.NoError(t, err)
+	assert.Equal(t, SectionLayout{
+		Message: []Section{SectionTomorrow, SectionToday},
+		Day:     []Section{SectionDeadlines, SectionEvents},
+	}, layout)
+
+	// 予定の一覧は省略できず、各日の先頭に表示する
+	layout, err = ParseSectionLayout([]string{"family"})
+	require.NoError(t, err)
+	assert.Equal(t, SectionLayout{Day: []Section{SectionEvents, SectionFamily}}, layout)
+	assert.Equal(t, defaultMessageSections, layout.messageSections())
+
+	_, err = ParseSectionLayout([]string{"weather"})
+	assert.Error(t, err)
+	_, err = ParseSectionLayout([]string{"today", "today"})
+	assert.Error(t, err)
+	_, err = ParseSectionLayout([]string{"warning"})
+	assert.Error(t, err)
+}
+
+func TestTextRenderer_Sections(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	today := time.Date(2025, 3, 10, 7, 0, 0, 0, jst)
+	todayEvents := []domain.Event{
+		{Title: "定例", StartTime: today.Add(3 * time.Hour), EndTime: today.Add(4 * time.Hour)},
+		{Title: "請求書の提出", StartTime: today, EndTime: today, IsAllDay: true, IsDeadline: true},
+	}
+	report := domain.NewScheduleReport(today, today, today.AddDate(0, 0, 1), todayEvents, nil)
+	format := MessageFormat{
+		Anniversaries: &AnniversaryCountdown{Anniversaries: []Anniversary{{Name: "母の誕生日", Month: time.March, Day: 14}}, Within: 7},
+	}
+
+	format.Sections, _ = ParseSectionLayout([]string{"tomorrow", "today", "deadlines", "events"})
+	got := TextRenderer{Format: format}.Render(report)
+	require.Len(t, got, 1)
+	assert.Equal(t, scheduleMessageHeader+
+		"翌日 3/11(火): 予定なし\n"+
+		"\n\n"+
+		"本日 3/10(月) (1件):\n"+
+		"📋 締め切り:\n"+
+		"🔸 請求書の提出\n"+
+		"🔸 10:00〜11:00 定例\n", got[0])
+	assert.NotContains(t, got[0], "母の誕生日")
+
+	// 本日と翌日を分ける場合は、後に並べた日から別のメッセージにする
+	format.SplitDays = true
+	format.Sections, _ = ParseSectionLayout([]string{"tomorrow", "anniversaries", "today"})
+	got = TextRenderer{Format: format}.Render(report)
+	require.Len(t, got, 2)
+	assert.Equal(t, scheduleMessageHeader+"翌日 3/11(火): 予定なし\n\n\n🎂 母の誕生日まであと4日", got[0])
+	assert.Contains(t, got[1], "本日 3/10(月) (1件):\n🔸 10:00〜11:00 定例\n📋 締め切り:\n")
+}
@@ -0,0 +1,109 @@
+package ics
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/sanitize"
+)
+
+const (
+	// prodID iCalendarの生成元識別子
+	prodID = "-//google-calendar-line-notifier//JA"
+	// maxLineOctets RFC 5545で定められた1行あたりの最大オクテット数
+	maxLineOctets = 75
+)
+
+// Encode イベント一覧をiCalendar(.ics)形式に変換
+// stampは各イベントのDTSTAMP（生成日時）として使用する
+func Encode(events []domain.Event, stamp time.Time) []byte {
+	var b strings.Builder
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+prodID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+
+	for _, event := range events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+escapeText(uid(event)))
+		writeLine(&b, "DTSTAMP:"+formatUTC(stamp))
+		if event.IsAllDay {
+			writeLine(&b, "DTSTART;VALUE=DATE:"+event.StartTime.Format("20060102"))
+			writeLine(&b, "DTEND;VALUE=DATE:"+allDayEnd(event).Format("20060102"))
+		} else {
+			writeLine(&b, "DTSTART:"+formatUTC(event.StartTime))
+			writeLine(&b, "DTEND:"+formatUTC(event.EndTime))
+		}
+		writeLine(&b, "SUMMARY:"+escapeText(event.Title))
+		if event.Location != "" {
+			writeLine(&b, "LOCATION:"+escapeText(event.Location))
+		}
+		if event.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(sanitize.HTMLToText(event.Description)))
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// uid イベントの一意な識別子を生成
+func uid(event domain.Event) string {
+	if event.ID != "" {
+		return event.ID + "@google-calendar-line-notifier"
+	}
+	h := fnv.New64a()
+	h.Write([]byte(event.Title))
+	return fmt.Sprintf("%s-%x@google-calendar-line-notifier", event.StartTime.Format("20060102T150405"), h.Sum64())
+}
+
+// allDayEnd 終日イベントの終了日（排他的）を返す
+func allDayEnd(event domain.Event) time.Time {
+	if event.EndTime.After(event.StartTime) {
+		return event.EndTime
+	}
+	return event.StartTime.AddDate(0, 0, 1)
+}
+
+// formatUTC 日時をUTCのiCalendar形式に変換
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeText TEXT型の値に含まれる特殊文字をエスケープ
+func escapeText(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(s)
+}
+
+// writeLine 75オクテットを超える行を折り返してCRLFで書き込む
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		// マルチバイト文字の途中で分割しないよう境界まで戻す
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// 継続行は先頭の空白1文字分だけ短くなる
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,73 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+func TestEncode(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	stamp := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	events := []domain.Event{
+		{
+			ID:          "event-1",
+			Title:       "朝会, 定例",
+			StartTime:   time.Date(2024, 1, 15, 9, 0, 0, 0, jst),
+			EndTime:     time.Date(2024, 1, 15, 9, 30, 0, 0, jst),
+			Location:    "会議室A; 3F",
+			Description: `<p>議題</p><a href="https://example.com/doc">資料</a>`,
+		},
+		{
+			ID:        "event-2",
+			Title:     "休暇",
+			StartTime: time.Date(2024, 1, 15, 0, 0, 0, 0, jst),
+			EndTime:   time.Date(2024, 1, 16, 0, 0, 0, 0, jst),
+			IsAllDay:  true,
+		},
+	}
+
+	result := string(Encode(events, stamp))
+
+	assert.True(t, strings.HasPrefix(result, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(result, "END:VCALENDAR\r\n"))
+	assert.Contains(t, result, "UID:event-1@google-calendar-line-notifier\r\n")
+	assert.Contains(t, result, "DTSTAMP:20240115T000000Z\r\n")
+	assert.Contains(t, result, "DTSTART:20240115T000000Z\r\n")
+	assert.Contains(t, result, "DTEND:20240115T003000Z\r\n")
+	assert.Contains(t, result, `SUMMARY:朝会\, 定例`+"\r\n")
+	assert.Contains(t, result, `LOCATION:会議室A\; 3F`+"\r\n")
+	assert.Contains(t, result, `DESCRIPTION:議題\n資料 (https://example.com/doc)`+"\r\n")
+	assert.Contains(t, result, "DTSTART;VALUE=DATE:20240115\r\n")
+	assert.Contains(t, result, "DTEND;VALUE=DATE:20240116\r\n")
+	assert.Equal(t, 2, strings.Count(result, "BEGIN:VEVENT"))
+}
+
+func TestEscapeText(t *testing.T) {
+	assert.Equal(t, `a\\b\;c\,d\ne`, escapeText("a\\b;c,d\ne"))
+}
+
+func TestWriteLine_FoldsLongLines(t *testing.T) {
+	var b strings.Builder
+	writeLine(&b, "DESCRIPTION:"+strings.Repeat("あ", 60))
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	assert.Greater(t, len(lines), 1)
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), maxLineOctets)
+		assert.True(t, utf8.ValidString(line), "マルチバイト文字が分割されています")
+		if i > 0 {
+			assert.True(t, strings.HasPrefix(line, " "))
+		}
+	}
+
+	// 折り返しを戻すと元の文字列になる
+	unfolded := strings.ReplaceAll(strings.TrimSuffix(b.String(), "\r\n"), "\r\n ", "")
+	assert.Equal(t, "DESCRIPTION:"+strings.Repeat("あ", 60), unfolded)
+}
@@ -0,0 +1,143 @@
+// Package metrics はCloudWatch Embedded Metric Format（EMF）でメトリクスをログに出力する
+// Lambdaの標準出力に書き出したEMFのログは、CloudWatchがメトリクスとして取り込む
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Namespace メトリクスの名前空間
+const Namespace = "GoogleCalendarLineNotifier"
+
+// 実行結果
+const (
+	// OutcomeSent 通知を送信した
+	OutcomeSent = "sent"
+	// OutcomeDryRun ドライランのため送信しなかった
+	OutcomeDryRun = "dry_run"
+	// OutcomeSkipped 意図的に送信しなかった（理由はSkipReasonに記録する）
+	OutcomeSkipped = "skipped"
+	// OutcomeFailed 失敗した
+	OutcomeFailed = "failed"
+)
+
+// noSkipReason 送信しなかった理由がない場合のSkipReasonディメンションの値
+// EMFではディメンションの値を省略できないため、空の代わりに使う
+const noSkipReason = "none"
+
+// emfMetadata EMFのメタデータ
+type emfMetadata struct {
+	Timestamp         int64              `json:"Timestamp"`
+	CloudWatchMetrics []emfMetricsConfig `json:"CloudWatchMetrics"`
+}
+
+// emfMetricsConfig EMFのメトリクス定義
+type emfMetricsConfig struct {
+	Namespace  string            `json:"Namespace"`
+	Dimensions [][]string        `json:"Dimensions"`
+	Metrics    []emfMetricConfig `json:"Metrics"`
+}
+
+// emfMetricConfig EMFの個々のメトリクスの定義
+type emfMetricConfig struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit"`
+}
+
+// runRecord 1回の実行のメトリクス
+type runRecord struct {
+	AWS        emfMetadata `json:"_aws"`
+	Outcome    string      `json:"Outcome"`
+	SkipReason string      `json:"SkipReason"`
+	Runs       int         `json:"Runs"`
+	RunID      string      `json:"RunId,omitempty"`
+}
+
+// EmitRun 実行結果ごとの実行回数（Runs）をOutcome・SkipReasonのディメンション付きで出力する
+// ダッシュボードで意図的に送信しなかった実行と失敗を区別するために使う
+func EmitRun(w io.Writer, now time.Time, runID, outcome, skipReason string) error {
+	if skipReason == "" {
+		skipReason = noSkipReason
+	}
+	record := runRecord{
+		AWS: emfMetadata{
+			Timestamp: now.UnixMilli(),
+			CloudWatchMetrics: []emfMetricsConfig{{
+				Namespace:  Namespace,
+				Dimensions: [][]string{{"Outcome"}, {"Outcome", "SkipReason"}},
+				Metrics:    []emfMetricConfig{{Name: "Runs", Unit: "Count"}},
+			}},
+		},
+		Outcome:    outcome,
+		SkipReason: skipReason,
+		Runs:       1,
+		RunID:      runID,
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("メトリクスのJSON変換に失敗しました: %v", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// requestRecord 1回のHTTPリクエストのメトリクス
+type requestRecord struct {
+	AWS        emfMetadata `json:"_aws"`
+	Host       string      `json:"Host"`
+	StatusCode string      `json:"StatusCode"`
+	Method     string      `json:"Method"`
+	Requests   int         `json:"Requests"`
+	Latency    float64     `json:"Latency"`
+}
+
+// EmitRequest 外部APIへのリクエスト回数（Requests）と所要時間（Latency）をHost・StatusCodeのディメンション付きで出力する
+// 通信エラーでレスポンスがない場合、StatusCodeは"error"とする
+func EmitRequest(w io.Writer, now time.Time, method, host string, statusCode int, duration time.Duration, err error) error {
+	status := fmt.Sprintf("%d", statusCode)
+	if err != nil && statusCode == 0 {
+		status = "error"
+	}
+	record := requestRecord{
+		AWS: emfMetadata{
+			Timestamp: now.UnixMilli(),
+			CloudWatchMetrics: []emfMetricsConfig{{
+				Namespace:  Namespace,
+				Dimensions: [][]string{{"Host"}, {"Host", "StatusCode"}},
+				Metrics:    []emfMetricConfig{{Name: "Requests", Unit: "Count"}, {Name: "Latency", Unit: "Milliseconds"}},
+			}},
+		},
+		Host:       host,
+		StatusCode: status,
+		Method:     method,
+		Requests:   1,
+		Latency:    float64(duration) / float64(time.Millisecond),
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("メトリクスのJSON変換に失敗しました: %v", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// RequestObserver transport.RequestObserverとしてリクエストごとのメトリクスを出力する
+type RequestObserver struct {
+	w   io.Writer
+	now func() time.Time
+}
+
+// NewRequestObserver wに出力するRequestObserverを作成
+func NewRequestObserver(w io.Writer) *RequestObserver {
+	return &RequestObserver{w: w, now: time.Now}
+}
+
+// ObserveRequest リクエストの結果をメトリクスとして出力する
+func (o *RequestObserver) ObserveRequest(method, host string, statusCode int, duration time.Duration, err error) {
+	if err := EmitRequest(o.w, o.now(), method, host, statusCode, duration, err); err != nil {
+		fmt.Printf("Warning: メトリクスを出力できませんでした: %v\n", err)
+	}
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmitRun(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2024, 1, 15, 1, 0, 0, 0, time.UTC)
+	require.NoError(t, EmitRun(&buf, now, "run-1", OutcomeSkipped, "empty"))
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, "skipped", got["Outcome"])
+	assert.Equal(t, "empty", got["SkipReason"])
+	assert.Equal(t, "run-1", got["RunId"])
+	assert.EqualValues(t, 1, got["Runs"])
+
+	aws := got["_aws"].(map[string]any)
+	assert.EqualValues(t, now.UnixMilli(), aws["Timestamp"])
+	metric := aws["CloudWatchMetrics"].([]any)[0].(map[string]any)
+	assert.Equal(t, Namespace, metric["Namespace"])
+	assert.Equal(t, []any{[]any{"Outcome"}, []any{"Outcome", "SkipReason"}}, metric["Dimensions"])
+}
+
+func TestEmitRun_NoSkipReason(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, EmitRun(&buf, time.Now(), "", OutcomeSent, ""))
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, "none", got["SkipReason"])
+	assert.NotContains(t, got, "RunId")
+}
+
+func TestRequestObserver(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2024, 1, 15, 1, 0, 0, 0, time.UTC)
+	observer := NewRequestObserver(&buf)
+	observer.now = func() time.Time { return now }
+
+	observer.ObserveRequest("POST", "api.line.me", 200, 1500*time.Microsecond, nil)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, "api.line.me", got["Host"])
+	assert.Equal(t, "200", got["StatusCode"])
+	assert.Equal(t, "POST", got["Method"])
+	assert.EqualValues(t, 1, got["Requests"])
+	assert.EqualValues(t, 1.5, got["Latency"])
+
+	aws := got["_aws"].(map[string]any)
+	assert.EqualValues(t, now.UnixMilli(), aws["Timestamp"])
+	metric := aws["CloudWatchMetrics"].([]any)[0].(map[string]any)
+	assert.Equal(t, []any{[]any{"Host"}, []any{"Host", "StatusCode"}}, metric["Dimensions"])
+}
+
+func TestEmitRequest_Error(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, EmitRequest(&buf, time.Now(), "GET", "www.googleapis.com", 0, time.Second, errors.New("connection refused")))
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, "error", got["StatusCode"])
+}
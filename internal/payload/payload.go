@@ -0,0 +1,149 @@
+// Package payload はLambda関数が受け付けるイベントペイロードの型とスキーマを定義する
+package payload
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
+)
+
+// SchemaVersion 現在のペイロードのバージョン
+const SchemaVersion = 1
+
+// Schema ペイロードのJSONスキーマ（EventBridgeの入力定数を作成する際の参照用）
+//
+//go:embed schema.json
+var Schema []byte
+
+// Event Lambda関数が受け付けるペイロード
+// EventBridge Schedulerの入力定数としてJSONで指定する。空の場合はすべてデフォルト値で実行する
+type Event struct {
+	// Version ペイロードのバージョン（省略時はSchemaVersion）
+	Version int `json:"version,omitempty"`
+	// Flags この実行でのみ有効にするフィーチャーフラグ（"-"を付けると無効）
+	Flags []string `json:"flags,omitempty"`
+	// TodayOverride 今日として扱う日付（YYYY-MM-DD、設定されたタイムゾーン）。年末年始・月末・うるう日の表示確認用
+	TodayOverride string `json:"todayOverride,omitempty"`
+	// DryRun trueの場合はLINEに送信せず、メッセージをログに出力する
+	DryRun bool `json:"dryRun,omitempty"`
+	// Force TodayOverrideを指定した場合でも実際に送信する
+	Force bool `json:"force,omitempty"`
+	// Schedule 実行するスケジュールの名前（SCHEDULESで定義したスケジュールの表示テンプレートを適用する）
+	Schedule string `json:"schedule,omitempty"`
+	// Reminder 指定した場合は予定通知の代わりに、この予定のリマインダーを送信する
+	// REMINDER_SCHEDULE_GROUPを設定した場合に、予定ごとに登録する1回限りのスケジュールが指定する
+	Reminder *Reminder `json:"reminder,omitempty"`
+}
+
+// Reminder リマインダーを送信する予定
+type Reminder struct {
+	EventID  string    `json:"eventId"`
+	Title    string    `json:"title"`
+	Start    time.Time `json:"start"`
+	Location string    `json:"location,omitempty"`
+	RemindAt time.Time `json:"remindAt"`
+}
+
+// todayOverrideLayout TodayOverrideの日付形式
+const todayOverrideLayout = "2006-01-02"
+
+// Today TodayOverrideで指定された日付（設定されたタイムゾーンの0時）を返す。未指定の場合はfalse
+func (e Event) Today() (time.Time, bool) {
+	if e.TodayOverride == "" {
+		return time.Time{}, false
+	}
+	date, err := dates.ParseDate(e.TodayOverride, dates.Location())
+	if err != nil {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// IsDryRun 送信せずにメッセージを確認するだけの実行かどうか
+// TodayOverrideを指定した場合は、Forceを指定しない限りドライランとして扱う
+func (e Event) IsDryRun() bool {
+	if e.DryRun {
+		return true
+	}
+	_, overridden := e.Today()
+	return overridden && !e.Force
+}
+
+// scheduledEventEnvelope 入力定数を指定しないEventBridgeルールから届くイベントの判定用
+type scheduledEventEnvelope struct {
+	Source     string `json:"source"`
+	DetailType string `json:"detail-type"`
+}
+
+// Parse ペイロードを厳密に検証して解析する
+// 未知のフィールドや不正なバージョンは、入力定数の修正箇所がわかるエラーにする
+func Parse(raw []byte) (Event, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return Event{Version: SchemaVersion}, nil
+	}
+
+	// 入力定数のないスケジュールルールはEventBridgeのイベントそのものが届くため、デフォルト値で実行する
+	var envelope scheduledEventEnvelope
+	if err := json.Unmarshal(trimmed, &envelope); err == nil && envelope.Source == "aws.events" && envelope.DetailType != "" {
+		return Event{Version: SchemaVersion}, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.DisallowUnknownFields()
+
+	var event Event
+	if err := decoder.Decode(&event); err != nil {
+		return Event{}, describeError(err)
+	}
+	if decoder.More() {
+		return Event{}, errors.New("ペイロードが不正です: JSONオブジェクトの後に余分なデータがあります")
+	}
+
+	if event.Version == 0 {
+		event.Version = SchemaVersion
+	}
+	if event.Version != SchemaVersion {
+		return Event{}, fmt.Errorf("ペイロードが不正です: versionは%dのみ対応しています（指定値: %d）", SchemaVersion, event.Version)
+	}
+	if event.TodayOverride != "" {
+		if _, err := time.Parse(todayOverrideLayout, event.TodayOverride); err != nil {
+			return Event{}, fmt.Errorf("ペイロードが不正です: todayOverrideはYYYY-MM-DD形式で指定してください（指定値: %s）", event.TodayOverride)
+		}
+	}
+	if event.Schedule != "" && strings.TrimSpace(event.Schedule) != event.Schedule {
+		return Event{}, fmt.Errorf("ペイロードが不正です: scheduleの前後に空白があります（指定値: %q）", event.Schedule)
+	}
+	if event.Reminder != nil && (event.Reminder.Title == "" || event.Reminder.Start.IsZero()) {
+		return Event{}, errors.New("ペイロードが不正です: reminderにはtitleとstartを指定してください")
+	}
+	for _, flag := range event.Flags {
+		if strings.TrimSpace(strings.TrimPrefix(flag, "-")) == "" {
+			return Event{}, errors.New("ペイロードが不正です: flagsに空のフラグ名が含まれています")
+		}
+	}
+	return event, nil
+}
+
+// describeError JSONの解析エラーを入力定数の修正に役立つメッセージにする
+func describeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("ペイロードが不正です: JSONの構文エラー（%d文字目）: %v", syntaxErr.Offset, err)
+	case errors.As(err, &typeErr):
+		return fmt.Errorf("ペイロードが不正です: %sの型が不正です（%sが必要）", typeErr.Field, typeErr.Type)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+		return fmt.Errorf("ペイロードが不正です: 未知のフィールド %s（使用できるのは version, flags, todayOverride, dryRun, force, schedule, reminder）", field)
+	default:
+		return fmt.Errorf("ペイロードが不正です: %v", err)
+	}
+}
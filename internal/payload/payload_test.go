@@ -0,0 +1,137 @@
+package payload
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want Event
+	}{
+		{name: "空", raw: "", want: Event{Version: 1}},
+		{name: "null", raw: "null", want: Event{Version: 1}},
+		{name: "空のオブジェクト", raw: "{}", want: Event{Version: 1}},
+		{name: "フラグ指定", raw: `{"version":1,"flags":["plain_text","-split_day_messages"]}`, want: Event{Version: 1, Flags: []string{"plain_text", "-split_day_messages"}}},
+		{name: "スケジュール指定", raw: `{"schedule":"evening"}`, want: Event{Version: 1, Schedule: "evening"}},
+		{
+			name: "リマインダー",
+			raw:  `{"version":1,"reminder":{"eventId":"review","title":"設計レビュー","start":"2024-01-15T14:00:00+09:00","location":"会議室A","remindAt":"2024-01-15T13:30:00+09:00"}}`,
+			want: Event{Version: 1, Reminder: &Reminder{
+				EventID:  "review",
+				Title:    "設計レビュー",
+				Start:    time.Date(2024, 1, 15, 14, 0, 0, 0, time.FixedZone("", 9*60*60)),
+				Location: "会議室A",
+				RemindAt: time.Date(2024, 1, 15, 13, 30, 0, 0, time.FixedZone("", 9*60*60)),
+			}},
+		},
+		{
+			name: "入力定数のないスケジュールルール",
+			raw:  `{"version":"0","id":"abc","detail-type":"Scheduled Event","source":"aws.events","time":"2024-01-15T01:00:00Z","detail":{}}`,
+			want: Event{Version: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.raw))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "未知のフィールド", raw: `{"mode":"preview"}`, want: `未知のフィールド "mode"`},
+		{name: "型の誤り", raw: `{"flags":"plain_text"}`, want: "flagsの型が不正です"},
+		{name: "構文エラー", raw: `{"flags":[}`, want: "JSONの構文エラー"},
+		{name: "日付の形式", raw: `{"todayOverride":"2024/12/31"}`, want: "todayOverrideはYYYY-MM-DD形式"},
+		{name: "存在しない日付", raw: `{"todayOverride":"2023-02-29"}`, want: "todayOverrideはYYYY-MM-DD形式"},
+		{name: "未対応のバージョン", raw: `{"version":2}`, want: "versionは1のみ対応しています"},
+		{name: "空のフラグ", raw: `{"flags":["-"]}`, want: "空のフラグ名"},
+		{name: "余分なデータ", raw: `{} {}`, want: "余分なデータ"},
+		{name: "スケジュール名の空白", raw: `{"schedule":" evening"}`, want: "scheduleの前後に空白"},
+		{name: "リマインダーのタイトルなし", raw: `{"reminder":{"start":"2024-01-15T14:00:00+09:00"}}`, want: "reminderにはtitleとstart"},
+		{name: "リマインダーの未知のフィールド", raw: `{"reminder":{"title":"定例","start":"2024-01-15T14:00:00+09:00","url":"x"}}`, want: `未知のフィールド "url"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.raw))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestEvent_TodayOverride(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+
+	event, err := Parse([]byte(`{"todayOverride":"2024-02-29"}`))
+	require.NoError(t, err)
+	today, ok := event.Today()
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, jst), today)
+	assert.True(t, event.IsDryRun(), "日付を差し替えた場合はドライラン")
+
+	event, err = Parse([]byte(`{"todayOverride":"2024-12-31","force":true}`))
+	require.NoError(t, err)
+	assert.False(t, event.IsDryRun(), "forceを指定した場合は送信する")
+
+	event, err = Parse([]byte(`{"dryRun":true}`))
+	require.NoError(t, err)
+	_, ok = event.Today()
+	assert.False(t, ok)
+	assert.True(t, event.IsDryRun())
+
+	assert.False(t, Event{}.IsDryRun())
+}
+
+func TestEvent_TodayOverride_ConfiguredTimezone(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	dates.SetLocation(ny)
+	t.Cleanup(func() { dates.SetLocation(nil) })
+
+	event, err := Parse([]byte(`{"todayOverride":"2024-03-10"}`))
+	require.NoError(t, err)
+	today, ok := event.Today()
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, ny), today)
+}
+
+func TestSchema_MatchesEvent(t *testing.T) {
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(Schema, &schema))
+
+	var schemaFields []string
+	for name := range schema.Properties {
+		schemaFields = append(schemaFields, name)
+	}
+	var structFields []string
+	eventType := reflect.TypeOf(Event{})
+	for i := 0; i < eventType.NumField(); i++ {
+		structFields = append(structFields, strings.Split(eventType.Field(i).Tag.Get("json"), ",")[0])
+	}
+	sort.Strings(schemaFields)
+	sort.Strings(structFields)
+
+	assert.Equal(t, structFields, schemaFields)
+}
@@ -0,0 +1,95 @@
+// Package rewrite は設定された正規表現ルールで予定のタイトルを書き換える
+package rewrite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/usecase"
+)
+
+// Rule タイトルの書き換えルール
+type Rule struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// ruleJSON 設定値として受け付けるルールのJSON表現
+type ruleJSON struct {
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+}
+
+// ParseRules [{"pattern":"^\\[PRJ-\\d+\\]\\s*","replacement":""}] 形式のJSONからルールを解析する
+// replacementでは $1 などでキャプチャグループを参照できる
+func ParseRules(s string) ([]Rule, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var raw []ruleJSON
+	if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		return nil, fmt.Errorf("タイトル書き換えルールのJSON解析に失敗しました: %v", err)
+	}
+
+	rules := make([]Rule, 0, len(raw))
+	for _, r := range raw {
+		pattern, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("タイトル書き換えルールの正規表現が不正です (%s): %v", r.Pattern, err)
+		}
+		rules = append(rules, Rule{Pattern: pattern, Replacement: r.Replacement})
+	}
+	return rules, nil
+}
+
+// Rewriter ルールを定義順に適用してタイトルを書き換える
+type Rewriter struct {
+	rules []Rule
+}
+
+// NewRewriter ルールを指定してRewriterを作成
+func NewRewriter(rules []Rule) *Rewriter {
+	return &Rewriter{rules: rules}
+}
+
+// Title タイトルにすべてのルールを適用する
+// 書き換えの結果が空になる場合は元のタイトルを返す
+func (w *Rewriter) Title(title string) string {
+	rewritten := title
+	for _, rule := range w.rules {
+		rewritten = rule.Pattern.ReplaceAllString(rewritten, rule.Replacement)
+	}
+	if rewritten = strings.TrimSpace(rewritten); rewritten == "" {
+		return title
+	}
+	return rewritten
+}
+
+// Repository 取得した予定のタイトルを書き換えるCalendarRepositoryのデコレーター
+type Repository struct {
+	next     usecase.CalendarRepository
+	rewriter *Rewriter
+}
+
+// NewRepository nextから取得した予定のタイトルをrewriterで書き換えるRepositoryを作成
+func NewRepository(next usecase.CalendarRepository, rewriter *Rewriter) *Repository {
+	return &Repository{next: next, rewriter: rewriter}
+}
+
+// GetEvents 指定日のイベントを取得し、タイトルを書き換えて返す
+func (r *Repository) GetEvents(ctx context.Context, targetDate time.Time) ([]domain.Event, error) {
+	events, err := r.next.GetEvents(ctx, targetDate)
+	if err != nil {
+		return nil, err
+	}
+	for i := range events {
+		events[i].Title = r.rewriter.Title(events[i].Title)
+	}
+	return events, nil
+}
@@ -0,0 +1,58 @@
+package rewrite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/fakes"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(`[{"pattern":"^\\[[A-Z]+-\\d+\\]\\s*","replacement":""},{"pattern":"Weekly (\\w+)","replacement":"週次$1"}]`)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "週次$1", rules[1].Replacement)
+
+	rules, err = ParseRules("")
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	_, err := ParseRules(`not json`)
+	assert.Error(t, err)
+
+	_, err = ParseRules(`[{"pattern":"(unclosed","replacement":""}]`)
+	assert.Error(t, err)
+}
+
+func TestRewriter_Title(t *testing.T) {
+	rules, err := ParseRules(`[{"pattern":"^\\[[A-Z]+-\\d+\\]\\s*","replacement":""},{"pattern":"^Weekly (\\w+)$","replacement":"週次$1"}]`)
+	require.NoError(t, err)
+	w := NewRewriter(rules)
+
+	assert.Equal(t, "設計レビュー", w.Title("[PRJ-123] 設計レビュー"))
+	assert.Equal(t, "週次Sync", w.Title("[OPS-9] Weekly Sync"))
+	assert.Equal(t, "ランチ", w.Title("ランチ"))
+	// 書き換えで空になる場合は元のタイトル
+	assert.Equal(t, "[PRJ-1]", w.Title("[PRJ-1]"))
+}
+
+func TestRepository_GetEvents(t *testing.T) {
+	rules, err := ParseRules(`[{"pattern":"^\\[PRJ-\\d+\\]\\s*","replacement":""}]`)
+	require.NoError(t, err)
+	repo := NewRepository(fakes.CalendarRepository{Events: []domain.Event{{Title: "[PRJ-1] 定例"}}}, NewRewriter(rules))
+
+	events, err := repo.GetEvents(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "定例", events[0].Title)
+
+	_, err = NewRepository(fakes.CalendarRepository{Err: errors.New("boom")}, NewRewriter(rules)).GetEvents(context.Background(), time.Now())
+	assert.Error(t, err)
+}
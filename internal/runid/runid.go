@@ -0,0 +1,28 @@
+// Package runid は1回の実行を識別するIDをcontextで受け渡す
+// ログ、送信ログ、レスポンスに同じIDを残し、特定の配信を追跡できるようにする
+package runid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// contextKey contextに保存する際のキー
+type contextKey struct{}
+
+// New 新しい実行IDを生成
+func New() string {
+	return uuid.NewString()
+}
+
+// WithRunID 実行IDを保存したcontextを返す
+func WithRunID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext contextに保存された実行IDを返す（未設定の場合は空文字）
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
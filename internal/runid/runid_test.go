@@ -0,0 +1,19 @@
+package runid
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunID(t *testing.T) {
+	assert.Empty(t, FromContext(context.Background()))
+
+	id := New()
+	assert.NotEmpty(t, id)
+	assert.NotEqual(t, id, New())
+
+	ctx := WithRunID(context.Background(), id)
+	assert.Equal(t, id, FromContext(ctx))
+}
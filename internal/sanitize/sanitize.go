@@ -0,0 +1,152 @@
+// Package sanitize はカレンダーの説明文など外部由来のテキストを表示用に整形する
+package sanitize
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	// urlPattern テキスト中のURL
+	urlPattern = regexp.MustCompile(`https?://[^\s<>"'（）「」、。]+`)
+	// spacesPattern 連続する空白（改行を除く）
+	spacesPattern = regexp.MustCompile(`[ \t\f\r\x{00A0}\x{3000}]+`)
+	// blankLinesPattern 3行以上連続する改行
+	blankLinesPattern = regexp.MustCompile(`\n{3,}`)
+)
+
+// HTMLToText HTMLをプレーンテキストに変換する
+// タグを除去して文字参照をデコードし、ブロック要素は改行に、リンクは「テキスト (URL)」に置き換えて空白を詰める
+func HTMLToText(s string) string {
+	if !strings.ContainsAny(s, "<&") {
+		return collapseWhitespace(s)
+	}
+
+	var b strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(s))
+	var skipDepth int
+	var href, linkText string
+	inLink := false
+
+	for {
+		tt := tokenizer.Next()
+		switch tt {
+		case html.ErrorToken:
+			return collapseWhitespace(b.String())
+
+		case html.TextToken:
+			if skipDepth > 0 {
+				continue
+			}
+			text := string(tokenizer.Text())
+			if inLink {
+				linkText += text
+			}
+			b.WriteString(text)
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			a := atom.Lookup(name)
+			switch {
+			case a == atom.Script || a == atom.Style:
+				if tt == html.StartTagToken {
+					skipDepth++
+				}
+			case a == atom.A && tt == html.StartTagToken:
+				inLink, href, linkText = true, "", ""
+				for hasAttr {
+					var key, val []byte
+					key, val, hasAttr = tokenizer.TagAttr()
+					if string(key) == "href" {
+						href = string(val)
+					}
+				}
+			case isBlock(a):
+				b.WriteString("\n")
+			}
+
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			a := atom.Lookup(name)
+			switch {
+			case a == atom.Script || a == atom.Style:
+				if skipDepth > 0 {
+					skipDepth--
+				}
+			case a == atom.A:
+				// リンク先がテキストと異なる場合のみURLを併記する
+				if inLink && href != "" && strings.TrimSpace(linkText) != href && strings.HasPrefix(href, "http") {
+					b.WriteString(" (" + href + ")")
+				}
+				inLink = false
+			case isBlock(a):
+				b.WriteString("\n")
+			}
+		}
+	}
+}
+
+// ExtractURLs テキストに含まれるhttp(s)のURLを出現順に重複なく返す
+func ExtractURLs(s string) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, u := range urlPattern.FindAllString(s, -1) {
+		u = strings.TrimRight(u, ".,;:!?)]}")
+		if !seen[u] {
+			seen[u] = true
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// StripEmoji 絵文字と、絵文字の表示に使う異体字セレクタ・結合子を取り除く
+func StripEmoji(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isEmoji(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// isEmoji 絵文字として扱う文字かどうか
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // 絵文字・国旗・肌の色
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // その他の記号・装飾記号
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // ⭐ などの矢印・記号
+		return true
+	case r == 0x231A || r == 0x231B || r == 0x2328 || r == 0x23CF || (r >= 0x23E9 && r <= 0x23FA): // ⌚ ⏰ ⏱ など
+		return true
+	case r == 0x200D || r == 0x20E3 || r == 0xFE0E || r == 0xFE0F: // 結合子・異体字セレクタ
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // タグ文字
+		return true
+	}
+	return false
+}
+
+// isBlock 改行として扱う要素かどうか
+func isBlock(a atom.Atom) bool {
+	switch a {
+	case atom.Br, atom.P, atom.Div, atom.Li, atom.Ul, atom.Ol, atom.Tr, atom.Table,
+		atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Blockquote, atom.Pre, atom.Hr:
+		return true
+	}
+	return false
+}
+
+// collapseWhitespace 行内の連続する空白を1つにまとめ、空行は最大1行に詰める
+func collapseWhitespace(s string) string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(spacesPattern.ReplaceAllString(line, " "))
+	}
+	return strings.TrimSpace(blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,47 @@
+package sanitize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "プレーンテキスト", input: "  議題:  進捗確認 \n\n\n\n資料あり ", want: "議題: 進捗確認\n\n資料あり"},
+		{name: "改行タグ", input: "1行目<br>2行目<br/>3行目", want: "1行目\n2行目\n3行目"},
+		{name: "ブロック要素とリスト", input: "<p>議題</p><ul><li>進捗</li><li>課題</li></ul>", want: "議題\n\n進捗\n\n課題"},
+		{name: "文字参照", input: "A &amp; B &lt;tag&gt; &quot;quoted&quot;&nbsp;end", want: `A & B <tag> "quoted" end`},
+		{name: "リンク", input: `参加は<a href="https://zoom.us/j/123">こちら</a>から`, want: "参加はこちら (https://zoom.us/j/123)から"},
+		{name: "URLがそのまま表示されるリンク", input: `<a href="https://example.com">https://example.com</a>`, want: "https://example.com"},
+		{name: "scriptとstyleは除去", input: "<style>p{color:red}</style>本文<script>alert(1)</script>", want: "本文"},
+		{name: "装飾タグ", input: "<b>重要</b>: <i>必ず</i>参加", want: "重要: 必ず参加"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HTMLToText(tt.input))
+		})
+	}
+}
+
+func TestExtractURLs(t *testing.T) {
+	text := "Zoom: https://zoom.us/j/123?pwd=abc, 資料（https://docs.google.com/document/d/xyz）。\n再掲 https://zoom.us/j/123?pwd=abc."
+
+	assert.Equal(t, []string{
+		"https://zoom.us/j/123?pwd=abc",
+		"https://docs.google.com/document/d/xyz",
+	}, ExtractURLs(text))
+	assert.Empty(t, ExtractURLs("URLなし"))
+}
+
+func TestStripEmoji(t *testing.T) {
+	assert.Equal(t, "誕生日会 ", StripEmoji("誕生日会 🎂"))
+	assert.Equal(t, "出張", StripEmoji("✈️出張"))
+	assert.Equal(t, "家族で外出", StripEmoji("👨‍👩‍👧家族で外出"))
+	assert.Equal(t, "時間: 会議 〜 12:00 ", StripEmoji("⏱時間: 会議 〜 12:00 ★⭐"))
+}
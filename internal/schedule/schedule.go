@@ -0,0 +1,53 @@
+// Package schedule は名前付きの表示テンプレート（フィーチャーフラグのプリセット）を定義する
+// 実行するスケジュール（EventBridgeのルール）の入力定数でscheduleに名前を指定すると、その実行にプリセットのフラグを適用する
+// スケジュールごとのルール（cron式と入力定数）はtemplate.yamlで定義する
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Schedule 名前付きの表示テンプレート
+type Schedule struct {
+	// Name ペイロードのscheduleで指定する名前
+	Name string `json:"name"`
+	// Flags このスケジュールで有効にするフィーチャーフラグ（"-"を付けると無効）
+	Flags []string `json:"flags,omitempty"`
+}
+
+// Parse [{"name":"morning"},{"name":"evening","flags":["plain_text"]}]
+// 形式のJSONからスケジュールを解析する
+func Parse(s string) ([]Schedule, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var schedules []Schedule
+	if err := json.Unmarshal([]byte(s), &schedules); err != nil {
+		return nil, fmt.Errorf("スケジュールのJSON解析に失敗しました: %v", err)
+	}
+
+	seen := make(map[string]bool, len(schedules))
+	for _, schedule := range schedules {
+		if schedule.Name == "" {
+			return nil, fmt.Errorf("スケジュールの名前が指定されていません")
+		}
+		if seen[schedule.Name] {
+			return nil, fmt.Errorf("スケジュールの名前が重複しています: %s", schedule.Name)
+		}
+		seen[schedule.Name] = true
+	}
+	return schedules, nil
+}
+
+// Find 名前でスケジュールを探す
+func Find(schedules []Schedule, name string) (Schedule, bool) {
+	for _, schedule := range schedules {
+		if schedule.Name == name {
+			return schedule, true
+		}
+	}
+	return Schedule{}, false
+}
@@ -0,0 +1,38 @@
+package schedule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	schedules, err := Parse(`[{"name":"morning"},{"name":"evening","flags":["plain_text"]}]`)
+	require.NoError(t, err)
+	require.Len(t, schedules, 2)
+
+	evening, ok := Find(schedules, "evening")
+	require.True(t, ok)
+	assert.Equal(t, []string{"plain_text"}, evening.Flags)
+
+	_, ok = Find(schedules, "noon")
+	assert.False(t, ok)
+
+	schedules, err = Parse("")
+	require.NoError(t, err)
+	assert.Empty(t, schedules)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for name, input := range map[string]string{
+		"JSONでない": `{`,
+		"名前なし":    `[{"flags":["plain_text"]}]`,
+		"名前の重複":   `[{"name":"a"},{"name":"a","flags":["plain_text"]}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(input)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Package scoring は予定の重要度スコアを算出する
+package scoring
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/usecase"
+)
+
+const (
+	// keywordWeight タイトルに重要キーワードを含む場合の加点
+	keywordWeight = 3
+	// organizerWeight 重要な主催者による予定の加点
+	organizerWeight = 3
+	// largeMeetingAttendees この人数以上の参加者がいる予定を大人数の会議とみなす
+	largeMeetingAttendees = 10
+	// meetingAttendees この人数以上の参加者がいる予定を会議とみなす
+	meetingAttendees = 3
+	// longEventDuration この時間以上の時間指定予定を長時間の予定とみなす
+	longEventDuration = time.Hour
+)
+
+// Scorer キーワード・参加者数・主催者・所要時間から予定の重要度を算出する
+type Scorer struct {
+	keywords   []string
+	organizers map[string]struct{}
+}
+
+// NewScorer 重要キーワードと重要な主催者のメールアドレスを指定してScorerを作成
+// キーワードは大文字小文字を区別せずタイトルとの部分一致で判定する
+func NewScorer(keywords, organizers []string) *Scorer {
+	s := &Scorer{organizers: make(map[string]struct{}, len(organizers))}
+	for _, keyword := range keywords {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+			s.keywords = append(s.keywords, keyword)
+		}
+	}
+	for _, organizer := range organizers {
+		if organizer = strings.ToLower(strings.TrimSpace(organizer)); organizer != "" {
+			s.organizers[organizer] = struct{}{}
+		}
+	}
+	return s
+}
+
+// Score 予定の重要度スコアを返す
+func (s *Scorer) Score(event domain.Event) int {
+	score := 0
+
+	title := strings.ToLower(event.Title)
+	for _, keyword := range s.keywords {
+		if strings.Contains(title, keyword) {
+			score += keywordWeight
+			break
+		}
+	}
+
+	if _, ok := s.organizers[strings.ToLower(event.Organizer)]; ok {
+		score += organizerWeight
+	}
+
+	switch {
+	case event.AttendeeCount >= largeMeetingAttendees:
+		score += 2
+	case event.AttendeeCount >= meetingAttendees:
+		score++
+	}
+
+	if !event.IsAllDay && event.EndTime.Sub(event.StartTime) >= longEventDuration {
+		score++
+	}
+
+	return score
+}
+
+// Apply 各予定のImportanceにスコアを設定する
+func (s *Scorer) Apply(events []domain.Event) {
+	for i := range events {
+		events[i].Importance = s.Score(events[i])
+	}
+}
+
+// Repository 取得した予定に重要度を設定するCalendarRepositoryのデコレーター
+type Repository struct {
+	next   usecase.CalendarRepository
+	scorer *Scorer
+}
+
+// NewRepository nextから取得した予定にscorerで重要度を設定するRepositoryを作成
+func NewRepository(next usecase.CalendarRepository, scorer *Scorer) *Repository {
+	return &Repository{next: next, scorer: scorer}
+}
+
+// GetEvents 指定日のイベントを取得し、重要度を設定して返す
+func (r *Repository) GetEvents(ctx context.Context, targetDate time.Time) ([]domain.Event, error) {
+	events, err := r.next.GetEvents(ctx, targetDate)
+	if err != nil {
+		return nil, err
+	}
+	r.scorer.Apply(events)
+	return events, nil
+}
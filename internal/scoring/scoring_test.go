@@ -0,0 +1,58 @@
+package scoring
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/fakes"
+)
+
+func TestScorer_Score(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	scorer := NewScorer([]string{"面接", " Review "}, []string{"Boss@example.com"})
+
+	tests := []struct {
+		name  string
+		event domain.Event
+		want  int
+	}{
+		{name: "短い個人予定", event: domain.Event{Title: "歯医者", StartTime: start, EndTime: start.Add(30 * time.Minute)}, want: 0},
+		{name: "キーワード一致", event: domain.Event{Title: "最終面接", StartTime: start, EndTime: start.Add(30 * time.Minute)}, want: 3},
+		{name: "キーワードは大文字小文字を区別しない", event: domain.Event{Title: "Design REVIEW", StartTime: start, EndTime: start.Add(30 * time.Minute)}, want: 3},
+		{name: "重要な主催者", event: domain.Event{Title: "1on1", Organizer: "boss@example.com", StartTime: start, EndTime: start.Add(30 * time.Minute)}, want: 3},
+		{name: "会議", event: domain.Event{Title: "定例", AttendeeCount: 4, StartTime: start, EndTime: start.Add(30 * time.Minute)}, want: 1},
+		{name: "大人数で長時間", event: domain.Event{Title: "全社会議", AttendeeCount: 50, StartTime: start, EndTime: start.Add(2 * time.Hour)}, want: 3},
+		{name: "終日予定は所要時間で加点しない", event: domain.Event{Title: "出張", IsAllDay: true, StartTime: start, EndTime: start.Add(24 * time.Hour)}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, scorer.Score(tt.event))
+		})
+	}
+}
+
+func TestRepository_GetEvents(t *testing.T) {
+	repo := NewRepository(fakes.CalendarRepository{Events: []domain.Event{
+		{Title: "面接"},
+		{Title: "ランチ"},
+	}}, NewScorer([]string{"面接"}, nil))
+
+	events, err := repo.GetEvents(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 3, events[0].Importance)
+	assert.Equal(t, 0, events[1].Importance)
+}
+
+func TestRepository_GetEventsError(t *testing.T) {
+	repo := NewRepository(fakes.CalendarRepository{Err: errors.New("boom")}, NewScorer(nil, nil))
+
+	_, err := repo.GetEvents(context.Background(), time.Now())
+	assert.Error(t, err)
+}
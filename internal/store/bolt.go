@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+)
+
+// boltBucket 値を保存するbboltのバケット名
+var boltBucket = []byte("store")
+
+// boltExpiryBytes 値の先頭に付与する有効期限（Unix秒）のバイト数
+const boltExpiryBytes = 8
+
+// BoltStore ローカルのbboltファイルに保存するストア（セルフホスト用）
+type BoltStore struct {
+	db    *bolt.DB
+	clock clock.Clock
+}
+
+// OpenBoltStore 指定したパスのbboltファイルを開いてBoltStoreを作成
+func OpenBoltStore(path string, clk clock.Clock) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("ストアファイルを開けませんでした: %v", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ストアの初期化に失敗しました: %v", err)
+	}
+
+	return &BoltStore{db: db, clock: clk}, nil
+}
+
+// Close ストアファイルを閉じる
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// Get キーに対応する値を取得
+func (s *BoltStore) Get(_ context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltBucket).Get([]byte(key))
+		if len(data) < boltExpiryBytes {
+			return ErrNotFound
+		}
+
+		var expiry time.Time
+		if seconds := int64(binary.BigEndian.Uint64(data[:boltExpiryBytes])); seconds != 0 {
+			expiry = time.Unix(seconds, 0)
+		}
+		if expired(expiry, s.clock.Now()) {
+			return ErrNotFound
+		}
+
+		// トランザクション外では参照できないためコピーする
+		value = append([]byte(nil), data[boltExpiryBytes:]...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Put 値を保存
+func (s *BoltStore) Put(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	data := make([]byte, boltExpiryBytes+len(value))
+	if expiry := expiresAt(s.clock.Now(), ttl); !expiry.IsZero() {
+		binary.BigEndian.PutUint64(data[:boltExpiryBytes], uint64(expiry.Unix()))
+	}
+	copy(data[boltExpiryBytes:], value)
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(key), data)
+	})
+	if err != nil {
+		return fmt.Errorf("ストアへの保存に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// Delete キーを削除
+func (s *BoltStore) Delete(_ context.Context, key string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete([]byte(key))
+	})
+	if err != nil {
+		return fmt.Errorf("ストアからの削除に失敗しました: %v", err)
+	}
+	return nil
+}
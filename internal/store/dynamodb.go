@@ -0,0 +1,110 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+)
+
+// DynamoDBテーブルの属性名
+// テーブルはパーティションキーpk（文字列）のみを持ち、expiresAtをTTL属性に設定する
+const (
+	dynamoKeyAttr    = "pk"
+	dynamoValueAttr  = "value"
+	dynamoExpiryAttr = "expiresAt"
+)
+
+// DynamoDBAPI は DynamoDB のアイテム操作を抽象化する
+type DynamoDBAPI interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+}
+
+// DynamoDBStore DynamoDBテーブルに保存するストア（Lambda実行用）
+type DynamoDBStore struct {
+	client DynamoDBAPI
+	table  string
+	clock  clock.Clock
+}
+
+// NewDynamoDBStore テーブル名を指定してDynamoDBStoreを作成
+func NewDynamoDBStore(client DynamoDBAPI, table string, clk clock.Clock) *DynamoDBStore {
+	return &DynamoDBStore{client: client, table: table, clock: clk}
+}
+
+// Get キーに対応する値を取得
+// DynamoDBのTTLによる削除は遅延するため、期限切れのアイテムも存在しないものとして扱う
+func (s *DynamoDBStore) Get(ctx context.Context, key string) ([]byte, error) {
+	out, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.table),
+		Key:            dynamoKey(key),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("DynamoDBからの取得に失敗しました: %v", err)
+	}
+	if out.Item == nil {
+		return nil, ErrNotFound
+	}
+
+	if attr, ok := out.Item[dynamoExpiryAttr].(*types.AttributeValueMemberN); ok {
+		seconds, err := strconv.ParseInt(attr.Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("DynamoDBの有効期限の値が不正です: %v", err)
+		}
+		if expired(time.Unix(seconds, 0), s.clock.Now()) {
+			return nil, ErrNotFound
+		}
+	}
+
+	value, ok := out.Item[dynamoValueAttr].(*types.AttributeValueMemberB)
+	if !ok {
+		return nil, fmt.Errorf("DynamoDBのアイテムに%s属性がありません", dynamoValueAttr)
+	}
+	return value.Value, nil
+}
+
+// Put 値を保存
+func (s *DynamoDBStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	item := dynamoKey(key)
+	item[dynamoValueAttr] = &types.AttributeValueMemberB{Value: value}
+	if expiry := expiresAt(s.clock.Now(), ttl); !expiry.IsZero() {
+		item[dynamoExpiryAttr] = &types.AttributeValueMemberN{Value: strconv.FormatInt(expiry.Unix(), 10)}
+	}
+
+	_, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.table),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("DynamoDBへの保存に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// Delete キーを削除
+func (s *DynamoDBStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.table),
+		Key:       dynamoKey(key),
+	})
+	if err != nil {
+		return fmt.Errorf("DynamoDBからの削除に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// dynamoKey キーをDynamoDBのキー属性に変換
+func dynamoKey(key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		dynamoKeyAttr: &types.AttributeValueMemberS{Value: key},
+	}
+}
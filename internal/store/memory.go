@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+)
+
+// memoryEntry メモリ上に保存する値
+type memoryEntry struct {
+	value  []byte
+	expiry time.Time
+}
+
+// MemoryStore プロセス内のメモリに保存するストア（テストやローカル実行用）
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	clock   clock.Clock
+}
+
+// NewMemoryStore MemoryStoreを作成
+func NewMemoryStore(clk clock.Clock) *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), clock: clk}
+}
+
+// Get キーに対応する値を取得
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if expired(entry.expiry, s.clock.Now()) {
+		delete(s.entries, key)
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), entry.value...), nil
+}
+
+// Put 値を保存
+func (s *MemoryStore) Put(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = memoryEntry{
+		value:  append([]byte(nil), value...),
+		expiry: expiresAt(s.clock.Now(), ttl),
+	}
+	return nil
+}
+
+// Delete キーを削除
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
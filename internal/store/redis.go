@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisAPI は Redis のキー操作を抽象化する
+type RedisAPI interface {
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+}
+
+// RedisStore Redis（ElastiCache）に保存するストア
+// 高頻度の実行でDynamoDBの代わりに使う。有効期限はRedisのTTLに任せる
+type RedisStore struct {
+	client RedisAPI
+}
+
+// NewRedisStore RedisStoreを作成
+func NewRedisStore(client RedisAPI) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// OpenRedisStore 接続URL（redis:// または TLSの場合は rediss://）からRedisStoreを作成
+func OpenRedisStore(url string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("RedisのURLが不正です: %v", err)
+	}
+	return NewRedisStore(redis.NewClient(opts)), nil
+}
+
+// Get キーに対応する値を取得
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Redisからの取得に失敗しました: %v", err)
+	}
+	return value, nil
+}
+
+// Put 値を保存
+func (s *RedisStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl < 0 {
+		ttl = 0
+	}
+	if err := s.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("Redisへの保存に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// Delete キーを削除
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("Redisからの削除に失敗しました: %v", err)
+	}
+	return nil
+}
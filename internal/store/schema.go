@@ -0,0 +1,97 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Migration あるバージョンの値を次のバージョンの値に変換する
+type Migration func(data []byte) ([]byte, error)
+
+// Schema ストアに保存する値のスキーマのバージョンと移行手順
+// 値は {"schemaVersion":N,"data":...} の形式で保存する
+// バージョンを持たない値（バージョン管理を始める前に保存されたもの）はバージョン1として扱う
+type Schema struct {
+	// Name ログやエラーに表示するスキーマ名
+	Name string
+	// Version 現在のバージョン（1以上）
+	Version int
+	// Migrations バージョンvの値をv+1に変換する手順（キーは変換元のバージョン）
+	Migrations map[int]Migration
+}
+
+// envelope バージョン付きで保存する値
+type envelope struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// Encode 値を現在のバージョンで保存する形式に変換
+func (s Schema) Encode(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("%sのJSON変換に失敗しました: %v", s.Name, err)
+	}
+	return json.Marshal(envelope{SchemaVersion: s.Version, Data: data})
+}
+
+// Decode 保存された値を現在のバージョンに移行してからvに読み込む
+// 古いバージョンから移行した場合はmigratedがtrueになる（呼び出し元で保存し直すと次回以降の移行を省ける）
+// 現在より新しいバージョンの値は上書きで失われないようエラーにする
+func (s Schema) Decode(raw []byte, v any) (migrated bool, err error) {
+	version, data := 1, raw
+	var env envelope
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &env); err == nil && env.SchemaVersion > 0 {
+			version, data = env.SchemaVersion, env.Data
+		}
+	}
+
+	if version > s.Version {
+		return false, fmt.Errorf("%sのバージョン%dには対応していません（対応しているのはバージョン%dまで）", s.Name, version, s.Version)
+	}
+	for ; version < s.Version; version++ {
+		migrate, ok := s.Migrations[version]
+		if !ok {
+			return false, fmt.Errorf("%sをバージョン%dから%dに移行する手順がありません", s.Name, version, version+1)
+		}
+		if data, err = migrate(data); err != nil {
+			return false, fmt.Errorf("%sをバージョン%dから%dに移行できませんでした: %v", s.Name, version, version+1, err)
+		}
+		migrated = true
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("%sの内容を解析できませんでした: %v", s.Name, err)
+	}
+	return migrated, nil
+}
+
+// MigrateKey キーに保存された値を現在のバージョンに移行して保存し直す
+// 値が存在しない場合や、すでに現在のバージョンの場合は何もしない
+func MigrateKey(ctx context.Context, s Store, key string, schema Schema, ttl time.Duration) (migrated bool, err error) {
+	raw, err := s.Get(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var data json.RawMessage
+	if migrated, err = schema.Decode(raw, &data); err != nil || !migrated {
+		return false, err
+	}
+	encoded, err := schema.Encode(data)
+	if err != nil {
+		return false, err
+	}
+	if err := s.Put(ctx, key, encoded, ttl); err != nil {
+		return false, err
+	}
+	return true, nil
+}
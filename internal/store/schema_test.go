@@ -0,0 +1,88 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+)
+
+// testSchema バージョン1の {"name":...} をバージョン2の {"title":...} に変更したスキーマ
+var testSchema = Schema{
+	Name:    "テスト",
+	Version: 2,
+	Migrations: map[int]Migration{
+		1: func(data []byte) ([]byte, error) {
+			return bytes.Replace(data, []byte(`"name"`), []byte(`"title"`), 1), nil
+		},
+	},
+}
+
+type testRecord struct {
+	Title string `json:"title"`
+}
+
+func TestSchema_EncodeDecode(t *testing.T) {
+	data, err := testSchema.Encode(testRecord{Title: "朝会"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"schemaVersion":2,"data":{"title":"朝会"}}`, string(data))
+
+	var record testRecord
+	migrated, err := testSchema.Decode(data, &record)
+	require.NoError(t, err)
+	assert.False(t, migrated)
+	assert.Equal(t, "朝会", record.Title)
+}
+
+func TestSchema_DecodeMigrates(t *testing.T) {
+	for name, data := range map[string]string{
+		"バージョンなし": `{"name":"朝会"}`,
+		"バージョン1":  `{"schemaVersion":1,"data":{"name":"朝会"}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var record testRecord
+			migrated, err := testSchema.Decode([]byte(data), &record)
+			require.NoError(t, err)
+			assert.True(t, migrated)
+			assert.Equal(t, "朝会", record.Title)
+		})
+	}
+}
+
+func TestSchema_DecodeErrors(t *testing.T) {
+	var record testRecord
+	_, err := testSchema.Decode([]byte(`{"schemaVersion":3,"data":{}}`), &record)
+	assert.ErrorContains(t, err, "バージョン3には対応していません")
+
+	missing := Schema{Name: "テスト", Version: 3}
+	_, err = missing.Decode([]byte(`{"schemaVersion":2,"data":{}}`), &record)
+	assert.ErrorContains(t, err, "移行する手順がありません")
+}
+
+func TestMigrateKey(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(clock.Fixed(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)))
+	key := Key(NamespaceHistory, "U123")
+
+	migrated, err := MigrateKey(ctx, s, key, testSchema, 0)
+	require.NoError(t, err)
+	assert.False(t, migrated, "存在しないキーは何もしない")
+
+	require.NoError(t, s.Put(ctx, key, []byte(`{"name":"朝会"}`), 0))
+	migrated, err = MigrateKey(ctx, s, key, testSchema, 0)
+	require.NoError(t, err)
+	assert.True(t, migrated)
+
+	data, err := s.Get(ctx, key)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"schemaVersion":2,"data":{"title":"朝会"}}`, string(data))
+
+	migrated, err = MigrateKey(ctx, s, key, testSchema, 0)
+	require.NoError(t, err)
+	assert.False(t, migrated, "移行済みの値はそのまま")
+}
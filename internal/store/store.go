@@ -0,0 +1,51 @@
+// Package store は冪等性マーカー、同期トークン、ミュート状態、送信履歴などの
+// 小さな状態を保存するキーバリューストアを提供する
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotFound 指定したキーが存在しない（または期限切れの）場合のエラー
+var ErrNotFound = errors.New("store: key not found")
+
+// 用途ごとのキーの名前空間
+const (
+	NamespaceSyncToken = "synctoken"
+	NamespaceHistory   = "history"
+	NamespaceDedup     = "dedup"
+	NamespaceOutbox    = "outbox"
+	NamespaceAudit     = "audit"
+	NamespaceMirror    = "mirror"
+)
+
+// Store 状態を保存するキーバリューストア
+type Store interface {
+	// Get キーに対応する値を取得する。存在しない場合はErrNotFoundを返す
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put 値を保存する。ttlが0の場合は期限なし
+	Put(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete キーを削除する。存在しない場合もエラーにしない
+	Delete(ctx context.Context, key string) error
+}
+
+// Key 名前空間とIDからキーを作成
+func Key(namespace, id string) string {
+	return fmt.Sprintf("%s:%s", namespace, id)
+}
+
+// expiresAt 保存時刻とttlから有効期限を算出（ttlが0の場合はゼロ値）
+func expiresAt(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return now.Add(ttl)
+}
+
+// expired 有効期限を過ぎているかを判定
+func expired(expiry, now time.Time) bool {
+	return !expiry.IsZero() && !now.Before(expiry)
+}
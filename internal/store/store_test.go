@@ -0,0 +1,161 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+)
+
+// fakeDynamoDB テーブルの内容をメモリ上に保持するDynamoDBのフェイク
+type fakeDynamoDB struct {
+	mu    sync.Mutex
+	items map[string]map[string]types.AttributeValue
+}
+
+func newFakeDynamoDB() *fakeDynamoDB {
+	return &fakeDynamoDB{items: make(map[string]map[string]types.AttributeValue)}
+}
+
+func fakeDynamoKey(key map[string]types.AttributeValue) string {
+	return key[dynamoKeyAttr].(*types.AttributeValueMemberS).Value
+}
+
+func (f *fakeDynamoDB) GetItem(_ context.Context, params *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &dynamodb.GetItemOutput{Item: f.items[fakeDynamoKey(params.Key)]}, nil
+}
+
+func (f *fakeDynamoDB) PutItem(_ context.Context, params *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items[fakeDynamoKey(params.Item)] = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeDynamoDB) DeleteItem(_ context.Context, params *dynamodb.DeleteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.items, fakeDynamoKey(params.Key))
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+// fakeRedis キーと有効期限をメモリ上に保持するRedisのフェイク
+type fakeRedis struct {
+	values map[string][]byte
+	ttls   map[string]time.Duration
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{values: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (f *fakeRedis) Get(ctx context.Context, key string) *redis.StringCmd {
+	value, ok := f.values[key]
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	return redis.NewStringResult(string(value), nil)
+}
+
+func (f *fakeRedis) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+	f.values[key] = value.([]byte)
+	f.ttls[key] = expiration
+	return redis.NewStatusResult("OK", nil)
+}
+
+func (f *fakeRedis) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	for _, key := range keys {
+		delete(f.values, key)
+	}
+	return redis.NewIntResult(int64(len(keys)), nil)
+}
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeRedis()
+	s := NewRedisStore(client)
+
+	_, err := s.Get(ctx, Key(NamespaceDedup, "ev1"))
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, s.Put(ctx, Key(NamespaceDedup, "ev1"), []byte("1"), 10*time.Minute))
+	assert.Equal(t, 10*time.Minute, client.ttls["dedup:ev1"])
+
+	value, err := s.Get(ctx, Key(NamespaceDedup, "ev1"))
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(value))
+
+	require.NoError(t, s.Delete(ctx, Key(NamespaceDedup, "ev1")))
+	_, err = s.Get(ctx, Key(NamespaceDedup, "ev1"))
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestOpenRedisStore_InvalidURL(t *testing.T) {
+	_, err := OpenRedisStore("http://localhost:6379")
+	assert.ErrorContains(t, err, "RedisのURLが不正です")
+}
+
+func TestStores(t *testing.T) {
+	backends := map[string]func(t *testing.T, clk clock.Clock) Store{
+		"memory": func(_ *testing.T, clk clock.Clock) Store {
+			return NewMemoryStore(clk)
+		},
+		"dynamodb": func(_ *testing.T, clk clock.Clock) Store {
+			return NewDynamoDBStore(newFakeDynamoDB(), "notifier-state", clk)
+		},
+		"bolt": func(t *testing.T, clk clock.Clock) Store {
+			s, err := OpenBoltStore(filepath.Join(t.TempDir(), "state.db"), clk)
+			require.NoError(t, err)
+			t.Cleanup(func() { s.Close() })
+			return s
+		},
+	}
+
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Date(2024, 1, 15, 7, 0, 0, 0, time.UTC)
+			s := open(t, clock.Func(func() time.Time { return now }))
+
+			_, err := s.Get(ctx, Key(NamespaceSyncToken, "primary"))
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			require.NoError(t, s.Put(ctx, Key(NamespaceSyncToken, "primary"), []byte("on"), 0))
+			require.NoError(t, s.Put(ctx, Key(NamespaceDedup, "2024-01-15"), []byte("sent"), time.Hour))
+
+			value, err := s.Get(ctx, Key(NamespaceSyncToken, "primary"))
+			require.NoError(t, err)
+			assert.Equal(t, "on", string(value))
+
+			value, err = s.Get(ctx, Key(NamespaceDedup, "2024-01-15"))
+			require.NoError(t, err)
+			assert.Equal(t, "sent", string(value))
+
+			// 有効期限を過ぎた値は取得できない
+			now = now.Add(time.Hour)
+			_, err = s.Get(ctx, Key(NamespaceDedup, "2024-01-15"))
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			// 期限なしの値は残る
+			value, err = s.Get(ctx, Key(NamespaceSyncToken, "primary"))
+			require.NoError(t, err)
+			assert.Equal(t, "on", string(value))
+
+			require.NoError(t, s.Delete(ctx, Key(NamespaceSyncToken, "primary")))
+			require.NoError(t, s.Delete(ctx, Key(NamespaceSyncToken, "primary")))
+			_, err = s.Get(ctx, Key(NamespaceSyncToken, "primary"))
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package testutil
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update -update フラグ指定時はゴールデンファイルを実際の出力で更新する
+var update = flag.Bool("update", false, "ゴールデンファイルを更新する")
+
+// AssertGolden 出力がゴールデンファイルの内容と一致することを検証する
+// 対象パッケージで `go test -update` を実行するとゴールデンファイルを書き換える
+func AssertGolden(t testing.TB, path string, actual []byte) {
+	t.Helper()
+
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, actual, 0o644))
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err, "ゴールデンファイルがありません。-update フラグで生成してください: %s", path)
+	assert.Equal(t, string(expected), string(actual), "ゴールデンファイルと一致しません: %s", path)
+}
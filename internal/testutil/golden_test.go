@@ -0,0 +1,28 @@
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertGolden_Match(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "message.golden")
+	require.NoError(t, os.WriteFile(path, []byte("本日 1/15(月): 予定なし\n"), 0o644))
+
+	AssertGolden(t, path, []byte("本日 1/15(月): 予定なし\n"))
+}
+
+func TestAssertGolden_Update(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "message.golden")
+
+	*update = true
+	t.Cleanup(func() { *update = false })
+	AssertGolden(t, path, []byte("updated"))
+
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "updated", string(written))
+}
@@ -0,0 +1,173 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// RecordEnv 設定されている場合、Recorderは実APIへのレスポンスをフィクスチャに記録する
+const RecordEnv = "RECORD_FIXTURES"
+
+// Interaction 記録された1往復分のリクエストとレスポンス
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest 記録されたリクエスト（認証情報は含まない）
+type RecordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+}
+
+// RecordedResponse 記録されたレスポンス
+type RecordedResponse struct {
+	StatusCode int             `json:"status"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// cassette フィクスチャファイルの構造
+type cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder レスポンスをフィクスチャに記録・再生するRoundTripper
+//
+// 記録モードではPathPrefixに一致するリクエストのみを実APIに転送して記録し、
+// それ以外（OAuthトークン取得など）は記録せずに転送する。
+// 再生モードでは記録順にレスポンスを返し、メソッドとパスが一致しない場合はエラーにする。
+type Recorder struct {
+	path       string
+	pathPrefix string
+	base       http.RoundTripper
+	recording  bool
+
+	mu           sync.Mutex
+	interactions []Interaction
+	next         int
+}
+
+// NewRecorder フィクスチャファイルを指定してRecorderを作成
+// RecordEnvが設定されていれば記録モード、それ以外は既存のフィクスチャを読み込む再生モードになる
+func NewRecorder(path, pathPrefix string, base http.RoundTripper) (*Recorder, error) {
+	r := &Recorder{
+		path:       path,
+		pathPrefix: pathPrefix,
+		base:       base,
+		recording:  os.Getenv(RecordEnv) != "",
+	}
+	if r.recording {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("フィクスチャの読み込みに失敗しました: %v", err)
+	}
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("フィクスチャのJSON解析に失敗しました: %v", err)
+	}
+	r.interactions = c.Interactions
+	return r, nil
+}
+
+// Recording 記録モードかどうか
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// RoundTrip 記録モードでは転送して記録し、再生モードでは記録済みレスポンスを返す
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.recording {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+// record 実APIにリクエストを転送し、対象パスのレスポンスを記録
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err != nil || !strings.HasPrefix(req.URL.Path, r.pathPrefix) {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	recordedBody := json.RawMessage(body)
+	if !json.Valid(body) {
+		recordedBody, _ = json.Marshal(string(body))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  req.URL.RawQuery,
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Body:       recordedBody,
+		},
+	})
+	return resp, nil
+}
+
+// replay 記録済みのレスポンスを順番に返す
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.next >= len(r.interactions) {
+		return nil, fmt.Errorf("フィクスチャに記録されていないリクエストです: %s %s", req.Method, req.URL.Path)
+	}
+	interaction := r.interactions[r.next]
+	r.next++
+
+	if interaction.Request.Method != req.Method || interaction.Request.Path != req.URL.Path {
+		return nil, fmt.Errorf("フィクスチャと異なるリクエストです: 期待値 %s %s, 実際 %s %s",
+			interaction.Request.Method, interaction.Request.Path, req.Method, req.URL.Path)
+	}
+
+	return &http.Response{
+		StatusCode: interaction.Response.StatusCode,
+		Status:     fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		Header:     http.Header{"Content-Type": []string{"application/json; charset=UTF-8"}},
+		Body:       io.NopCloser(bytes.NewReader(interaction.Response.Body)),
+		Request:    req,
+	}, nil
+}
+
+// Save 記録モードの場合、記録したやり取りをフィクスチャファイルに書き出す
+func (r *Recorder) Save() error {
+	if !r.recording {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(cassette{Interactions: r.interactions}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("フィクスチャのJSON変換に失敗しました: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("フィクスチャディレクトリの作成に失敗しました: %v", err)
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
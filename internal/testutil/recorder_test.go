@@ -0,0 +1,75 @@
+package testutil
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+
+	// 記録
+	t.Setenv(RecordEnv, "1")
+	rec, err := NewRecorder(fixture, "/api/", http.DefaultTransport)
+	require.NoError(t, err)
+	client := &http.Client{Transport: rec}
+
+	resp, err := client.Get(server.URL + "/api/events")
+	require.NoError(t, err)
+	resp.Body.Close()
+	// 対象外のパスは記録されない
+	resp, err = client.Get(server.URL + "/token")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.NoError(t, rec.Save())
+
+	// 再生
+	t.Setenv(RecordEnv, "")
+	replay, err := NewRecorder(fixture, "/api/", nil)
+	require.NoError(t, err)
+	assert.False(t, replay.Recording())
+
+	resp, err = (&http.Client{Transport: replay}).Get("https://example.invalid/api/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"path":"/api/events"}`, string(body))
+
+	// 記録されていないリクエストはエラー
+	_, err = (&http.Client{Transport: replay}).Get("https://example.invalid/api/events")
+	assert.Error(t, err)
+}
+
+func TestRecorder_ReplayMismatch(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+	t.Setenv(RecordEnv, "1")
+	rec, err := NewRecorder(fixture, "/", nil)
+	require.NoError(t, err)
+	rec.interactions = []Interaction{{
+		Request:  RecordedRequest{Method: http.MethodGet, Path: "/expected"},
+		Response: RecordedResponse{StatusCode: http.StatusOK, Body: []byte(`{}`)},
+	}}
+	require.NoError(t, rec.Save())
+
+	t.Setenv(RecordEnv, "")
+	replay, err := NewRecorder(fixture, "/", nil)
+	require.NoError(t, err)
+
+	_, err = (&http.Client{Transport: replay}).Get("https://example.invalid/other")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "フィクスチャと異なるリクエストです")
+}
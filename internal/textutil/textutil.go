@@ -0,0 +1,106 @@
+// Package textutil はマルチバイト文字を考慮した文字列の切り詰めなどを提供する
+package textutil
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Ellipsis 切り詰めた文字列の末尾に付ける省略記号
+const Ellipsis = "…"
+
+// Truncate 文字列を最大maxLen文字（rune単位）に切り詰める
+// 切り詰めた場合は末尾を省略記号に置き換え、省略記号を含めてmaxLen文字に収める
+// maxLenが0以下の場合は切り詰めない
+func Truncate(s string, maxLen int) string {
+	if maxLen <= 0 || utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+
+	runes := []rune(s)
+	cut := maxLen - 1
+	// 結合文字の途中で切らないよう、直前の基底文字ごと落とす
+	for cut > 0 && isCombining(runes[cut]) {
+		cut--
+	}
+	return string(runes[:cut]) + Ellipsis
+}
+
+// DisplayWidth 等幅フォントでの表示幅を返す（全角文字は2、半角文字は1として数える）
+func DisplayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// TruncateWidth 表示幅がmaxWidth以下になるように切り詰める
+// 切り詰めた場合は末尾に省略記号（幅1）を付け、省略記号を含めてmaxWidthに収める
+// maxWidthが0以下の場合は切り詰めない
+func TruncateWidth(s string, maxWidth int) string {
+	if maxWidth <= 0 || DisplayWidth(s) <= maxWidth {
+		return s
+	}
+
+	runes := []rune(s)
+	width, cut := 0, 0
+	for cut < len(runes) {
+		w := runeWidth(runes[cut])
+		if width+w > maxWidth-1 {
+			break
+		}
+		width += w
+		cut++
+	}
+	for cut > 0 && cut < len(runes) && isCombining(runes[cut]) {
+		cut--
+	}
+	return string(runes[:cut]) + Ellipsis
+}
+
+// runeWidth 文字の表示幅
+func runeWidth(r rune) int {
+	switch {
+	case isCombining(r):
+		return 0
+	case isWide(r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// isCombining 直前の文字と組み合わせて表示される文字（濁点の結合文字・異体字セレクタなど）
+func isCombining(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me) || r == 0x200D || (r >= 0xFE00 && r <= 0xFE0F)
+}
+
+// isWide 全角で表示される文字かどうか
+func isWide(r rune) bool {
+	switch {
+	case r >= 0x1100 && r <= 0x115F: // ハングル字母
+		return true
+	case r >= 0x2E80 && r <= 0x303E: // CJK部首・記号
+		return true
+	case r >= 0x3041 && r <= 0x33FF: // ひらがな・カタカナ・CJK互換
+		return true
+	case r >= 0x3400 && r <= 0x4DBF: // CJK統合漢字拡張A
+		return true
+	case r >= 0x4E00 && r <= 0x9FFF: // CJK統合漢字
+		return true
+	case r >= 0xAC00 && r <= 0xD7A3: // ハングル音節
+		return true
+	case r >= 0xF900 && r <= 0xFAFF: // CJK互換漢字
+		return true
+	case r >= 0xFE30 && r <= 0xFE4F: // CJK互換形
+		return true
+	case r >= 0xFF00 && r <= 0xFF60, r >= 0xFFE0 && r <= 0xFFE6: // 全角英数・記号
+		return true
+	case r >= 0x1F300 && r <= 0x1FAFF: // 絵文字
+		return true
+	case r >= 0x20000 && r <= 0x3FFFD: // CJK統合漢字拡張B以降
+		return true
+	}
+	return false
+}
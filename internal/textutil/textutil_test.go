@@ -0,0 +1,48 @@
+package textutil
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		maxLen int
+		want   string
+	}{
+		{name: "上限以内", input: "定例会議", maxLen: 4, want: "定例会議"},
+		{name: "日本語を切り詰め", input: "プロジェクト定例会議", maxLen: 6, want: "プロジェク…"},
+		{name: "英数字", input: "Weekly sync", maxLen: 8, want: "Weekly …"},
+		{name: "上限0は切り詰めない", input: "プロジェクト定例会議", maxLen: 0, want: "プロジェクト定例会議"},
+		{name: "結合文字の手前で切る", input: "か\u3099き\u3099く\u3099", maxLen: 4, want: "か\u3099…"},
+		{name: "結合文字を含む文字はまとめて落とす", input: "か\u3099き\u3099く\u3099", maxLen: 2, want: "…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.input, tt.maxLen)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, utf8.ValidString(got))
+		})
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	assert.Equal(t, 4, DisplayWidth("abcd"))
+	assert.Equal(t, 8, DisplayWidth("定例会議"))
+	assert.Equal(t, 7, DisplayWidth("MTG会議"))
+	assert.Equal(t, 4, DisplayWidth("ＡＢ"))
+	assert.Equal(t, 2, DisplayWidth("か\u3099"))
+}
+
+func TestTruncateWidth(t *testing.T) {
+	assert.Equal(t, "定例会議", TruncateWidth("定例会議", 8))
+	assert.Equal(t, "定例会…", TruncateWidth("定例会議です", 8))
+	// 全角文字が収まらない場合は手前で切る
+	assert.Equal(t, "MTG…", TruncateWidth("MTG定例会議", 5))
+	assert.Equal(t, "MTG定例会議", TruncateWidth("MTG定例会議", 0))
+}
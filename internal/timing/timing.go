@@ -0,0 +1,101 @@
+// Package timing は1回の実行における処理段階ごとの所要時間を記録する
+// コールドスタートや並行取得の最適化の判断材料として、レスポンスとログに出力する
+package timing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stage 処理段階と所要時間
+type Stage struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Recorder 処理段階ごとの所要時間を記録する（複数のゴルーチンから利用可能）
+type Recorder struct {
+	mu     sync.Mutex
+	stages []Stage
+	now    func() time.Time
+}
+
+// NewRecorder Recorderを作成
+func NewRecorder() *Recorder {
+	return &Recorder{now: time.Now}
+}
+
+// contextKey contextに保存する際のキー
+type contextKey struct{}
+
+// WithRecorder Recorderを保存したcontextを返す
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+// FromContext contextに保存されたRecorderを返す（未設定の場合はnil）
+func FromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(contextKey{}).(*Recorder)
+	return r
+}
+
+// Start contextのRecorderで処理段階の計測を開始し、終了時に呼び出す関数を返す
+// Recorderが設定されていない場合は何もしない
+func Start(ctx context.Context, name string) func() {
+	return FromContext(ctx).Start(name)
+}
+
+// Start 処理段階の計測を開始し、終了時に呼び出す関数を返す
+func (r *Recorder) Start(name string) func() {
+	if r == nil {
+		return func() {}
+	}
+
+	r.mu.Lock()
+	index := len(r.stages)
+	r.stages = append(r.stages, Stage{Name: name})
+	started := r.now()
+	r.mu.Unlock()
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.stages[index].Duration = r.now().Sub(started)
+	}
+}
+
+// Stages 記録した処理段階を開始順に返す
+func (r *Recorder) Stages() []Stage {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Stage(nil), r.stages...)
+}
+
+// Milliseconds 処理段階ごとの所要時間（ミリ秒）を返す
+func (r *Recorder) Milliseconds() map[string]int64 {
+	stages := r.Stages()
+	if len(stages) == 0 {
+		return nil
+	}
+	result := make(map[string]int64, len(stages))
+	for _, stage := range stages {
+		result[stage.Name] += stage.Duration.Milliseconds()
+	}
+	return result
+}
+
+// String ログ出力用に「name=12ms」形式で処理段階を並べる
+func (r *Recorder) String() string {
+	stages := r.Stages()
+	parts := make([]string, len(stages))
+	for i, stage := range stages {
+		parts[i] = fmt.Sprintf("%s=%dms", stage.Name, stage.Duration.Milliseconds())
+	}
+	return strings.Join(parts, " ")
+}
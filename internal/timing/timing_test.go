@@ -0,0 +1,44 @@
+package timing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, time.UTC)
+	r := NewRecorder()
+	r.now = func() time.Time { return now }
+	ctx := WithRecorder(context.Background(), r)
+
+	stopConfig := Start(ctx, "config_load")
+	now = now.Add(120 * time.Millisecond)
+	stopConfig()
+
+	stopToday := Start(ctx, "fetch_today")
+	stopTomorrow := Start(ctx, "fetch_tomorrow")
+	now = now.Add(300 * time.Millisecond)
+	stopTomorrow()
+	now = now.Add(50 * time.Millisecond)
+	stopToday()
+
+	assert.Equal(t, []Stage{
+		{Name: "config_load", Duration: 120 * time.Millisecond},
+		{Name: "fetch_today", Duration: 350 * time.Millisecond},
+		{Name: "fetch_tomorrow", Duration: 300 * time.Millisecond},
+	}, r.Stages())
+	assert.Equal(t, map[string]int64{"config_load": 120, "fetch_today": 350, "fetch_tomorrow": 300}, r.Milliseconds())
+	assert.Equal(t, "config_load=120ms fetch_today=350ms fetch_tomorrow=300ms", r.String())
+}
+
+func TestStart_WithoutRecorder(t *testing.T) {
+	stop := Start(context.Background(), "send")
+	stop()
+
+	var r *Recorder
+	assert.Nil(t, r.Stages())
+	assert.Nil(t, r.Milliseconds())
+}
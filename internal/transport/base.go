@@ -0,0 +1,63 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// 接続の使い回しの設定
+// Lambdaの1回の実行で同じAPIホストへ続けて送るリクエスト（複数日・複数の送信先など）が
+// TLSハンドシェイクをやり直さないよう、ホストごとのアイドル接続を多めに保持する
+const (
+	// maxIdleConns 全体で保持するアイドル接続の上限
+	maxIdleConns = 64
+	// maxIdleConnsPerHost ホストごとに保持するアイドル接続の上限（http.Transportの既定は2）
+	maxIdleConnsPerHost = 16
+	// idleConnTimeout アイドル接続を保持する時間（Lambdaの実行環境が再利用される間隔を目安にする）
+	idleConnTimeout = 5 * time.Minute
+)
+
+// NewBase 追加CA証明書を反映した通信用のRoundTripperを作成
+// caBundlePathが指定された場合、システムの証明書に加えてPEM形式の証明書を信頼する。
+// http.DefaultTransportの設定（HTTPS_PROXY/HTTP_PROXY/NO_PROXYによるプロキシ・HTTP/2・gzipの自動展開）を引き継ぎ、
+// アイドル接続の保持数と保持時間のみ変更する。
+func NewBase(caBundlePath string) (http.RoundTripper, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.MaxIdleConns = maxIdleConns
+	base.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	base.IdleConnTimeout = idleConnTimeout
+
+	if caBundlePath != "" {
+		pool, err := loadCertPool(caBundlePath)
+		if err != nil {
+			return nil, err
+		}
+		base.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	return base, nil
+}
+
+// loadCertPool システムの証明書プールにCAバンドルの証明書を追加
+func loadCertPool(caBundlePath string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caBundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("CAバンドルの読み込みに失敗しました: %v", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CAバンドルに有効な証明書が含まれていません: %s", caBundlePath)
+	}
+	return pool, nil
+}
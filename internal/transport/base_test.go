@@ -0,0 +1,98 @@
+package transport
+
+import (
+	"compress/gzip"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCABundle TLSテストサーバーの証明書をPEMファイルに書き出すヘルパー
+func writeCABundle(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestNewBase_CustomCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// CAバンドルなしでは自己署名証明書を信頼しない
+	base, err := NewBase("")
+	require.NoError(t, err)
+	_, err = (&http.Client{Transport: base}).Get(server.URL)
+	assert.Error(t, err)
+
+	base, err = NewBase(writeCABundle(t, server))
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: base}).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestNewBase_InvalidCABundle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invalid.pem")
+	require.NoError(t, os.WriteFile(path, []byte("not a certificate"), 0o600))
+
+	_, err := NewBase(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "有効な証明書が含まれていません")
+
+	_, err = NewBase(filepath.Join(t.TempDir(), "missing.pem"))
+	assert.Error(t, err)
+}
+
+// プロキシ設定はhttp.DefaultTransportと同じくhttp.ProxyFromEnvironmentに任せる
+func TestNewBase_HonorsProxyEnvironment(t *testing.T) {
+	base, err := NewBase("")
+	require.NoError(t, err)
+	proxy := base.(*http.Transport).Proxy
+	require.NotNil(t, proxy)
+	assert.Equal(t, reflect.ValueOf(http.ProxyFromEnvironment).Pointer(), reflect.ValueOf(proxy).Pointer())
+}
+
+// CAバンドルを指定してTLSの設定を変えても、http.DefaultTransportのHTTP/2・gzipの自動展開が引き継がれる
+func TestNewBase_HTTP2AndCompression(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			w.Write([]byte("uncompressed"))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(r.Proto))
+		gz.Close()
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	base, err := NewBase(writeCABundle(t, server))
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: base}).Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.ProtoMajor)
+	assert.True(t, resp.Uncompressed)
+	assert.Equal(t, "HTTP/2.0", string(body))
+
+	transport := base.(*http.Transport)
+	assert.Equal(t, maxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+}
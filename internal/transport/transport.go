@@ -0,0 +1,85 @@
+package transport
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// DefaultUserAgent 外部API呼び出し時に送信するデフォルトのUser-Agent
+const DefaultUserAgent = "google-calendar-line-notifier"
+
+// RequestObserver HTTPリクエストの結果を受け取るメトリクス収集用のインターフェース
+type RequestObserver interface {
+	ObserveRequest(method, host string, statusCode int, duration time.Duration, err error)
+}
+
+// Options 共通RoundTripperの設定
+type Options struct {
+	// UserAgent 送信するUser-Agent（空の場合はDefaultUserAgent）
+	UserAgent string
+	// Debug trueの場合、リクエストごとにメソッド・URL・ステータス・所要時間をログ出力する
+	Debug bool
+	// Observer リクエスト結果の通知先（nilの場合は通知しない）
+	Observer RequestObserver
+	// Base 実際の通信を行うRoundTripper（nilの場合はhttp.DefaultTransport）
+	Base http.RoundTripper
+}
+
+// roundTripper User-Agentの付与とリクエストログ・メトリクス通知を行うRoundTripper
+type roundTripper struct {
+	userAgent string
+	debug     bool
+	observer  RequestObserver
+	base      http.RoundTripper
+}
+
+// New GoogleとLINEのクライアントで共通して使用するRoundTripperを作成
+func New(opts Options) http.RoundTripper {
+	rt := &roundTripper{
+		userAgent: opts.UserAgent,
+		debug:     opts.Debug,
+		observer:  opts.Observer,
+		base:      opts.Base,
+	}
+	if rt.userAgent == "" {
+		rt.userAgent = DefaultUserAgent
+	}
+	if rt.base == nil {
+		rt.base = http.DefaultTransport
+	}
+	return rt
+}
+
+// RoundTrip User-Agentを付与してリクエストを送信し、結果を記録する
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTripperは元のリクエストを変更してはならないため複製する
+	req = req.Clone(req.Context())
+	if ua := req.Header.Get("User-Agent"); ua != "" {
+		req.Header.Set("User-Agent", rt.userAgent+" "+ua)
+	} else {
+		req.Header.Set("User-Agent", rt.userAgent)
+	}
+
+	start := time.Now()
+	resp, err := rt.base.RoundTrip(req)
+	duration := time.Since(start)
+
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+
+	if rt.debug {
+		if err != nil {
+			log.Printf("DEBUG: HTTP %s %s failed (%s): %v", req.Method, req.URL.Redacted(), duration, err)
+		} else {
+			log.Printf("DEBUG: HTTP %s %s -> %d (%s)", req.Method, req.URL.Redacted(), statusCode, duration)
+		}
+	}
+	if rt.observer != nil {
+		rt.observer.ObserveRequest(req.Method, req.URL.Host, statusCode, duration, err)
+	}
+
+	return resp, err
+}
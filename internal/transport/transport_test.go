@@ -0,0 +1,116 @@
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingObserver は通知されたリクエスト結果を記録するテスト用Observer
+type recordingObserver struct {
+	mu          sync.Mutex
+	statusCodes []int
+	errs        []error
+}
+
+func (o *recordingObserver) ObserveRequest(_, _ string, statusCode int, _ time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.statusCodes = append(o.statusCodes, statusCode)
+	o.errs = append(o.errs, err)
+}
+
+// captureLog テスト中のログ出力を取得するヘルパー
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func TestRoundTrip_SetsUserAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(r.Header.Get("User-Agent")))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: New(Options{UserAgent: "notifier-test/1.0"})}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("User-Agent", "google-api-go-client/0.5")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body bytes.Buffer
+	_, err = body.ReadFrom(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "notifier-test/1.0 google-api-go-client/0.5", body.String())
+	// 元のリクエストは変更されない
+	assert.Equal(t, "google-api-go-client/0.5", req.Header.Get("User-Agent"))
+}
+
+func TestRoundTrip_DebugLogAndObserver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer server.Close()
+
+	buf := captureLog(t)
+	observer := &recordingObserver{}
+	client := &http.Client{Transport: New(Options{Debug: true, Observer: observer})}
+
+	resp, err := client.Get(server.URL + "/v2/bot/message/push")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Contains(t, buf.String(), "DEBUG: HTTP GET "+server.URL+"/v2/bot/message/push -> 418")
+	assert.Equal(t, []int{http.StatusTeapot}, observer.statusCodes)
+}
+
+func TestRoundTrip_NoLogWhenNotDebug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	buf := captureLog(t)
+	client := &http.Client{Transport: New(Options{})}
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Empty(t, buf.String())
+}
+
+// failingRoundTripper は常にエラーを返すテスト用RoundTripper
+type failingRoundTripper struct{}
+
+func (failingRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestRoundTrip_ObservesErrors(t *testing.T) {
+	observer := &recordingObserver{}
+	client := &http.Client{Transport: New(Options{Observer: observer, Base: failingRoundTripper{}})}
+
+	_, err := client.Get("https://example.invalid")
+	assert.Error(t, err)
+	require.Len(t, observer.errs, 1)
+	assert.Error(t, observer.errs[0])
+	assert.Equal(t, 0, observer.statusCodes[0])
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"strings"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+// EarlyWarning 出社日の早い時間帯の予定を警告する設定（Beforeが0の場合は警告しない）
+type EarlyWarning struct {
+	// Before この時刻（0時からの経過時間）より前に始まる予定を警告する
+	Before time.Duration
+	// OfficeKeywords 場所にこのキーワードを含む予定を出社の予定とみなす
+	OfficeKeywords []string
+}
+
+// warnings 本日の最初の予定が出社を伴い、かつ早い時間に始まる場合の警告を返す
+func (w EarlyWarning) warnings(events []domain.Event) []domain.Warning {
+	if w.Before <= 0 {
+		return nil
+	}
+
+	officeDay := false
+	var first *domain.Event
+	for i, event := range events {
+		if event.WorkingLocation == domain.WorkingLocationOffice {
+			officeDay = true
+		}
+		if event.IsAllDay || event.IsDeadline || event.IsFamily || event.WorkingLocation != "" {
+			continue
+		}
+		if first == nil || event.StartTime.Before(first.StartTime) {
+			first = &events[i]
+		}
+	}
+
+	if first == nil || dates.SinceStartOfDay(first.StartTime) >= w.Before || !w.inOffice(*first, officeDay) {
+		return nil
+	}
+	return []domain.Warning{{Kind: domain.WarningEarlyOffice, Event: *first}}
+}
+
+// inOffice 予定が出社を伴うかどうか
+// 場所がオフィスのキーワードに一致するか、勤務場所がオフィスの日でオンライン会議でない予定を出社とみなす
+func (w EarlyWarning) inOffice(event domain.Event, officeDay bool) bool {
+	for _, keyword := range w.OfficeKeywords {
+		if keyword != "" && strings.Contains(event.Location, keyword) {
+			return true
+		}
+	}
+	return officeDay && !event.IsOnline
+}
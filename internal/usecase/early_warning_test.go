@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+func TestEarlyWarning_Warnings(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 15, hour, minute, 0, 0, jst)
+	}
+	warning := EarlyWarning{Before: 9 * time.Hour, OfficeKeywords: []string{"本社"}}
+	officeDay := domain.Event{Title: "オフィス", IsAllDay: true, WorkingLocation: domain.WorkingLocationOffice}
+	review := domain.Event{Title: "設計レビュー", StartTime: at(8, 30), EndTime: at(9, 30), Location: "本社 5F"}
+	standup := domain.Event{Title: "朝会", StartTime: at(8, 45), EndTime: at(9, 0)}
+
+	tests := []struct {
+		name    string
+		warning EarlyWarning
+		events  []domain.Event
+		want    *domain.Event
+	}{
+		{
+			name:    "場所がオフィスのキーワードに一致",
+			warning: warning,
+			events:  []domain.Event{{Title: "定例", StartTime: at(10, 0), EndTime: at(11, 0)}, review},
+			want:    &review,
+		},
+		{
+			name:    "勤務場所がオフィスの日",
+			warning: warning,
+			events:  []domain.Event{officeDay, standup},
+			want:    &standup,
+		},
+		{
+			name:    "勤務場所がオフィスでもオンライン会議は対象外",
+			warning: warning,
+			events:  []domain.Event{officeDay, {Title: "朝会", StartTime: at(8, 45), EndTime: at(9, 0), IsOnline: true}},
+		},
+		{
+			name:    "最初の予定が閾値以降",
+			warning: warning,
+			events:  []domain.Event{{Title: "設計レビュー", StartTime: at(9, 0), EndTime: at(10, 0), Location: "本社"}},
+		},
+		{
+			name:    "在宅の日",
+			warning: warning,
+			events:  []domain.Event{{Title: "朝会", StartTime: at(8, 0), EndTime: at(8, 15), Location: "自宅"}},
+		},
+		{
+			name:    "家族の予定は対象外",
+			warning: warning,
+			events:  []domain.Event{{Title: "朝の送り", StartTime: at(7, 30), Location: "本社前", IsFamily: true}},
+		},
+		{
+			name:   "設定なし",
+			events: []domain.Event{{Title: "設計レビュー", StartTime: at(8, 0), EndTime: at(9, 0), Location: "本社"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.warning.warnings(tt.events)
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, []domain.Warning{{Kind: domain.WarningEarlyOffice, Event: *tt.want}}, got)
+		})
+	}
+}
+
+func TestExecute_EarlyWarning(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, jst)
+	today := time.Date(2024, 1, 15, 0, 0, 0, 0, jst)
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, jst)
+	review := domain.Event{Title: "設計レビュー", StartTime: today.Add(8*time.Hour + 30*time.Minute), EndTime: today.Add(9*time.Hour + 30*time.Minute), Location: "本社 5F"}
+
+	mockRepo := new(MockCalendarRepository)
+	mockRepo.On("GetEvents", mock.Anything, today).Return([]domain.Event{review}, nil)
+	mockRepo.On("GetEvents", mock.Anything, tomorrow).Return([]domain.Event{}, nil)
+	mockNotifier := new(MockNotifier)
+	var sent domain.ScheduleReport
+	mockNotifier.On("SendScheduleNotification", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sent = args.Get(1).(domain.ScheduleReport)
+	}).Return(nil)
+
+	// 警告はユースケースが判定し、レポートに含めて通知先に渡す
+	uc := NewNotifyScheduleUseCase(mockRepo, mockNotifier, clock.Fixed(now))
+	uc.SetEarlyWarning(EarlyWarning{Before: 9 * time.Hour, OfficeKeywords: []string{"本社"}})
+	_, err := uc.Execute(context.Background(), today, tomorrow)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Warning{{Kind: domain.WarningEarlyOffice, Event: review}}, sent.Warnings)
+}
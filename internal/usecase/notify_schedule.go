@@ -2,12 +2,21 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"time"
 
+	"golang.org/x/sync/errgroup"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
 	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/timing"
 )
 
+// maxConcurrentFetches 予定取得を同時に実行する日数の上限
+const maxConcurrentFetches = 4
+
 // CalendarRepository カレンダーからイベントを取得するポート
 type CalendarRepository interface {
 	GetEvents(ctx context.Context, targetDate time.Time) ([]domain.Event, error)
@@ -15,49 +24,133 @@ type CalendarRepository interface {
 
 // Notifier 通知を送信するポート
 type Notifier interface {
-	SendScheduleNotification(ctx context.Context, todayEvents, tomorrowEvents []domain.Event) error
+	SendScheduleNotification(ctx context.Context, report domain.ScheduleReport) error
 }
 
+// SkipReason 通知を送信しなかった理由（送信した場合は空）
+// レスポンスやメトリクスで、意図的に送信しなかった場合と失敗を区別するために使う
+type SkipReason string
+
+const (
+	// SkipReasonEmpty 両日とも予定がない
+	SkipReasonEmpty SkipReason = "empty"
+	// SkipReasonOutOfOffice 本日が不在の日のため通知を抑制した
+	SkipReasonOutOfOffice SkipReason = "out_of_office"
+)
+
 // NotifyScheduleUseCase 予定通知ユースケース
 type NotifyScheduleUseCase struct {
 	calendarRepo CalendarRepository
 	notifier     Notifier
+	clock        clock.Clock
+	outOfOffice  OutOfOffice
+	earlyWarning EarlyWarning
+	// notifyWhenEmpty trueの場合、両日とも予定がなくても通知する
+	notifyWhenEmpty bool
 }
 
 // NewNotifyScheduleUseCase ユースケースを生成
-func NewNotifyScheduleUseCase(calendarRepo CalendarRepository, notifier Notifier) *NotifyScheduleUseCase {
+func NewNotifyScheduleUseCase(calendarRepo CalendarRepository, notifier Notifier, clk clock.Clock) *NotifyScheduleUseCase {
 	return &NotifyScheduleUseCase{
 		calendarRepo: calendarRepo,
 		notifier:     notifier,
+		clock:        clk,
 	}
 }
 
+// SetOutOfOffice 本日が不在の日の通知の抑制方法を設定（未設定の場合は抑制しない）
+func (uc *NotifyScheduleUseCase) SetOutOfOffice(outOfOffice OutOfOffice) {
+	uc.outOfOffice = outOfOffice
+}
+
+// SetEarlyWarning 出社日の早い予定の警告を設定（未設定の場合は警告しない）
+func (uc *NotifyScheduleUseCase) SetEarlyWarning(earlyWarning EarlyWarning) {
+	uc.earlyWarning = earlyWarning
+}
+
+// SetNotifyWhenEmpty trueの場合、両日とも予定がない日も通知を送信する
+func (uc *NotifyScheduleUseCase) SetNotifyWhenEmpty(enabled bool) {
+	uc.notifyWhenEmpty = enabled
+}
+
+// Run 現在時刻から今日と明日を算出し、予定通知を実行する
+// 通知を送信しなかった場合はその理由を返す
+func (uc *NotifyScheduleUseCase) Run(ctx context.Context) (SkipReason, error) {
+	// 設定されたタイムゾーンで今日と明日の日付を計算
+	today := dates.Today(uc.clock.Now(), dates.Location())
+	tomorrow := dates.AddDays(today, 1)
+
+	return uc.Execute(ctx, today, tomorrow)
+}
+
 // Execute 今日と明日の予定を取得し、LINE通知を送信する
-func (uc *NotifyScheduleUseCase) Execute(ctx context.Context, today, tomorrow time.Time) (skipped bool, err error) {
-	// 今日の予定を取得
-	todayEvents, err := uc.calendarRepo.GetEvents(ctx, today)
+// 通知を送信しなかった場合はその理由を返す
+func (uc *NotifyScheduleUseCase) Execute(ctx context.Context, today, tomorrow time.Time) (SkipReason, error) {
+	// 今日と明日の予定を並行して取得
+	events, err := uc.fetchEvents(ctx, today, tomorrow)
 	if err != nil {
-		log.Printf("今日の予定取得に失敗しました: %v", err)
-		return false, err
+		return "", err
 	}
+	todayEvents := events[0]
 
-	// 明日の予定を取得
-	tomorrowEvents, err := uc.calendarRepo.GetEvents(ctx, tomorrow)
-	if err != nil {
-		log.Printf("明日の予定取得に失敗しました: %v", err)
-		return false, err
+	// 本日が不在の日は設定に従って通知を抑制する
+	if uc.outOfOffice.Mute != "" && uc.outOfOffice.Mute != OutOfOfficeMuteOff && uc.outOfOffice.coversDay(today, todayEvents) {
+		if uc.outOfOffice.Mute == OutOfOfficeMuteAll {
+			log.Printf("本日は不在の予定があるため通知を送信しません")
+			return SkipReasonOutOfOffice, nil
+		}
+		todayEvents = familyOnly(todayEvents)
 	}
+	report := domain.NewScheduleReport(uc.clock.Now(), today, tomorrow, todayEvents, events[1])
+	report.Warnings = uc.earlyWarning.warnings(todayEvents)
 
-	// 予定が両日ともない場合はスキップ
-	if len(todayEvents) == 0 && len(tomorrowEvents) == 0 {
-		return true, nil
+	// 予定が両日ともない場合はスキップ（予定がなくても通知する設定の場合を除く）
+	if report.IsEmpty() && !uc.notifyWhenEmpty {
+		return SkipReasonEmpty, nil
 	}
 
 	// LINE通知を送信
-	if err := uc.notifier.SendScheduleNotification(ctx, todayEvents, tomorrowEvents); err != nil {
+	if err := uc.notifier.SendScheduleNotification(ctx, report); err != nil {
 		log.Printf("LINE通知の送信に失敗しました: %v", err)
-		return false, err
+		return "", err
+	}
+
+	return "", nil
+}
+
+// fetchStageName 取得対象の順序に応じた計測段階名（3日目以降は日数で表す）
+func fetchStageName(index int) string {
+	switch index {
+	case 0:
+		return "fetch_today"
+	case 1:
+		return "fetch_tomorrow"
+	default:
+		return fmt.Sprintf("fetch_day%d", index)
+	}
+}
+
+// fetchEvents 指定された各日の予定を並行して取得し、引数と同じ順序で返す
+func (uc *NotifyScheduleUseCase) fetchEvents(ctx context.Context, dates ...time.Time) ([][]domain.Event, error) {
+	results := make([][]domain.Event, len(dates))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentFetches)
+	for i, date := range dates {
+		g.Go(func() error {
+			defer timing.Start(ctx, fetchStageName(i))()
+			events, err := uc.calendarRepo.GetEvents(gctx, date)
+			if err != nil {
+				log.Printf("%s の予定取得に失敗しました: %v", date.Format("2006-01-02"), err)
+				return err
+			}
+			results[i] = events
+			return nil
+		})
 	}
 
-	return false, nil
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
 }
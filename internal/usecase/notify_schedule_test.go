@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
 	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
 )
 
@@ -31,8 +33,8 @@ type MockNotifier struct {
 	mock.Mock
 }
 
-func (m *MockNotifier) SendScheduleNotification(ctx context.Context, todayEvents, tomorrowEvents []domain.Event) error {
-	args := m.Called(ctx, todayEvents, tomorrowEvents)
+func (m *MockNotifier) SendScheduleNotification(ctx context.Context, report domain.ScheduleReport) error {
+	args := m.Called(ctx, report)
 	return args.Error(0)
 }
 
@@ -41,11 +43,12 @@ func (m *MockNotifier) SendScheduleNotification(ctx context.Context, todayEvents
 func TestExecute_Success(t *testing.T) {
 	mockRepo := new(MockCalendarRepository)
 	mockNotifier := new(MockNotifier)
-	uc := NewNotifyScheduleUseCase(mockRepo, mockNotifier)
 
 	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, jst)
 	today := time.Date(2024, 1, 15, 0, 0, 0, 0, jst)
 	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, jst)
+	uc := NewNotifyScheduleUseCase(mockRepo, mockNotifier, clock.Fixed(now))
 
 	todayEvents := []domain.Event{
 		{Title: "朝会", StartTime: today.Add(9 * time.Hour), EndTime: today.Add(10 * time.Hour)},
@@ -56,11 +59,15 @@ func TestExecute_Success(t *testing.T) {
 
 	mockRepo.On("GetEvents", mock.Anything, today).Return(todayEvents, nil)
 	mockRepo.On("GetEvents", mock.Anything, tomorrow).Return(tomorrowEvents, nil)
-	mockNotifier.On("SendScheduleNotification", mock.Anything, todayEvents, tomorrowEvents).Return(nil)
+	mockNotifier.On("SendScheduleNotification", mock.Anything, domain.ScheduleReport{
+		GeneratedAt: now,
+		Today:       domain.DaySchedule{Date: today, Events: todayEvents},
+		Tomorrow:    domain.DaySchedule{Date: tomorrow, Events: tomorrowEvents},
+	}).Return(nil)
 
-	skipped, err := uc.Execute(context.Background(), today, tomorrow)
+	reason, err := uc.Execute(context.Background(), today, tomorrow)
 	require.NoError(t, err)
-	assert.False(t, skipped)
+	assert.Empty(t, reason)
 	mockRepo.AssertExpectations(t)
 	mockNotifier.AssertExpectations(t)
 }
@@ -68,7 +75,7 @@ func TestExecute_Success(t *testing.T) {
 func TestExecute_NoEvents_Skipped(t *testing.T) {
 	mockRepo := new(MockCalendarRepository)
 	mockNotifier := new(MockNotifier)
-	uc := NewNotifyScheduleUseCase(mockRepo, mockNotifier)
+	uc := NewNotifyScheduleUseCase(mockRepo, mockNotifier, clock.RealClock{})
 
 	jst := time.FixedZone("JST", 9*60*60)
 	today := time.Date(2024, 1, 15, 0, 0, 0, 0, jst)
@@ -77,23 +84,46 @@ func TestExecute_NoEvents_Skipped(t *testing.T) {
 	mockRepo.On("GetEvents", mock.Anything, today).Return([]domain.Event{}, nil)
 	mockRepo.On("GetEvents", mock.Anything, tomorrow).Return([]domain.Event{}, nil)
 
-	skipped, err := uc.Execute(context.Background(), today, tomorrow)
+	reason, err := uc.Execute(context.Background(), today, tomorrow)
 	require.NoError(t, err)
-	assert.True(t, skipped)
+	assert.Equal(t, SkipReasonEmpty, reason)
 	// 予定なしの場合 SendScheduleNotification は呼ばれない
 	mockNotifier.AssertNotCalled(t, "SendScheduleNotification")
 }
 
+func TestExecute_NoEvents_NotifyWhenEmpty(t *testing.T) {
+	mockRepo := new(MockCalendarRepository)
+	mockNotifier := new(MockNotifier)
+
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, jst)
+	today := time.Date(2024, 1, 15, 0, 0, 0, 0, jst)
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, jst)
+	uc := NewNotifyScheduleUseCase(mockRepo, mockNotifier, clock.Fixed(now))
+	uc.SetNotifyWhenEmpty(true)
+
+	mockRepo.On("GetEvents", mock.Anything, today).Return([]domain.Event{}, nil)
+	mockRepo.On("GetEvents", mock.Anything, tomorrow).Return([]domain.Event{}, nil)
+	mockNotifier.On("SendScheduleNotification", mock.Anything, mock.AnythingOfType("domain.ScheduleReport")).Return(nil)
+
+	reason, err := uc.Execute(context.Background(), today, tomorrow)
+	require.NoError(t, err)
+	assert.Empty(t, reason)
+	mockNotifier.AssertExpectations(t)
+}
+
 func TestExecute_CalendarError(t *testing.T) {
 	mockRepo := new(MockCalendarRepository)
 	mockNotifier := new(MockNotifier)
-	uc := NewNotifyScheduleUseCase(mockRepo, mockNotifier)
+	uc := NewNotifyScheduleUseCase(mockRepo, mockNotifier, clock.RealClock{})
 
 	jst := time.FixedZone("JST", 9*60*60)
 	today := time.Date(2024, 1, 15, 0, 0, 0, 0, jst)
 	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, jst)
 
 	mockRepo.On("GetEvents", mock.Anything, today).Return(nil, errors.New("calendar API error"))
+	// 並行取得のため明日の取得が呼ばれるかどうかはタイミング次第
+	mockRepo.On("GetEvents", mock.Anything, tomorrow).Return([]domain.Event{}, nil).Maybe()
 
 	_, err := uc.Execute(context.Background(), today, tomorrow)
 	assert.Error(t, err)
@@ -104,7 +134,7 @@ func TestExecute_CalendarError(t *testing.T) {
 func TestExecute_NotifierError(t *testing.T) {
 	mockRepo := new(MockCalendarRepository)
 	mockNotifier := new(MockNotifier)
-	uc := NewNotifyScheduleUseCase(mockRepo, mockNotifier)
+	uc := NewNotifyScheduleUseCase(mockRepo, mockNotifier, clock.RealClock{})
 
 	jst := time.FixedZone("JST", 9*60*60)
 	today := time.Date(2024, 1, 15, 0, 0, 0, 0, jst)
@@ -116,9 +146,105 @@ func TestExecute_NotifierError(t *testing.T) {
 
 	mockRepo.On("GetEvents", mock.Anything, today).Return(todayEvents, nil)
 	mockRepo.On("GetEvents", mock.Anything, tomorrow).Return([]domain.Event{}, nil)
-	mockNotifier.On("SendScheduleNotification", mock.Anything, todayEvents, []domain.Event{}).Return(errors.New("LINE API error"))
+	mockNotifier.On("SendScheduleNotification", mock.Anything, mock.Anything).Return(errors.New("LINE API error"))
 
 	_, err := uc.Execute(context.Background(), today, tomorrow)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "LINE API error")
 }
+
+func TestExecute_FetchesDaysConcurrently(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	today := time.Date(2024, 1, 15, 0, 0, 0, 0, jst)
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, jst)
+
+	// 両日の取得が同時に進行していないと解放されないバリア
+	var wg sync.WaitGroup
+	wg.Add(2)
+	repo := &barrierCalendarRepository{wg: &wg}
+	mockNotifier := new(MockNotifier)
+	mockNotifier.On("SendScheduleNotification", mock.Anything, mock.Anything).Return(nil)
+	uc := NewNotifyScheduleUseCase(repo, mockNotifier, clock.RealClock{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		reason, err := uc.Execute(context.Background(), today, tomorrow)
+		assert.NoError(t, err)
+		assert.Empty(t, reason)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("予定取得が並行に実行されていません")
+	}
+}
+
+// barrierCalendarRepository は全ての呼び出しが揃うまで応答を待つテスト用リポジトリ
+type barrierCalendarRepository struct {
+	wg *sync.WaitGroup
+}
+
+func (r *barrierCalendarRepository) GetEvents(_ context.Context, targetDate time.Time) ([]domain.Event, error) {
+	r.wg.Done()
+	r.wg.Wait()
+	return []domain.Event{{Title: targetDate.Format("1/2")}}, nil
+}
+
+func TestRun_UsesInjectedClock(t *testing.T) {
+	mockRepo := new(MockCalendarRepository)
+	mockNotifier := new(MockNotifier)
+
+	// UTCで前日15:00 = JSTで当日0:00
+	now := time.Date(2024, 1, 14, 15, 0, 0, 0, time.UTC)
+	uc := NewNotifyScheduleUseCase(mockRepo, mockNotifier, clock.Fixed(now))
+
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	today := time.Date(2024, 1, 15, 0, 0, 0, 0, jst)
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, jst)
+
+	mockRepo.On("GetEvents", mock.Anything, today).Return([]domain.Event{}, nil)
+	mockRepo.On("GetEvents", mock.Anything, tomorrow).Return([]domain.Event{}, nil)
+
+	reason, err := uc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, SkipReasonEmpty, reason)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRun_DateBoundaries(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		today    time.Time
+		tomorrow time.Time
+	}{
+		{"年末", time.Date(2024, 12, 31, 10, 0, 0, 0, jst), time.Date(2024, 12, 31, 0, 0, 0, 0, jst), time.Date(2025, 1, 1, 0, 0, 0, 0, jst)},
+		{"年末の深夜", time.Date(2024, 12, 31, 23, 59, 59, 0, jst), time.Date(2024, 12, 31, 0, 0, 0, 0, jst), time.Date(2025, 1, 1, 0, 0, 0, 0, jst)},
+		{"UTCでは前年", time.Date(2024, 12, 31, 15, 30, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, jst), time.Date(2025, 1, 2, 0, 0, 0, 0, jst)},
+		{"31日の月末", time.Date(2024, 1, 31, 10, 0, 0, 0, jst), time.Date(2024, 1, 31, 0, 0, 0, 0, jst), time.Date(2024, 2, 1, 0, 0, 0, 0, jst)},
+		{"30日の月末", time.Date(2024, 4, 30, 10, 0, 0, 0, jst), time.Date(2024, 4, 30, 0, 0, 0, 0, jst), time.Date(2024, 5, 1, 0, 0, 0, 0, jst)},
+		{"うるう年の2/28", time.Date(2024, 2, 28, 10, 0, 0, 0, jst), time.Date(2024, 2, 28, 0, 0, 0, 0, jst), time.Date(2024, 2, 29, 0, 0, 0, 0, jst)},
+		{"うるう日", time.Date(2024, 2, 29, 10, 0, 0, 0, jst), time.Date(2024, 2, 29, 0, 0, 0, 0, jst), time.Date(2024, 3, 1, 0, 0, 0, 0, jst)},
+		{"平年の2/28", time.Date(2023, 2, 28, 10, 0, 0, 0, jst), time.Date(2023, 2, 28, 0, 0, 0, 0, jst), time.Date(2023, 3, 1, 0, 0, 0, 0, jst)},
+		{"100年単位の平年", time.Date(2100, 2, 28, 10, 0, 0, 0, jst), time.Date(2100, 2, 28, 0, 0, 0, 0, jst), time.Date(2100, 3, 1, 0, 0, 0, 0, jst)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCalendarRepository)
+			mockNotifier := new(MockNotifier)
+			uc := NewNotifyScheduleUseCase(mockRepo, mockNotifier, clock.Fixed(tt.now))
+
+			mockRepo.On("GetEvents", mock.Anything, tt.today).Return([]domain.Event{}, nil)
+			mockRepo.On("GetEvents", mock.Anything, tt.tomorrow).Return([]domain.Event{}, nil)
+
+			_, err := uc.Run(context.Background())
+			require.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/dates"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+// OutOfOfficeMute 本日が不在（休暇など）の日の通知の抑制方法
+type OutOfOfficeMute string
+
+const (
+	// OutOfOfficeMuteOff 不在の日も通常どおり通知する
+	OutOfOfficeMuteOff OutOfOfficeMute = "off"
+	// OutOfOfficeMuteWork 本日の仕事の予定（家族の予定以外）を表示しない
+	OutOfOfficeMuteWork OutOfOfficeMute = "work"
+	// OutOfOfficeMuteAll 通知自体を送らない（デフォルト）
+	OutOfOfficeMuteAll OutOfOfficeMute = "all"
+)
+
+// ParseOutOfOfficeMute 文字列からOutOfOfficeMuteを解析する
+// 空文字の場合はOutOfOfficeMuteAllを返す
+func ParseOutOfOfficeMute(s string) (OutOfOfficeMute, error) {
+	switch m := OutOfOfficeMute(strings.ToLower(strings.TrimSpace(s))); m {
+	case "":
+		return OutOfOfficeMuteAll, nil
+	case OutOfOfficeMuteOff, OutOfOfficeMuteWork, OutOfOfficeMuteAll:
+		return m, nil
+	default:
+		return "", fmt.Errorf("不在の日の通知抑制の設定が不正です: %q (off, work, all のいずれか)", s)
+	}
+}
+
+// OutOfOffice 不在の日の判定と通知の抑制の設定
+type OutOfOffice struct {
+	Mute OutOfOfficeMute
+	// Keywords タイトルにこのキーワードを含む終日予定も不在とみなす（例: 休暇, 有給）
+	Keywords []string
+}
+
+// coversDay 本日の予定のうち、1日全体を覆う不在の予定があるかどうか
+// 半休など一部の時間帯だけの不在は対象外
+func (o OutOfOffice) coversDay(today time.Time, events []domain.Event) bool {
+	start, end := dates.StartOfDay(today), dates.AddDays(today, 1)
+	for _, event := range events {
+		if event.IsFamily || !o.isOutOfOffice(event) {
+			continue
+		}
+		if !event.StartTime.After(start) && !event.EndTime.Before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+// isOutOfOffice 予定が不在を表すかどうか
+func (o OutOfOffice) isOutOfOffice(event domain.Event) bool {
+	if event.IsOutOfOffice {
+		return true
+	}
+	if !event.IsAllDay {
+		return false
+	}
+	for _, keyword := range o.Keywords {
+		if keyword != "" && strings.Contains(event.Title, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// familyOnly 家族の予定のみを返す
+func familyOnly(events []domain.Event) []domain.Event {
+	var family []domain.Event
+	for _, event := range events {
+		if event.IsFamily {
+			family = append(family, event)
+		}
+	}
+	return family
+}
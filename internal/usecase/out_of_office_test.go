@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k-negishi/google-calendar-line-notifier/internal/clock"
+	"github.com/k-negishi/google-calendar-line-notifier/internal/domain"
+)
+
+func TestParseOutOfOfficeMute(t *testing.T) {
+	for input, want := range map[string]OutOfOfficeMute{"": OutOfOfficeMuteAll, "off": OutOfOfficeMuteOff, " Work ": OutOfOfficeMuteWork, "ALL": OutOfOfficeMuteAll} {
+		got, err := ParseOutOfOfficeMute(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+	_, err := ParseOutOfOfficeMute("quiet")
+	assert.Error(t, err)
+}
+
+func TestOutOfOffice_CoversDay(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	today := time.Date(2024, 1, 15, 0, 0, 0, 0, jst)
+	at := func(day, hour int) time.Time { return time.Date(2024, 1, day, hour, 0, 0, 0, jst) }
+	o := OutOfOffice{Keywords: []string{"休暇"}}
+
+	tests := []struct {
+		name  string
+		event domain.Event
+		want  bool
+	}{
+		{name: "不在の予定（終日）", event: domain.Event{IsOutOfOffice: true, StartTime: at(15, 0), EndTime: at(16, 0)}, want: true},
+		{name: "複数日にまたがる不在", event: domain.Event{IsOutOfOffice: true, StartTime: at(13, 0), EndTime: at(18, 0)}, want: true},
+		{name: "午後休", event: domain.Event{IsOutOfOffice: true, StartTime: at(15, 13), EndTime: at(16, 0)}, want: false},
+		{name: "キーワードに一致する終日予定", event: domain.Event{Title: "夏季休暇", IsAllDay: true, StartTime: at(15, 0), EndTime: at(16, 0)}, want: true},
+		{name: "キーワードに一致する時間指定の予定", event: domain.Event{Title: "休暇申請の締め切り", StartTime: at(15, 0), EndTime: at(16, 0)}, want: false},
+		{name: "家族の不在は対象外", event: domain.Event{IsOutOfOffice: true, IsFamily: true, StartTime: at(15, 0), EndTime: at(16, 0)}, want: false},
+		{name: "通常の終日予定", event: domain.Event{Title: "出張", IsAllDay: true, StartTime: at(15, 0), EndTime: at(16, 0)}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, o.coversDay(today, []domain.Event{tt.event}))
+		})
+	}
+}
+
+func TestExecute_OutOfOfficeMute(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 1, 15, 7, 0, 0, 0, jst)
+	today := time.Date(2024, 1, 15, 0, 0, 0, 0, jst)
+	tomorrow := time.Date(2024, 1, 16, 0, 0, 0, 0, jst)
+
+	vacation := domain.Event{Title: "休暇", IsOutOfOffice: true, IsAllDay: true, StartTime: today, EndTime: tomorrow}
+	standup := domain.Event{Title: "朝会", StartTime: today.Add(9 * time.Hour), EndTime: today.Add(10 * time.Hour)}
+	pickup := domain.Event{Title: "保育園お迎え", StartTime: today.Add(17 * time.Hour), EndTime: today.Add(18 * time.Hour), IsFamily: true}
+	review := domain.Event{Title: "レビュー", StartTime: tomorrow.Add(10 * time.Hour), EndTime: tomorrow.Add(11 * time.Hour)}
+
+	t.Run("all", func(t *testing.T) {
+		mockRepo := new(MockCalendarRepository)
+		mockNotifier := new(MockNotifier)
+		uc := NewNotifyScheduleUseCase(mockRepo, mockNotifier, clock.Fixed(now))
+		uc.SetOutOfOffice(OutOfOffice{Mute: OutOfOfficeMuteAll})
+		mockRepo.On("GetEvents", mock.Anything, today).Return([]domain.Event{vacation, standup}, nil)
+		mockRepo.On("GetEvents", mock.Anything, tomorrow).Return([]domain.Event{review}, nil)
+
+		reason, err := uc.Execute(context.Background(), today, tomorrow)
+		require.NoError(t, err)
+		assert.Equal(t, SkipReasonOutOfOffice, reason)
+		mockNotifier.AssertNotCalled(t, "SendScheduleNotification")
+	})
+
+	t.Run("work", func(t *testing.T) {
+		mockRepo := new(MockCalendarRepository)
+		mockNotifier := new(MockNotifier)
+		uc := NewNotifyScheduleUseCase(mockRepo, mockNotifier, clock.Fixed(now))
+		uc.SetOutOfOffice(OutOfOffice{Mute: OutOfOfficeMuteWork})
+		mockRepo.On("GetEvents", mock.Anything, today).Return([]domain.Event{vacation, standup, pickup}, nil)
+		mockRepo.On("GetEvents", mock.Anything, tomorrow).Return([]domain.Event{review}, nil)
+		mockNotifier.On("SendScheduleNotification", mock.Anything, domain.NewScheduleReport(now, today, tomorrow, []domain.Event{pickup}, []domain.Event{review})).Return(nil)
+
+		reason, err := uc.Execute(context.Background(), today, tomorrow)
+		require.NoError(t, err)
+		assert.Empty(t, reason)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("off", func(t *testing.T) {
+		mockRepo := new(MockCalendarRepository)
+		mockNotifier := new(MockNotifier)
+		uc := NewNotifyScheduleUseCase(mockRepo, mockNotifier, clock.Fixed(now))
+		uc.SetOutOfOffice(OutOfOffice{Mute: OutOfOfficeMuteOff})
+		mockRepo.On("GetEvents", mock.Anything, today).Return([]domain.Event{vacation, standup}, nil)
+		mockRepo.On("GetEvents", mock.Anything, tomorrow).Return([]domain.Event{review}, nil)
+		mockNotifier.On("SendScheduleNotification", mock.Anything, domain.NewScheduleReport(now, today, tomorrow, []domain.Event{vacation, standup}, []domain.Event{review})).Return(nil)
+
+		_, err := uc.Execute(context.Background(), today, tomorrow)
+		require.NoError(t, err)
+		mockNotifier.AssertExpectations(t)
+	})
+}